@@ -0,0 +1,118 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrDeadlineUnachievable is returned for electrons which are shed on
+// admission because their timeout cannot be met by the atomizer
+var ErrDeadlineUnachievable = errors.New("electron deadline unachievable")
+
+// LatencyEstimator estimates the time it takes for an electron for the atom
+// to complete when it is admitted behind the number of queued electrons
+// for the atom which have not finished processing
+type LatencyEstimator func(atomID string, queued int) time.Duration
+
+// WithDeadlineAdmission sheds electrons on admission whose Timeout is
+// shorter than the estimated latency of the electron, rejecting them with
+// ErrDeadlineUnachievable rather than accepting work which cannot meet its
+// deadline. Electrons without a timeout are always admitted. The queued
+// electrons only count towards the latency when the timeout starts from
+// submission (see WithTimeoutSemantics). When the
+// estimator is nil the latency is estimated as the average processing time
+// of the atom for each queued electron and the electron itself, divided
+// across the replicas of the atom and the electrons each replica executes
+// concurrently (see WithReplicas and WithMaxConcurrency).
+func WithDeadlineAdmission(estimator LatencyEstimator) Option {
+	return func(a *atomizer) error {
+		if estimator == nil {
+			estimator = a.estimate
+		}
+
+		a.estimator = estimator
+		return nil
+	}
+}
+
+// estimate is the default latency estimator using the average
+// processing time of the atom
+func (a *atomizer) estimate(atomID string, queued int) time.Duration {
+	a.statsMu.RLock()
+	stats, ok := a.stats[atomID]
+	var average time.Duration
+	if ok && stats.Executions > 0 {
+		average = stats.Elapsed / time.Duration(stats.Executions)
+	}
+	a.statsMu.RUnlock()
+
+	if average == 0 {
+		return 0
+	}
+
+	// The queued electrons and the electron itself are processed
+	// in waves of the parallelism of the atom
+	parallel := a.parallelism(atomID)
+
+	return average * time.Duration((queued+parallel)/parallel)
+}
+
+// parallelism returns the number of electrons for the atom which are
+// processed at once across the replicas of the atom
+func (a *atomizer) parallelism(atomID string) int {
+	a.atomsMu.RLock()
+	defer a.atomsMu.RUnlock()
+
+	reps, ok := a.atoms[atomID]
+	if !ok || reps.size() == 0 {
+		return 1
+	}
+
+	return reps.size() * a.workers(reps.atom)
+}
+
+// unachievable indicates if the electron cannot complete within its
+// timeout given the estimated latency of its atom
+func (a *atomizer) unachievable(e *Electron) (time.Duration, bool) {
+	timeout, ok := e.timeout()
+	if a.estimator == nil || !ok {
+		return 0, false
+	}
+
+	// The time queued only counts towards the timeout when it starts
+	// from submission, otherwise only a single execution must fit
+	var queued int
+	if a.timeoutFrom == TimeoutFromSubmission {
+		queued = a.inflight.queued(e.AtomID)
+	}
+
+	latency := a.estimator(e.AtomID, queued)
+
+	return latency, latency > timeout
+}
+
+// shed rejects the instance when its deadline is unachievable
+func (a *atomizer) shed(inst instance) bool {
+	latency, shed := a.unachievable(inst.electron)
+	if !shed {
+		return false
+	}
+
+	a.reject(inst, StageAdmission, &Error{
+		Event: &Event{
+			Message: "electron timeout of " + inst.electron.Timeout.String() +
+				" is less than the estimated latency of " + latency.String(),
+			ElectronID:  inst.electron.ID,
+			AtomID:      inst.electron.AtomID,
+			ConductorID: ID(inst.conductor),
+		},
+		Internal: ErrDeadlineUnachievable,
+	})
+
+	return true
+}
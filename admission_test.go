@@ -0,0 +1,247 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAtomizer_DeadlineAdmission(t *testing.T) {
+	// The gate is shared by the subtests since the electrons queued
+	// by a subtest are still processed after the subtest finishes
+	gaterelease = make(chan struct{})
+	t.Cleanup(func() {
+		close(gaterelease)
+	})
+
+	t.Run("submission", func(t *testing.T) {
+		d := time.Second * 30
+		ctx, cancel := _ctxT(context.TODO(), &d)
+		defer cancel()
+
+		reset(ctx, t)
+		t.Cleanup(func() {
+			reset(context.TODO(), t)
+		})
+
+		atomID := ID(gateatom{})
+		rec, a := recHarness(
+			ctx,
+			t,
+			// Queue the backlog rather than blocking the conductor
+			WithConductorPriority("queue", 0),
+			WithTimeoutSemantics(TimeoutFromSubmission),
+			WithDeadlineAdmission(func(atomID string, queued int) time.Duration {
+				return time.Duration(queued+1) * time.Second
+			}),
+			&gateatom{},
+		)
+		rejections := a.Rejections(10)
+
+		eventually(t, time.Second, func() bool {
+			return a.route(newElectron(atomID, nil)) != nil
+		})
+
+		for i := 0; i < 10; i++ {
+			if _, err := rec.Send(ctx, newElectron(atomID, nil)); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		eventually(t, time.Second, func() bool {
+			return a.inflight.queued(atomID) == 10
+		})
+
+		tight := time.Second * 5
+		e := newElectron(atomID, nil)
+		e.Timeout = &tight
+
+		if _, err := rec.Send(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+
+		p := rec.next(ctx, t)
+		if p.ElectronID != e.ID || !errors.Is(p.Error, ErrDeadlineUnachievable) {
+			t.Fatalf("expected %s to be shed, got %+v", e.ID, p)
+		}
+
+		<-rejections
+
+		loose := time.Minute
+		e = newElectron(atomID, nil)
+		e.Timeout = &loose
+
+		if _, err := rec.Send(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+
+		eventually(t, time.Second, func() bool {
+			return a.inflight.queued(atomID) == 11
+		})
+
+		select {
+		case r := <-rejections:
+			t.Fatalf("unexpected rejection of %s", r.Electron.ID)
+		default:
+		}
+
+		err := a.SubmitWithCallback(ctx, &Electron{
+			SenderID: "s",
+			ID:       "submitted",
+			AtomID:   atomID,
+			Timeout:  &tight,
+		}, func(Properties, error) {})
+		if !errors.Is(err, ErrDeadlineUnachievable) {
+			t.Fatalf("expected submission to be shed, got %v", err)
+		}
+	})
+
+	t.Run("execution", func(t *testing.T) {
+		d := time.Second * 30
+		ctx, cancel := _ctxT(context.TODO(), &d)
+		defer cancel()
+
+		reset(ctx, t)
+		t.Cleanup(func() {
+			reset(context.TODO(), t)
+		})
+
+		atomID := ID(gateatom{})
+		rec, a := recHarness(
+			ctx,
+			t,
+			WithConductorPriority("queue", 0),
+			WithDeadlineAdmission(func(atomID string, queued int) time.Duration {
+				return time.Duration(queued+1) * time.Second
+			}),
+			&gateatom{},
+		)
+		rejections := a.Rejections(10)
+
+		eventually(t, time.Second, func() bool {
+			return a.route(newElectron(atomID, nil)) != nil
+		})
+
+		for i := 0; i < 10; i++ {
+			if _, err := rec.Send(ctx, newElectron(atomID, nil)); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		eventually(t, time.Second, func() bool {
+			return a.inflight.queued(atomID) == 10
+		})
+
+		// The timeout starts at execution so the queued
+		// electrons do not count towards the latency
+		fits := time.Second * 5
+		e := newElectron(atomID, nil)
+		e.Timeout = &fits
+
+		if _, err := rec.Send(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+
+		eventually(t, time.Second, func() bool {
+			return a.inflight.queued(atomID) == 11
+		})
+
+		select {
+		case r := <-rejections:
+			t.Fatalf("unexpected rejection of %s", r.Electron.ID)
+		default:
+		}
+
+		tight := time.Millisecond * 500
+		e = newElectron(atomID, nil)
+		e.Timeout = &tight
+
+		if _, err := rec.Send(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+
+		p := rec.next(ctx, t)
+		if p.ElectronID != e.ID || !errors.Is(p.Error, ErrDeadlineUnachievable) {
+			t.Fatalf("expected %s to be shed, got %+v", e.ID, p)
+		}
+	})
+}
+
+func TestAtomizer_estimate(t *testing.T) {
+	a := &atomizer{stats: map[string]*AtomStats{
+		"atom": {Executions: 4, Elapsed: time.Second * 4},
+	}}
+
+	if got := a.estimate("atom", 2); got != time.Second*3 {
+		t.Fatalf("expected 3s, got %s", got)
+	}
+
+	if got := a.estimate("other", 2); got != 0 {
+		t.Fatalf("expected no estimate, got %s", got)
+	}
+}
+
+func TestAtomizer_estimate_Parallelism(t *testing.T) {
+	a := &atomizer{
+		stats: map[string]*AtomStats{
+			"atom": {Executions: 4, Elapsed: time.Second * 4},
+		},
+		atoms: map[string]*replicas{
+			"atom": {
+				atom: &noopatom{},
+				channels: map[string]chan<- instance{
+					"atom#0": nil,
+					"atom#1": nil,
+				},
+			},
+		},
+		maxConcurrency: 2,
+	}
+
+	tests := map[int]time.Duration{
+		0: time.Second,
+		3: time.Second,
+		4: time.Second * 2,
+		7: time.Second * 2,
+		8: time.Second * 3,
+	}
+
+	for queued, expected := range tests {
+		if got := a.estimate("atom", queued); got != expected {
+			t.Fatalf("expected %s for %v queued, got %s", expected, queued, got)
+		}
+	}
+}
+
+func TestInflight_queued(t *testing.T) {
+	f := &inflight{}
+
+	first := &Electron{ID: "1", AtomID: "a"}
+	second := &Electron{ID: "2", AtomID: "a"}
+	other := &Electron{ID: "3", AtomID: "b"}
+
+	f.add(first, nil)
+	f.add(first, nil)
+	f.add(second, nil)
+	f.add(other, nil)
+
+	if f.queued("a") != 2 || f.queued("b") != 1 {
+		t.Fatalf("expected 2 and 1 queued, got %v and %v", f.queued("a"), f.queued("b"))
+	}
+
+	// The depth is counted by the atom the electron was tracked for
+	first.AtomID = "b"
+	f.done(first)
+	f.done(first)
+
+	if f.queued("a") != 1 || f.queued("b") != 1 {
+		t.Fatalf("expected 1 and 1 queued, got %v and %v", f.queued("a"), f.queued("b"))
+	}
+
+	f.abandon()
+
+	if f.queued("a") != 0 || f.queued("b") != 0 {
+		t.Fatalf("expected nothing queued, got %v and %v", f.queued("a"), f.queued("b"))
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import (
+	"container/heap"
+	"time"
+)
+
+// WithPriorityAging raises the priority of an electron waiting in the
+// priority queue by one for every interval it waits so that low priority
+// electrons are eventually dispatched under sustained higher priority
+// load. A shorter interval ages electrons faster. Aging enables the
+// priority queue (see WithConductorPriority).
+func WithPriorityAging(interval time.Duration) Option {
+	return func(a *atomizer) error {
+		if interval <= 0 {
+			return simple("priority aging interval must be positive", nil)
+		}
+
+		if a.queue == nil {
+			a.queue = newPQueue(DefaultQueueSize)
+		}
+
+		a.queue.aging = interval
+		return nil
+	}
+}
+
+// take removes the next instance to dispatch from the queue, considering
+// the aged priority of each instance when aging is enabled. The queue
+// lock MUST be held and the queue MUST NOT be empty.
+func (q *pqueue) take() *queued {
+	if q.aging <= 0 {
+		next, _ := heap.Pop(&q.items).(*queued)
+		return next
+	}
+
+	// Aging changes the order of the instances as they
+	// wait so the next instance is found at dispatch
+	now := time.Now()
+	best := 0
+	for i := 1; i < len(q.items); i++ {
+		if ahead(
+			q.items[i],
+			q.items[best],
+			q.aged(q.items[i], now),
+			q.aged(q.items[best], now),
+		) {
+			best = i
+		}
+	}
+
+	next, _ := heap.Remove(&q.items, best).(*queued)
+	return next
+}
+
+// aged returns the priority of the queued instance raised by one
+// for every aging interval it has waited
+func (q *pqueue) aged(item *queued, now time.Time) int {
+	return item.priority + int(now.Sub(item.enqueued)/q.aging)
+}
@@ -0,0 +1,73 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func Test_pqueue_PriorityAging(t *testing.T) {
+	tests := map[string]struct {
+		aging      time.Duration
+		dispatched bool
+	}{
+		"aging":    {time.Millisecond * 5, true},
+		"no aging": {0, false},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := _ctx(context.TODO())
+			defer cancel()
+
+			q := newPQueue(DefaultQueueSize)
+			q.aging = test.aging
+
+			low := newElectron("low", nil)
+			err := q.push(ctx, instance{electron: low}, 0, DefaultTenantWeight)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Continuously push high priority electrons ahead of
+			// the low priority electron
+			dispatched := false
+			for i := 0; i < 100 && !dispatched; i++ {
+				high := newElectron("high", nil)
+				high.Priority = 3
+
+				err = q.push(ctx, instance{electron: high}, 0, DefaultTenantWeight)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				inst, ok := q.pop(ctx)
+				if !ok {
+					t.Fatal("expected instance")
+				}
+
+				dispatched = inst.electron == low
+				time.Sleep(time.Millisecond)
+			}
+
+			if dispatched != test.dispatched {
+				t.Fatalf("expected low priority dispatched %v", test.dispatched)
+			}
+		})
+	}
+}
+
+func TestWithPriorityAging(t *testing.T) {
+	a := &atomizer{}
+	if err := WithPriorityAging(time.Second)(a); err != nil {
+		t.Fatal(err)
+	}
+
+	if a.queue == nil || a.queue.aging != time.Second {
+		t.Fatal("expected priority queue with aging")
+	}
+
+	if err := WithPriorityAging(0)(&atomizer{}); err == nil {
+		t.Fatal("expected error")
+	}
+}
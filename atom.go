@@ -7,8 +7,18 @@ package engine
 
 import (
 	"context"
+	"errors"
+	"fmt"
 )
 
+// ErrPayloadTooLarge is returned when the payload of an electron exceeds
+// the maximum payload size declared by its atom
+var ErrPayloadTooLarge = errors.New("payload too large")
+
+// ErrMissingPayload is returned when an electron without a payload is sent
+// to an atom which requires one
+var ErrMissingPayload = errors.New("missing payload")
+
 // Atom is an atomic action with process method for the atomizer to execute
 // the Atom
 type Atom interface {
@@ -18,3 +28,72 @@ type Atom interface {
 		electron *Electron,
 	) ([]byte, error)
 }
+
+// PayloadLimiter is an optional interface for atoms which declare the
+// maximum size in bytes of the electron payloads they accept. Electrons
+// with larger payloads are rejected with ErrPayloadTooLarge without being
+// processed by the atom. A maximum less than 1 accepts any size.
+type PayloadLimiter interface {
+	MaxPayloadSize() int
+}
+
+// PayloadRequirer is an optional interface for atoms which declare whether
+// they require a payload. Electrons without a payload sent to an atom which
+// requires one are rejected with ErrMissingPayload without being processed
+// by the atom. Atoms which do not implement the interface are triggered by
+// electrons with or without a payload.
+type PayloadRequirer interface {
+	RequiresPayload() bool
+}
+
+// oversized rejects the instance if the payload of the electron exceeds
+// the maximum payload size declared by the atom
+func (a *atomizer) oversized(atom Atom, inst instance) bool {
+	limiter, ok := atom.(PayloadLimiter)
+	if !ok {
+		return false
+	}
+
+	limit := limiter.MaxPayloadSize()
+	if limit < 1 || len(inst.electron.Payload) <= limit {
+		return false
+	}
+
+	a.reject(inst, StageExecution, &Error{
+		Event: &Event{
+			Message: fmt.Sprintf(
+				"payload of %v bytes exceeds max of %v bytes",
+				len(inst.electron.Payload),
+				limit,
+			),
+			AtomID:      ID(atom),
+			ElectronID:  inst.electron.ID,
+			ConductorID: ID(inst.conductor),
+		},
+		Internal: ErrPayloadTooLarge,
+	})
+
+	return true
+}
+
+// missing rejects the instance if the electron has no payload and the
+// atom requires one
+func (a *atomizer) missing(atom Atom, inst instance) bool {
+	requirer, ok := atom.(PayloadRequirer)
+	if !ok || !requirer.RequiresPayload() ||
+		len(inst.electron.Payload) > 0 {
+		return false
+	}
+
+	a.reject(inst, StageExecution, &Error{
+		Event: &Event{
+			Message:     "atom requires a payload",
+			AtomID:      ID(atom),
+			ElectronID:  inst.electron.ID,
+			ConductorID: ID(inst.conductor),
+		},
+		Internal: ErrMissingPayload,
+	})
+
+	return true
+}
@@ -0,0 +1,155 @@
+package engine
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// limitedatom accepts payloads of up to 10 bytes
+type limitedatom struct{}
+
+func (*limitedatom) MaxPayloadSize() int { return 10 }
+
+func (*limitedatom) Process(
+	ctx context.Context,
+	conductor Conductor,
+	electron *Electron,
+) ([]byte, error) {
+	return nil, nil
+}
+
+func TestAtomizer_MaxPayloadSize(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	rec, a := recHarness(ctx, t, &limitedatom{}, &noopatom{})
+	errs := a.Errors(10)
+
+	eventually(t, time.Second, func() bool {
+		return a.route(newElectron(ID(limitedatom{}), nil)) != nil &&
+			a.route(newElectron(ID(noopatom{}), nil)) != nil
+	})
+
+	large := []byte(`"` + string(bytes.Repeat([]byte("a"), 20)) + `"`)
+	small := []byte(`"small"`)
+
+	tests := map[string]struct {
+		atomID  string
+		payload []byte
+		err     error
+	}{
+		"limited large":    {ID(limitedatom{}), large, ErrPayloadTooLarge},
+		"limited small":    {ID(limitedatom{}), small, nil},
+		"permissive large": {ID(noopatom{}), large, nil},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			e := newElectron(test.atomID, test.payload)
+			if _, err := rec.Send(ctx, e); err != nil {
+				t.Fatal(err)
+			}
+
+			p := rec.next(ctx, t)
+			if p.ElectronID != e.ID {
+				t.Fatalf("expected %s, got %s", e.ID, p.ElectronID)
+			}
+
+			if !errors.Is(p.Error, test.err) ||
+				(test.err == nil && p.Error != nil) {
+				t.Fatalf("expected error %v, got %v", test.err, p.Error)
+			}
+
+			for len(errs) > 0 {
+				<-errs
+			}
+		})
+	}
+}
+
+// payloadatom requires a payload
+type payloadatom struct{}
+
+func (*payloadatom) RequiresPayload() bool { return true }
+
+func (*payloadatom) Process(
+	ctx context.Context,
+	conductor Conductor,
+	electron *Electron,
+) ([]byte, error) {
+	return nil, nil
+}
+
+// triggeratom is triggered by electrons without a payload
+type triggeratom struct{}
+
+func (*triggeratom) RequiresPayload() bool { return false }
+
+func (*triggeratom) Process(
+	ctx context.Context,
+	conductor Conductor,
+	electron *Electron,
+) ([]byte, error) {
+	return nil, nil
+}
+
+func TestAtomizer_RequiresPayload(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	rec, a := recHarness(ctx, t, &payloadatom{}, &triggeratom{})
+	errs := a.Errors(10)
+
+	eventually(t, time.Second, func() bool {
+		return a.route(newElectron(ID(payloadatom{}), nil)) != nil &&
+			a.route(newElectron(ID(triggeratom{}), nil)) != nil
+	})
+
+	tests := map[string]struct {
+		atomID  string
+		payload []byte
+		err     error
+	}{
+		"required missing": {ID(payloadatom{}), nil, ErrMissingPayload},
+		"required present": {ID(payloadatom{}), []byte(`"a"`), nil},
+		"trigger missing":  {ID(triggeratom{}), nil, nil},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			e := newElectron(test.atomID, test.payload)
+			if _, err := rec.Send(ctx, e); err != nil {
+				t.Fatal(err)
+			}
+
+			p := rec.next(ctx, t)
+			if p.ElectronID != e.ID {
+				t.Fatalf("expected %s, got %s", e.ID, p.ElectronID)
+			}
+
+			if !errors.Is(p.Error, test.err) ||
+				(test.err == nil && p.Error != nil) {
+				t.Fatalf("expected error %v, got %v", test.err, p.Error)
+			}
+
+			for len(errs) > 0 {
+				<-errs
+			}
+		})
+	}
+}
@@ -8,7 +8,9 @@ package engine
 import (
 	"context"
 	"reflect"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"devnw.com/validator"
@@ -35,17 +37,258 @@ type atomizer struct {
 	// registered into the system while it's alive
 	registrations chan interface{}
 
+	// buffers are the buffer sizes of the channels
+	buffers buffers
+
 	// This sync.Map contains the channels for handling each of the
 	// bondings for the different atoms registered in the system
 	atomsMu sync.RWMutex
-	atoms   map[string]chan<- instance
+	atoms   map[string]*replicas
+
+	// routes is the immutable snapshot of the atoms used
+	// for lock-free routing when snapshotRoutes is set
+	routes         atomic.Value
+	snapshotRoutes bool
+
+	// disabled contains the atoms which were disabled by a
+	// control command, guarded by atomsMu
+	disabled map[string]*replicas
+
+	// quarantined contains the disabled atoms which exceeded
+	// their panic budget, guarded by atomsMu
+	quarantined map[string]struct{}
+
+	// budget quarantines atoms which panic too often
+	budget *panicBudget
+
+	// conductors contains the registered conductors
+	// keyed by the conductor ID
+	conductorsMu sync.RWMutex
+	conductors   map[string]Conductor
+
+	// conducting cancels the receive loop of each registered
+	// conductor, guarded by conductorsMu
+	conducting map[string]context.CancelFunc
+
+	// hardTimeout is the maximum lifetime of an atom instance
+	// before its electron is forcibly completed
+	hardTimeout time.Duration
+
+	// compression compresses the results of electrons
+	// when marshalled if configured
+	compression *resultCompression
+
+	// forwarding routes electrons for atoms hosted
+	// on other nodes when configured
+	forwarding *forwarding
+
+	// offloading re-publishes electrons received
+	// while the node is overloaded
+	offloading *offloading
+
+	// pumps are the receivers of the conductors when
+	// electrons are manually pumped, guarded by conductorsMu
+	pumps map[string]<-chan *Electron
+
+	// validators are applied to the received electrons
+	// after the built-in validation
+	validators []ElectronValidator
+
+	// health tracks the health checks of the conductors
+	health *healthChecks
+
+	// outbound middleware is applied to the properties
+	// of processed electrons before completion
+	outbound []OutboundMiddleware
+
+	// nodeID identifies the node in the events,
+	// errors and properties of the atomizer
+	nodeID string
+
+	// resources pauses distribution while
+	// the node is under resource pressure
+	resources *resources
+
+	// scheduler limits the concurrent executions
+	// of the atoms by their classification
+	scheduler *scheduler
+
+	// connState is invoked on conductor state transitions
+	connState func(conductorID string, state ConnState)
+
+	// spill buffers the events on disk while
+	// the events channel is full
+	spill *spill
+
+	// estimator estimates the latency of electrons for
+	// shedding electrons with unachievable deadlines
+	estimator LatencyEstimator
+
+	// fallbacks are the fallback atoms keyed by the atom ID
+	fallbacks map[string]fallback
+
+	// limits are the rate limits of the atoms
+	limits map[string]*limiter
+
+	// smoothers smooth the electrons received
+	// from the conductors
+	smoothers map[string]*limiter
+
+	// timeoutFrom determines when the timeout
+	// of the electrons starts
+	timeoutFrom TimeoutSemantics
+
+	// lifecycleTrace emits the state transitions of
+	// every electron instance as events
+	lifecycleTrace bool
+
+	// sampling limits the rate of emitted events by message
+	sampling []*sampling
+
+	// redactor rewrites events before they are emitted
+	redactor Redactor
+
+	// metrics receives the typed metrics of the atomizer
+	metrics Monitor
+
+	// hashResults includes the hash of the result
+	// in the completed properties
+	hashResults bool
+
+	// retries is the default retry policy of
+	// electrons which fail processing
+	retries *RetryPolicy
+
+	// isolateTenants processes the electrons of each
+	// tenant on dedicated processing loops which are
+	// stopped once idle for tenantIdle
+	isolateTenants bool
+	tenantIdle     time.Duration
+
+	// healing is the minimum interval between the heals
+	// of a conductor and the time each was last healed
+	healing healing
+
+	// routines counts the goroutines started by the
+	// atomizer for diagnostics
+	routines routines
+
+	// drainTimeout bounds the graceful deregistration of an atom
+	drainTimeout time.Duration
+
+	// queue orders the electrons waiting to be distributed
+	// by priority and tenant weight when configured
+	queue      *pqueue
+	priorities map[string]int
+	weights    map[string]int
+
+	// hooks are invoked around each atom execution
+	hooks *execHooks
+
+	// dedup rejects electrons which were received
+	// recently when configured
+	dedup *dedup
+
+	// signer signs the results of the electrons
+	// processed by this node when configured
+	signer *resultSigner
+
+	// inflight tracks the received electrons until they
+	// finish processing for draining on shutdown
+	inflight inflight
+
+	// maxAtoms and maxConductors cap the number of registrations
+	maxAtoms      int
+	maxConductors int
+
+	// replicaCounts is the number of replicas to register
+	// for an atom, keyed by the atom ID
+	replicaCounts map[string]int
+
+	// sharders select the replicas of the atoms which
+	// process the electrons, keyed by the atom ID
+	sharders map[string]Sharder
+
+	// autoConcurrency sizes the replicas of the atoms
+	// relative to the number of CPUs
+	autoConcurrency bool
+
+	// maxConcurrency is the default number of electrons each
+	// processing loop of an atom executes concurrently
+	maxConcurrency int
 
 	eventsMu sync.RWMutex
 	events   chan interface{}
 
+	// sinks are the registered event sinks which each
+	// receive the events independently of the events channel
+	sinksMu sync.RWMutex
+	sinks   []*sink
+
 	errorsMu sync.RWMutex
 	errors   chan error
 
+	rejectionsMu sync.RWMutex
+	rejections   chan RejectedElectron
+
+	statsMu sync.RWMutex
+	stats   map[string]*AtomStats
+	cstats  map[string]*ConductorStats
+
+	// rates are the rolling error rates of the atoms
+	// over the rate window, guarded by statsMu
+	rates      map[string]*errorRate
+	rateWindow time.Duration
+
+	// timings are the recent processing times of the atoms
+	// for timeout suggestions, guarded by statsMu
+	timings map[string]*timings
+	margin  time.Duration
+
+	// receiveLatency enables measuring the time electrons are
+	// available from the conductors before being picked up
+	receiveLatency bool
+
+	// memProfiling enables sampling of the runtime memory
+	// statistics around atom executions
+	memProfiling bool
+
+	// leakTimeout is the duration an instance can execute
+	// without a heartbeat before a leak warning is emitted
+	leakTimeout time.Duration
+
+	// results stores the properties of completed
+	// electrons when configured
+	results ResultStore
+
+	// deadletters stores the electrons which failed
+	// processing when configured
+	deadletters DeadLetterStore
+
+	// instances persists the in-flight instances
+	// for recovery when configured
+	instances InstanceStore
+
+	// retry configures the retries of completions which
+	// failed on the conductor
+	retry completionRetry
+
+	// batching accumulates the completions of
+	// batch completers when configured
+	batching *batcher
+
+	// arrivals sequences the received electrons
+	arrivals uint64
+
+	// groups holds the completions of grouped electrons
+	// until every member of the group has completed
+	groups groups
+
+	// elector and leader gate the consumption of electrons
+	// from the conductors on this instance holding leadership
+	elector Elector
+	leader  *gate
+
 	ctx    context.Context
 	cancel context.CancelFunc
 
@@ -58,12 +301,39 @@ type errFunc func() error
 // event is a helper function that indicates
 // if the events channel is nil
 func (a *atomizer) event(fn eventFunc) {
-	if a.events != nil {
-		select {
-		case <-a.ctx.Done():
-			return
-		case a.events <- fn():
+	if a.events == nil && !a.hasSinks() {
+		return
+	}
+
+	e := fn()
+	if !a.sampled(e) {
+		return
+	}
+
+	a.identify(e)
+	e = a.redact(e)
+
+	a.publish(e)
+
+	if a.events == nil {
+		return
+	}
+
+	if a.spill != nil {
+		err := a.spill.put(a.ctx, a.events, e)
+		if err != nil && a.ctx.Err() == nil {
+			a.err(func() error {
+				return simple("unable to spill event", err)
+			})
 		}
+
+		return
+	}
+
+	select {
+	case <-a.ctx.Done():
+		return
+	case a.events <- e:
 	}
 }
 
@@ -71,10 +341,14 @@ func (a *atomizer) event(fn eventFunc) {
 // if the events channel is nil
 func (a *atomizer) err(fn errFunc) {
 	if a.errors != nil {
+		err := fn()
+		a.identify(err)
+		err = a.redactErr(err)
+
 		select {
 		case <-a.ctx.Done():
 			return
-		case a.errors <- fn():
+		case a.errors <- err:
 		}
 	}
 }
@@ -94,7 +368,6 @@ func (a *atomizer) receive() {
 		return
 	}
 
-	// TODO: Self-heal with heartbeats
 	for {
 		select {
 		case <-a.ctx.Done():
@@ -122,30 +395,55 @@ func (a *atomizer) register(input interface{}) {
 		a.err(func() error {
 			return simple("invalid registration "+ID(input), nil)
 		})
+		return
 	}
 
 	switch v := input.(type) {
 	case Conductor:
 		err := a.receiveConductor(v)
-		if err == nil {
-			a.event(func() interface{} {
-				return &Event{
-					Message:     "conductor received",
-					ConductorID: ID(v),
-				}
+		if err != nil {
+			a.err(func() error {
+				return err
 			})
+			return
 		}
-	case Atom:
 
+		a.event(func() interface{} {
+			return &Event{
+				Message:     "conductor received",
+				ConductorID: ID(v),
+			}
+		})
+	case Atom:
 		err := a.receiveAtom(v)
-		if err == nil {
-			a.event(func() interface{} {
-				return &Event{
-					Message: "atom received",
-					AtomID:  ID(v),
-				}
+		if err != nil {
+			a.err(func() error {
+				return err
 			})
+			return
 		}
+
+		a.event(func() interface{} {
+			return &Event{
+				Message: "atom received",
+				AtomID:  ID(v),
+			}
+		})
+	case *Command:
+		err := a.command(v)
+		if err != nil {
+			a.err(func() error {
+				return err
+			})
+			return
+		}
+
+		a.event(func() interface{} {
+			return &Event{
+				Message: "atom " + string(v.Action) + "d",
+				AtomID:  v.AtomID,
+			}
+		})
 	default:
 		a.err(func() error {
 			return simple(
@@ -165,7 +463,59 @@ func (a *atomizer) receiveConductor(conductor Conductor) error {
 		}}
 	}
 
-	go a.conduct(a.ctx, conductor)
+	a.conductorsMu.Lock()
+	if a.inflight.isDraining() || a.inflight.isStopped() {
+		a.conductorsMu.Unlock()
+		return &Error{Event: &Event{
+			Message:     "atomizer shutting down",
+			ConductorID: ID(conductor),
+		}}
+	}
+
+	if err := a.conductorCapped(ID(conductor)); err != nil {
+		a.conductorsMu.Unlock()
+		return err
+	}
+
+	if a.conductors == nil {
+		a.conductors = make(map[string]Conductor)
+	}
+
+	if a.conducting == nil {
+		a.conducting = make(map[string]context.CancelFunc)
+	}
+
+	ctx, cancel := context.WithCancel(a.ctx)
+	a.conductors[ID(conductor)] = conductor
+	a.conducting[ID(conductor)] = cancel
+	a.conductorsMu.Unlock()
+
+	if a.pumps != nil {
+		receiver := conductor.Receive(ctx)
+		a.bounded(conductor, receiver)
+
+		a.conductorsMu.Lock()
+		a.pumps[ID(conductor)] = receiver
+		a.conductorsMu.Unlock()
+	} else {
+		a.routine(func() {
+			a.conduct(ctx, conductor)
+		})
+	}
+
+	var c Controller
+	if conductorAs(conductor, &c) {
+		a.routine(func() {
+			a.control(ctx, c)
+		})
+	}
+
+	var hb Heartbeater
+	if conductorAs(conductor, &hb) {
+		a.routine(func() {
+			a.pulse(ctx, conductor, hb)
+		})
+	}
 
 	return nil
 }
@@ -173,21 +523,38 @@ func (a *atomizer) receiveConductor(conductor Conductor) error {
 // conduct reads in from a specific electron channel of a conductor and drop
 // it onto the atomizer channel for electrons
 func (a *atomizer) conduct(ctx context.Context, conductor Conductor) {
-	// Self Heal - Re-place the conductor on the register channel for
-	// the atomizer to re-initialize so this stack can be
-	// garbage collected
-
-	// 	a.event(a.Register(conductor))
-	// }))
+	atomic.AddInt64(&a.routines.conducts, 1)
+	defer atomic.AddInt64(&a.routines.conducts, -1)
 
 	receiver := conductor.Receive(ctx)
+	a.bounded(conductor, receiver)
+
+	if a.receiveLatency {
+		receiver = a.stamp(ctx, ID(conductor), receiver)
+	}
+
+	a.transition(ID(conductor), ConnConnected)
 
 	// Read from the electron channel for a conductor and push onto
 	// the a electron channel for processing
 	for {
+		var paused <-chan struct{}
+		if a.leader != nil {
+			// Wait for leadership before consuming electrons
+			select {
+			case <-ctx.Done():
+				return
+			case <-a.leader.open():
+			}
+
+			paused = a.leader.closed()
+		}
+
 		select {
 		case <-ctx.Done():
 			return
+		case <-paused:
+			continue
 		case e, ok := <-receiver:
 			if !ok {
 				a.err(func() error {
@@ -197,66 +564,141 @@ func (a *atomizer) conduct(ctx context.Context, conductor Conductor) {
 					}}
 				})
 
+				// Self heal by re-registering the conductor
+				// when health checks re-register conductors
+				var pinger Pinger
+				if conductorAs(conductor, &pinger) &&
+					a.health != nil && a.health.reregister &&
+					ctx.Err() == nil {
+					a.reregister(ID(conductor), conductor)
+					return
+				}
+
+				a.transition(ID(conductor), ConnFailed)
 				return
 			}
 
-			if !validator.Valid(e) {
-				err := &Error{Event: &Event{
-					Message:     "invalid electron",
-					ElectronID:  e.ID,
-					ConductorID: ID(conductor),
-				}}
-
-				err.Internal = conductor.Complete(
-					ctx,
-					&Properties{
-						ElectronID: e.ID,
-						AtomID:     e.AtomID,
-						Start:      time.Now(),
-						End:        time.Now(),
-						Error:      err,
-						Result:     nil,
-					},
-				)
-
-				a.err(func() error {
-					return err
-				})
-
+			inst, ok := a.admit(ctx, conductor, e)
+			if !ok {
 				continue
 			}
 
-			a.event(func() interface{} {
-				return &Event{
-					Message:     "electron received",
-					ElectronID:  e.ID,
-					AtomID:      e.AtomID,
-					ConductorID: ID(conductor),
-				}
-			})
-
-			// Send the electron down the electrons
-			// channel to be processed
-			select {
-			case <-a.ctx.Done():
+			if !a.accept(ctx, inst) {
 				return
-			case a.electrons <- instance{
-				electron:  e,
-				conductor: conductor,
-			}:
-				a.event(func() interface{} {
-					return &Event{
-						Message:     "electron distributed",
-						ElectronID:  e.ID,
-						AtomID:      e.AtomID,
-						ConductorID: ID(conductor),
-					}
-				})
 			}
 		}
 	}
 }
 
+// accept queues an admitted instance for distribution, offloading it
+// when the node is overloaded, and returns false if the context closed
+// before the instance was queued
+func (a *atomizer) accept(ctx context.Context, inst instance) bool {
+	if a.offload(inst) {
+		return true
+	}
+
+	if !a.smooth(ctx, inst) {
+		return false
+	}
+
+	// Send the electron down the electrons
+	// channel to be processed
+	e := inst.electron
+	a.inflight.add(e, inst.conductor)
+	a.persist(&inst, InstanceQueued)
+	a.lifecycle(Queued, &inst)
+	if !a.enqueue(inst) {
+		a.inflight.done(e)
+		return false
+	}
+
+	a.event(func() interface{} {
+		return &Event{
+			Message:     "electron distributed",
+			ElectronID:  e.ID,
+			AtomID:      e.AtomID,
+			ConductorID: ID(inst.conductor),
+			SenderID:    e.SenderID,
+			TenantID:    e.TenantID,
+			Stage:       inst.timing.stage(),
+		}
+	})
+
+	return true
+}
+
+// admit validates an electron received from the conductor and returns
+// the instance for distribution, electrons which are not admitted are
+// completed with the failure
+func (a *atomizer) admit(
+	ctx context.Context,
+	conductor Conductor,
+	e *Electron,
+) (instance, bool) {
+	if !validator.Valid(e) {
+		err := &Error{Event: &Event{
+			Message:     "invalid electron",
+			ElectronID:  e.ID,
+			ConductorID: ID(conductor),
+		}}
+
+		err.Internal = conductor.Complete(
+			ctx,
+			&Properties{
+				ElectronID:    e.ID,
+				AtomID:        e.AtomID,
+				CorrelationID: e.CorrelationID,
+				NodeID:        a.nodeID,
+				Start:         time.Now(),
+				End:           time.Now(),
+				Error:         err,
+				Result:        nil,
+			},
+		)
+
+		a.err(func() error {
+			return err
+		})
+
+		return instance{}, false
+	}
+
+	inst := a.instance(conductor, e)
+	if a.invalid(inst) || a.stored(inst) || a.duplicate(inst) ||
+		a.reused(inst) || a.shed(inst) {
+		return instance{}, false
+	}
+
+	a.event(func() interface{} {
+		return &Event{
+			Message:     "electron received",
+			ElectronID:  e.ID,
+			AtomID:      e.AtomID,
+			ConductorID: ID(conductor),
+			SenderID:    e.SenderID,
+			TenantID:    e.TenantID,
+			Stage:       inst.timing.stage(),
+		}
+	})
+	a.lifecycle(Received, &inst)
+	a.monitorReceived(e)
+
+	return inst, true
+}
+
+// instance returns a new instance of the electron received
+// from the conductor
+func (a *atomizer) instance(conductor Conductor, e *Electron) instance {
+	return instance{
+		electron:  e,
+		conductor: conductor,
+		timing:    &timing{},
+		received:  time.Now(),
+		seq:       atomic.AddUint64(&a.arrivals, 1),
+	}
+}
+
 // receiveAtom setups a retrieval loop for the conductor being passed in
 func (a *atomizer) receiveAtom(atom Atom) error {
 	if !validator.Valid(atom) {
@@ -268,11 +710,38 @@ func (a *atomizer) receiveAtom(atom Atom) error {
 		}
 	}
 
+	a.atomsMu.RLock()
+	err := a.atomCapped(ID(atom))
+	a.atomsMu.RUnlock()
+
+	if err != nil {
+		return err
+	}
+
+	n := a.concurrency(ID(atom), atom)
+
+	// Start the processing loops for the atom before the registration
+	// is visible to distribute so that electrons are never pushed to
+	// an atom which does not have a running reader
+	reps := newReplicas(atom)
+	reps.sharder = a.sharders[ID(atom)]
+	for i := 0; i < n; i++ {
+		reps.add(a.split(atom))
+	}
+
 	// Register the atom into the atomizer for receiving electrons
 	a.atomsMu.Lock()
-	defer a.atomsMu.Unlock()
+	if err = a.atomCapped(ID(atom)); err != nil {
+		a.atomsMu.Unlock()
+		return err
+	}
+
+	a.atoms[ID(atom)] = reps
+	delete(a.disabled, ID(atom))
+	delete(a.quarantined, ID(atom))
+	a.rebuild()
+	a.atomsMu.Unlock()
 
-	a.atoms[ID(atom)] = a.split(atom)
 	a.event(func() interface{} {
 		return &Event{
 			Message: "registered electron channel",
@@ -283,10 +752,18 @@ func (a *atomizer) receiveAtom(atom Atom) error {
 	return nil
 }
 
+// split starts a processing loop for the atom and returns the channel
+// for pushing electrons to the loop once the loop is running
 func (a *atomizer) split(atom Atom) chan<- instance {
-	electrons := make(chan instance)
+	electrons := make(chan instance, a.buffers.atoms)
+	running := make(chan struct{})
+
+	a.routine(func() {
+		close(running)
+		a._split(atom, electrons)
+	})
 
-	go a._split(atom, electrons)
+	<-running
 
 	return electrons
 }
@@ -295,6 +772,13 @@ func (a *atomizer) _split(
 	atom Atom,
 	electrons <-chan instance,
 ) {
+	atomic.AddInt64(&a.routines.splits, 1)
+	defer atomic.AddInt64(&a.routines.splits, -1)
+
+	// Electrons still executing are drained before the loop exits
+	workers := newPool(a.workers(atom))
+	defer workers.wait()
+
 	// Read from the electron channel for a conductor and push
 	// onto the a electron channel for processing
 	for {
@@ -314,12 +798,21 @@ func (a *atomizer) _split(
 				return
 			}
 
+			// A barrier stops the loop once every electron
+			// pushed to the loop before it has been processed
+			if inst.barrier != nil {
+				workers.wait()
+				close(inst.barrier)
+				return
+			}
+
 			a.event(func() interface{} {
 				return &Event{
 					Message:     "new instance of electron",
 					ElectronID:  inst.electron.ID,
 					AtomID:      ID(atom),
 					ConductorID: ID(inst.conductor),
+					TenantID:    inst.electron.TenantID,
 				}
 			})
 
@@ -331,29 +824,65 @@ func (a *atomizer) _split(
 			// rather than on individually bonded
 			// instances
 
-			var outatom Atom
-			// Copy the state of the original registration to
-			// the new atom
-			if inst.electron.CopyState {
-				outatom, _ = deepcopy.Copy(atom).(Atom)
-			} else {
-				// Initialize a new copy of the atom
-				newAtom := reflect.New(
-					reflect.TypeOf(atom).Elem(),
-				)
-
-				// ok is not checked here because this should
-				// never fail since the originating data item
-				// is what created this
-				outatom, _ = newAtom.Interface().(Atom)
+			processed := workers.run(a.ctx, func() {
+				a.process(atom, inst)
+			})
+			if !processed {
+				return
 			}
-
-			a.exec(inst, outatom)
 		}
 	}
 }
 
+// process executes the instance on a new instance of the atom
+func (a *atomizer) process(atom Atom, inst instance) {
+	if a.oversized(atom, inst) || a.missing(atom, inst) {
+		return
+	}
+
+	if !a.throttle(ID(atom), inst) {
+		return
+	}
+
+	outatom, ok := instantiate(atom, inst.electron.CopyState)
+	if !ok {
+		// Never execute the registered atom directly since
+		// it is shared across every electron for the atom
+		a.reject(inst, StageExecution, &Error{
+			Event: &Event{
+				Message:     "unable to instantiate atom",
+				AtomID:      ID(atom),
+				ElectronID:  inst.electron.ID,
+				ConductorID: ID(inst.conductor),
+			},
+		})
+		return
+	}
+
+	a.exec(inst, outatom)
+}
+
+// instantiate creates the atom instance which processes a single electron,
+// either as a deep copy of the registered atom when the electron requests
+// its state or as a new zero value of the registered atom type
+func instantiate(atom Atom, copyState bool) (Atom, bool) {
+	if copyState {
+		out, ok := deepcopy.Copy(atom).(Atom)
+		return out, ok && out != nil
+	}
+
+	t := reflect.TypeOf(atom)
+	if t == nil || t.Kind() != reflect.Ptr {
+		return nil, false
+	}
+
+	out, ok := reflect.New(t.Elem()).Interface().(Atom)
+	return out, ok
+}
+
 func (a *atomizer) exec(inst instance, atom Atom) {
+	defer a.inflight.done(inst.electron)
+
 	// bond the new atom instantiation to the electron instance
 	if err := inst.bond(atom); err != nil {
 		a.err(func() error {
@@ -369,9 +898,163 @@ func (a *atomizer) exec(inst instance, atom Atom) {
 		return
 	}
 
+	// The electron is only completed once even when the instance
+	// is forcibly completed by the hard timeout
+	var completed int32
+	complete := func(ctx context.Context, p *Properties) error {
+		if !atomic.CompareAndSwapInt32(&completed, 0, 1) {
+			return nil
+		}
+
+		a.lifecycle(ProcessingEnd, &inst)
+		err := a.complete(ctx, &inst, p)
+		a.lifecycle(Completed, &inst)
+
+		return err
+	}
+
+	a.lifecycle(Bonded, &inst)
+
+	inst.profileMem = a.memProfiling
+	inst.retry = a.retryPolicy(inst.electron)
+	inst.completer = complete
+
+	ctx, cancel := context.WithCancel(a.ctx)
+	defer cancel()
+
+	// Electrons abandoned by a shutdown are cancelled
+	// and completed with ErrShutdown
+	abort := func() {
+		cancel()
+		a.abort(&inst, ID(atom), complete)
+	}
+
+	if !a.inflight.bond(inst.electron, abort) {
+		// The electron was migrated by a snapshot
+		// or abandoned by a shutdown
+		return
+	}
+	a.persist(&inst, InstanceBonded)
+
+	inst.partials = &partials{}
+	a.streamPartials(ctx, &inst)
+
+	var expired chan struct{}
+	if a.hardTimeout > 0 {
+		expired = make(chan struct{})
+		start := time.Now()
+		t := time.AfterFunc(a.hardTimeout, func() {
+			a.expire(&inst, ID(atom), start, cancel, complete)
+			close(expired)
+		})
+		defer t.Stop()
+	}
+
+	ctx = withConductor(ctx, inst.conductor)
+	ctx = withParent(ctx, a, inst.electron)
+	ctx = withPartials(ctx, inst.partials)
+	ctx = withFlags(ctx, inst.electron)
+
+	inst.results = &resultWriter{
+		ctx:       ctx,
+		conductor: inst.conductor,
+		electron:  inst.electron,
+	}
+	ctx = withResult(ctx, inst.results)
+	ctx, l := alive(ctx, &inst)
+	if a.leakTimeout > 0 {
+		done := make(chan struct{})
+		defer close(done)
+
+		a.routine(func() {
+			a.watch(done, ID(atom), l)
+		})
+	}
+
+	release, scheduled := a.schedule(ctx, atom)
+	if !scheduled {
+		return
+	}
+	defer release()
+
 	// Execute the instance after it's been
 	// picked up for monitoring
-	err := inst.execute(a.ctx)
+	a.hookStart(&inst)
+	a.lifecycle(ProcessingStart, &inst)
+	a.deadline(&inst)
+	abandoned, err := a.sandbox(ctx, &inst, ID(atom), expired)
+	if abandoned {
+		// The instance still belongs to the leaked
+		// goroutine so it must not be touched here
+		a.record(ID(atom), nil, err)
+		a.monitorCompleted(ID(atom), nil, err)
+		return
+	}
+	defer a.hookEnd(&inst)
+
+	if inst.panicked {
+		a.panicked(ID(atom))
+	}
+
+	a.event(func() interface{} {
+		return &Event{
+			Message:     "atom execution complete",
+			ElectronID:  inst.electron.ID,
+			AtomID:      ID(atom),
+			ConductorID: ID(inst.conductor),
+			TenantID:    inst.electron.TenantID,
+			Stage:       inst.timing.stage(),
+		}
+	})
+
+	if inst.properties != nil && inst.properties.Status == StatusTimedOut {
+		timedout := &Error{
+			Event: &Event{
+				Message:     "electron timed out",
+				ElectronID:  inst.electron.ID,
+				AtomID:      ID(atom),
+				ConductorID: ID(inst.conductor),
+			},
+			Internal: inst.properties.Error,
+		}
+
+		a.err(func() error {
+			return timedout
+		})
+	}
+
+	if inst.properties != nil && inst.properties.Empty {
+		a.event(func() interface{} {
+			return &Event{
+				Message:     "empty result",
+				ElectronID:  inst.electron.ID,
+				AtomID:      ID(atom),
+				ConductorID: ID(inst.conductor),
+			}
+		})
+	}
+
+	// The instance completes itself unless the atom panicked, so any
+	// other error is the failure to complete the properties of the
+	// atom which is not a failure of the atom itself
+	if err != nil && !inst.panicked {
+		failed := err
+		err = nil
+
+		defer a.deadletter(&inst, failed)
+		defer a.err(func() error {
+			return failed
+		})
+	} else {
+		defer a.deadletter(&inst, err)
+	}
+
+	defer a.record(ID(atom), inst.properties, err)
+	defer a.monitorCompleted(ID(atom), inst.properties, err)
+	if inst.electron.ExpectsReply() {
+		defer a.storeResult(inst.properties)
+	}
+
 	if err != nil {
 		defer a.err(func() error {
 			return &Error{
@@ -399,10 +1082,12 @@ func (a *atomizer) exec(inst instance, atom Atom) {
 		}
 
 		if inst.conductor != nil {
-			completion := inst.conductor.Complete(a.ctx, inst.properties)
-			a.err(func() error {
-				return completion
-			})
+			completion := complete(a.ctx, inst.properties)
+			if completion != nil {
+				a.err(func() error {
+					return completion
+				})
+			}
 		}
 	}
 }
@@ -425,24 +1110,24 @@ func (a *atomizer) distribute() {
 				return
 			}
 
-			a.atomsMu.RLock()
-			achan, ok := a.atoms[inst.electron.AtomID]
-			a.atomsMu.RUnlock()
+			if inst.barrier != nil {
+				close(inst.barrier)
+				continue
+			}
 
-			if !ok {
-				// TODO: figure out what to do here
-				// since the atom doesn't exist in
-				// the registry
+			// Electrons migrated by a snapshot are dropped
+			if a.inflight.isMigrated(inst.electron) {
+				a.inflight.done(inst.electron)
+				continue
+			}
 
-				a.err(func() error {
-					return &Error{
-						Event: &Event{
-							Message:    "not registered",
-							AtomID:     inst.electron.AtomID,
-							ElectronID: inst.electron.ID,
-						},
-					}
-				})
+			// Pause while the node is under resource pressure
+			if !a.admitting() {
+				return
+			}
+
+			achan := a.dispatch(inst)
+			if achan == nil {
 				continue
 			}
 
@@ -452,22 +1137,90 @@ func (a *atomizer) distribute() {
 					ElectronID:  inst.electron.ID,
 					AtomID:      inst.electron.AtomID,
 					ConductorID: ID(inst.conductor),
+					Stage:       inst.timing.stage(),
 				}
 			})
 
-			select {
-			case <-a.ctx.Done():
+			if !a.handoff(inst, achan) {
 				return
-			case achan <- inst:
-				a.event(func() interface{} {
-					return &Event{
-						Message:     "pushed electron to atom",
-						ElectronID:  inst.electron.ID,
-						AtomID:      inst.electron.AtomID,
-						ConductorID: ID(inst.conductor),
-					}
-				})
 			}
+
+			a.event(func() interface{} {
+				return &Event{
+					Message:     "pushed electron to atom",
+					ElectronID:  inst.electron.ID,
+					AtomID:      inst.electron.AtomID,
+					ConductorID: ID(inst.conductor),
+				}
+			})
+			a.monitorDepth(inst.electron.AtomID)
+		}
+	}
+}
+
+// dispatch routes the instance to the channel of the atom replica which
+// processes it. Instances which cannot be processed locally are handled
+// here and nil is returned.
+func (a *atomizer) dispatch(inst instance) chan<- instance {
+	a.resolve(inst.electron)
+
+	achan := a.route(inst.electron)
+	if achan == nil && inst.electron.ForceAtomKey != "" {
+		a.reject(inst, StageDistribution, &Error{
+			Event: &Event{
+				Message: "forced atom key " +
+					inst.electron.ForceAtomKey +
+					" not registered",
+				AtomID:      inst.electron.AtomID,
+				ElectronID:  inst.electron.ID,
+				ConductorID: ID(inst.conductor),
+			},
+		})
+		return nil
+	}
+
+	if achan == nil && a.isDisabled(inst.electron.AtomID) {
+		err := &Error{
+			Event: &Event{
+				Message:     "atom disabled",
+				AtomID:      inst.electron.AtomID,
+				ElectronID:  inst.electron.ID,
+				ConductorID: ID(inst.conductor),
+			},
+		}
+
+		if a.isQuarantined(inst.electron.AtomID) {
+			err.Internal = ErrAtomQuarantined
+			a.deadletter(&inst, err)
 		}
+
+		a.reject(inst, StageDistribution, err)
+		return nil
 	}
+
+	if achan == nil && a.forward(inst) {
+		return nil
+	}
+
+	if achan == nil {
+		err := &Error{
+			Event: &Event{
+				Message:    "not registered",
+				AtomID:     inst.electron.AtomID,
+				ElectronID: inst.electron.ID,
+			},
+		}
+
+		if inst.electron.AtomID == "" {
+			err.Event.Message = "no atom capable of " +
+				strings.Join(inst.electron.Requires, ", ")
+		}
+
+		// The sender is completed with the error rather than
+		// waiting on an electron which will never be processed
+		a.reject(inst, StageDistribution, err)
+		return nil
+	}
+
+	return achan
 }
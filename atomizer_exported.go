@@ -7,17 +7,44 @@ package engine
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"devnw.com/validator"
 )
 
+// ErrAlreadyRunning is returned by Exec when the atomizer has
+// already been started
+var ErrAlreadyRunning = errors.New("atomizer already running")
+
 // Atomizer interface implementation
 type Atomizer interface {
 	Exec() error
 	Register(value ...interface{}) error
 	Events(buffer int) <-chan interface{}
+	AddEventSink(sink EventSink, buffer int) error
 	Errors(buffer int) <-chan error
+	Rejections(buffer int) <-chan RejectedElectron
+	Stats() map[string]AtomStats
+	SuggestTimeout(atomID string) time.Duration
+	ConductorStats() map[string]ConductorStats
+	Health() map[string]ConductorHealth
+	Diagnostics() Diagnostics
+	Inspect(fn func(Registration) bool)
+	Reprocess(ctx context.Context, filter DeadLetterFilter) (int, error)
+	Recover(ctx context.Context) (int, error)
+	Shutdown(ctx context.Context) (ShutdownReport, error)
+	Snapshot() ([]Electron, error)
+	Swap(atomID string, atom Atom) error
+	Scale(atomID string, n int) error
+	Deregister(id string, policy DeregisterPolicy) error
+	SubmitWithCallback(
+		ctx context.Context,
+		e *Electron,
+		callback func(Properties, error),
+	) error
+	Pump(ctx context.Context) (processed int, err error)
 	Wait()
 
 	// private methods enforce only this
@@ -30,35 +57,64 @@ type Atomizer interface {
 //
 // NOTE: Registrations can be added through this method and OVERRIDE any
 // existing registrations of the same Atom or Conductor.
+//
+// Options (see Option) can be passed alongside the registrations to
+// configure the optional behavior of the atomizer.
 func Atomize(
 	ctx context.Context,
 	registrations ...interface{},
 ) (Atomizer, error) {
-	err := Register(registrations...)
+	ctx, cancel := _ctx(ctx)
+
+	a := &atomizer{
+		ctx:        ctx,
+		cancel:     cancel,
+		atoms:      make(map[string]*replicas),
+		conductors: make(map[string]Conductor),
+		stats:      make(map[string]*AtomStats),
+		nodeID:     hostname(),
+	}
+
+	registrations, err := a.options(registrations...)
 	if err != nil {
+		cancel()
 		return nil, err
 	}
 
-	ctx, cancel := _ctx(ctx)
+	// The channels are created once the options have sized them
+	a.electrons = make(chan instance, a.buffers.electrons)
+	a.bonded = make(chan instance, a.buffers.bonded)
+	a.registrations = make(chan interface{}, a.buffers.registrations)
 
-	return &atomizer{
-		ctx:           ctx,
-		cancel:        cancel,
-		electrons:     make(chan instance),
-		bonded:        make(chan instance),
-		registrations: make(chan interface{}),
-		atoms:         make(map[string]chan<- instance),
-	}, nil
+	err = Register(registrations...)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	return a, nil
 }
 
 func (*atomizer) isAtomizer() {}
 
 // Exec kicks off the processing of the atomizer by pulling in the
 // pre-registrations through init calls on imported libraries and
-// starts up the receivers for atoms and conductors
+// starts up the receivers for atoms and conductors. Exec only starts
+// the atomizer once, subsequent calls return ErrAlreadyRunning.
 func (a *atomizer) Exec() (err error) {
+	// An atomizer is bound to the context it was created with
+	// and cannot be restarted once closed, a new atomizer must
+	// be created with Atomize using a new context instead
+	if a.ctx.Err() != nil {
+		return simple("atomizer closed", a.ctx.Err())
+	}
+
+	err = simple("atomizer already running", ErrAlreadyRunning)
+
 	// Execute on the atomizer should only ever be run once
 	a.execSyncOnce.Do(func() {
+		err = nil
+
 		defer a.event(func() interface{} {
 			return "pulling conductor and atom registrations"
 		})
@@ -69,12 +125,38 @@ func (a *atomizer) Exec() (err error) {
 		}
 
 		// Start up the receivers
-		go a.receive()
+		a.routine(a.receive)
 
 		// Setup the distribution loop for incoming electrons
 		// so that they can be properly fanned out to the
 		// atom receivers
-		go a.distribute()
+		a.routine(a.distribute)
+
+		// Dispatch the queued electrons in priority order
+		if a.queue != nil {
+			a.routine(a.dequeue)
+		}
+
+		// Replay the events spilled while the events channel was full
+		if a.spill != nil {
+			a.routine(a.replay)
+		}
+
+		// Pause intake while the node is under resource pressure
+		if a.resources != nil {
+			a.routine(a.monitor)
+		}
+
+		// Periodically check the health of the conductors
+		if a.health != nil {
+			a.routine(a.check)
+		}
+
+		// Follow the leadership of this instance so that
+		// electrons are only consumed while leading
+		if a.elector != nil {
+			a.routine(a.lead)
+		}
 
 		// TODO: Setup the instance receivers for monitoring of
 		// individual instances as well as sending of outbound
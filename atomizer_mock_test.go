@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"sync"
 	"testing"
+	"time"
 
 	"devnw.com/alog"
 	"devnw.com/validator"
@@ -278,6 +279,18 @@ func harness(
 	buffer int,
 	atoms ...Atom,
 ) (Conductor, <-chan interface{}, error) {
+	conductor, events, _, err := optHarness(ctx, buffer, nil, atoms...)
+	return conductor, events, err
+}
+
+// optHarness creates a valid atomizer that uses the passthrough conductor
+// configured with the supplied options
+func optHarness(
+	ctx context.Context,
+	buffer int,
+	opts []Option,
+	atoms ...Atom,
+) (Conductor, <-chan interface{}, *atomizer, error) {
 	pass := &passthrough{
 		input: make(chan *Electron, 1),
 	}
@@ -285,33 +298,41 @@ func harness(
 	// Register the conductor so it's picked up
 	// when the atomizer is initialized
 	if err := Register(pass); err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	// Test Atom registrations
 
 	if err := Register(&printer{}); err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	if err := Register(&noopatom{}); err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	if err := Register(&returner{}); err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	for _, a := range atoms {
 		if err := Register(a); err != nil {
-			return nil, nil, err
+			return nil, nil, nil, err
 		}
 	}
 
+	values := make([]interface{}, 0, len(opts))
+	for _, opt := range opts {
+		values = append(values, opt)
+	}
+
 	// Initialize the atomizer
-	mizer, err := Atomize(ctx)
+	mizer, err := Atomize(ctx, values...)
 	if err != nil {
-		return nil, nil, fmt.Errorf("error creating atomizer | %s", err)
+		return nil, nil, nil, fmt.Errorf(
+			"error creating atomizer | %s",
+			err,
+		)
 	}
 
 	a, _ := mizer.(*atomizer)
@@ -322,5 +343,131 @@ func harness(
 	}
 
 	// Start the execution threads
-	return pass, events, a.Exec()
+	return pass, events, a, a.Exec()
+}
+
+// sendAndWait sends the electron through the conductor and blocks
+// until the properties for the electron are returned
+func sendAndWait(
+	ctx context.Context,
+	t *testing.T,
+	c Conductor,
+	e *Electron,
+) *Properties {
+	t.Helper()
+
+	result, err := c.Send(ctx, e)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-ctx.Done():
+		t.Fatal("context closed, test failed")
+	case p, ok := <-result:
+		if !ok {
+			t.Fatal("result channel closed, test failed")
+		}
+
+		return p
+	}
+
+	return nil
+}
+
+// eventually polls the condition until it returns true or the
+// timeout is exceeded
+func eventually(t *testing.T, timeout time.Duration, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(timeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met before timeout")
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// recorder is a conductor which records every completion it receives
+// allowing the same electron to be completed multiple times
+type recorder struct {
+	input       chan *Electron
+	completions chan *Properties
+}
+
+func newRecorder() *recorder {
+	return &recorder{
+		input:       make(chan *Electron),
+		completions: make(chan *Properties, 100),
+	}
+}
+
+func (r *recorder) Receive(ctx context.Context) <-chan *Electron {
+	return r.input
+}
+
+func (r *recorder) Validate() bool { return r.input != nil }
+
+func (r *recorder) Complete(ctx context.Context, p *Properties) error {
+	select {
+	case <-ctx.Done():
+	case r.completions <- p:
+	}
+
+	return nil
+}
+
+func (r *recorder) Send(
+	ctx context.Context,
+	electron *Electron,
+) (<-chan *Properties, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case r.input <- electron:
+	}
+
+	return r.completions, nil
+}
+
+func (r *recorder) Close() {}
+
+// next returns the next completion recorded by the conductor
+func (r *recorder) next(ctx context.Context, t *testing.T) *Properties {
+	t.Helper()
+
+	select {
+	case <-ctx.Done():
+		t.Fatal("context closed, test failed")
+	case p := <-r.completions:
+		return p
+	}
+
+	return nil
+}
+
+// recHarness creates an atomizer with a recorder conductor and
+// the supplied options and registrations
+func recHarness(
+	ctx context.Context,
+	t *testing.T,
+	values ...interface{},
+) (*recorder, *atomizer) {
+	t.Helper()
+
+	rec := newRecorder()
+
+	mizer, err := Atomize(ctx, append(values, rec)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, _ := mizer.(*atomizer)
+	if err = a.Exec(); err != nil {
+		t.Fatal(err)
+	}
+
+	return rec, a
 }
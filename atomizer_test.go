@@ -2,8 +2,11 @@ package engine
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"runtime"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -134,6 +137,71 @@ func check(
 	}
 }
 
+func TestAtomizer_Exec_Twice(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	rec, a := recHarness(ctx, t, &noopatom{})
+
+	eventually(t, time.Second, func() bool {
+		return a.route(newElectron(ID(noopatom{}), nil)) != nil
+	})
+
+	before := runtime.NumGoroutine()
+
+	if err := a.Exec(); !errors.Is(err, ErrAlreadyRunning) {
+		t.Fatalf("expected ErrAlreadyRunning, got %v", err)
+	}
+
+	// Allow any duplicate loops to start
+	time.Sleep(time.Millisecond * 20)
+
+	if after := runtime.NumGoroutine(); after > before {
+		t.Fatalf("expected %v goroutines, got %v", before, after)
+	}
+
+	e := newElectron(ID(noopatom{}), nil)
+	if _, err := rec.Send(ctx, e); err != nil {
+		t.Fatal(err)
+	}
+
+	if p := rec.next(ctx, t); p.ElectronID != e.ID {
+		t.Fatalf("expected %s, got %s", e.ID, p.ElectronID)
+	}
+
+	select {
+	case p := <-rec.completions:
+		t.Fatalf("unexpected completion %+v", p)
+	case <-time.After(time.Millisecond * 20):
+	}
+}
+
+func TestAtomizer_Exec_Closed(t *testing.T) {
+	ctx, cancel := _ctx(context.TODO())
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	a, err := Atomize(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cancel()
+
+	if err = a.Exec(); err == nil {
+		t.Fatal("expected error executing closed atomizer")
+	}
+}
+
 func TestAtomizer_initReg_Exec(t *testing.T) {
 	d := time.Second * 30
 	// Setup a cancellation context for the test
@@ -1093,3 +1161,88 @@ func BenchmarkAtomizer_Exec_Single(b *testing.B) {
 		}
 	}
 }
+
+func TestAtomizer_Register_Interleaved(t *testing.T) {
+	d := time.Second * 10
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	rec, a := recHarness(ctx, t, &noopatom{})
+
+	total := 50
+	go func() {
+		for i := 0; i < total; i++ {
+			if err := a.Register(&noopatom{}); err != nil {
+				return
+			}
+		}
+	}()
+
+	go func() {
+		for i := 0; i < total; i++ {
+			_, err := rec.Send(ctx, newElectron(ID(noopatom{}), nil))
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	for i := 0; i < total; i++ {
+		if p := rec.next(ctx, t); p.Error != nil {
+			t.Fatal(p.Error)
+		}
+	}
+}
+
+var valueExecutions int64
+
+// valueatom is a non-pointer atom which cannot be instantiated
+type valueatom struct{}
+
+func (valueatom) Process(
+	ctx context.Context,
+	conductor Conductor,
+	electron *Electron,
+) ([]byte, error) {
+	atomic.AddInt64(&valueExecutions, 1)
+	return nil, nil
+}
+
+func TestAtomizer_Split_Uninstantiable(t *testing.T) {
+	d := time.Second * 10
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	if _, ok := instantiate(valueatom{}, false); ok {
+		t.Fatal("expected value atom to fail instantiation")
+	}
+
+	rec, a := recHarness(ctx, t, valueatom{})
+
+	eventually(t, time.Second, func() bool {
+		return a.route(newElectron(ID(valueatom{}), nil)) != nil
+	})
+
+	_, err := rec.Send(ctx, newElectron(ID(valueatom{}), nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if p := rec.next(ctx, t); p.Error == nil {
+		t.Fatal("expected instantiation error")
+	}
+
+	if n := atomic.LoadInt64(&valueExecutions); n != 0 {
+		t.Fatalf("expected shared atom to never execute, got %v", n)
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import (
+	"encoding/json"
+	"strconv"
+)
+
+// MarshalBatch marshals the electrons into a single JSON array frame so
+// that network conductors can send many electrons in one payload. Each
+// electron in the frame is received, routed and completed independently.
+func MarshalBatch(electrons []Electron) ([]byte, error) {
+	frame := make([]json.RawMessage, 0, len(electrons))
+	for i := range electrons {
+		data, err := electrons[i].MarshalJSON()
+		if err != nil {
+			return nil, simple(
+				"unable to marshal electron "+strconv.Itoa(i)+" of batch",
+				err,
+			)
+		}
+
+		frame = append(frame, data)
+	}
+
+	return json.Marshal(frame)
+}
+
+// UnmarshalBatch unmarshals a JSON array frame of electrons created
+// with MarshalBatch
+func UnmarshalBatch(data []byte) ([]Electron, error) {
+	var frame []json.RawMessage
+	if err := json.Unmarshal(data, &frame); err != nil {
+		return nil, simple("invalid electron batch", err)
+	}
+
+	electrons := make([]Electron, len(frame))
+	for i, raw := range frame {
+		if err := electrons[i].UnmarshalJSON(raw); err != nil {
+			return nil, simple(
+				"unable to unmarshal electron "+strconv.Itoa(i)+" of batch",
+				err,
+			)
+		}
+	}
+
+	return electrons, nil
+}
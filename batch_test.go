@@ -0,0 +1,39 @@
+package engine
+
+import (
+	"testing"
+)
+
+func TestBatch(t *testing.T) {
+	electrons := []Electron{
+		*newElectron("a", []byte(`{"n":1}`)),
+		*newElectron("b", nil),
+		*newElectron("c", []byte(`{"n":3}`)),
+	}
+
+	data, err := MarshalBatch(electrons)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := UnmarshalBatch(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(out) != len(electrons) {
+		t.Fatalf("expected %v electrons, got %v", len(electrons), len(out))
+	}
+
+	for i := range electrons {
+		if out[i].ID != electrons[i].ID ||
+			out[i].AtomID != electrons[i].AtomID ||
+			string(out[i].Payload) != string(electrons[i].Payload) {
+			t.Fatalf("expected %+v, got %+v", electrons[i], out[i])
+		}
+	}
+
+	if _, err = UnmarshalBatch([]byte(`{"id":"single"}`)); err == nil {
+		t.Fatal("expected error unmarshaling a non-batch frame")
+	}
+}
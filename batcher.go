@@ -0,0 +1,159 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// BatchCompleter is an optional interface for conductors which complete
+// many electrons in a single frame, such as network conductors. When
+// completion batching is enabled (see WithCompletionBatching) the
+// completions for a BatchCompleter are accumulated and completed together
+// through CompleteBatch rather than individually through Complete. The
+// properties of a batch are in the order the electrons were received
+// from the conductor rather than the order they finished.
+type BatchCompleter interface {
+	CompleteBatch(ctx context.Context, properties []Properties) error
+}
+
+// WithCompletionBatching accumulates the completions for conductors which
+// implement BatchCompleter, flushing them as a single batch once size
+// completions are pending or interval has passed since the first pending
+// completion, whichever comes first. Pending completions are flushed on
+// Shutdown.
+func WithCompletionBatching(size int, interval time.Duration) Option {
+	return func(a *atomizer) error {
+		if size < 1 {
+			return simple("completion batch size must be at least 1", nil)
+		}
+
+		if interval <= 0 {
+			return simple("completion batch interval must be positive", nil)
+		}
+
+		a.batching = &batcher{
+			a:        a,
+			size:     size,
+			interval: interval,
+			pending:  make(map[string]*pendingBatch),
+		}
+
+		return nil
+	}
+}
+
+// batched is a completion pending in a batch
+type batched struct {
+	seq uint64
+	p   Properties
+}
+
+// pendingBatch holds the pending completions of a conductor
+type pendingBatch struct {
+	conductor BatchCompleter
+	items     []batched
+	timer     *time.Timer
+}
+
+// batcher accumulates the completions of the batch completers
+type batcher struct {
+	a        *atomizer
+	size     int
+	interval time.Duration
+
+	mu      sync.Mutex
+	pending map[string]*pendingBatch
+}
+
+// add adds the completion to the pending batch of the conductor and
+// flushes the batch once it is full
+func (b *batcher) add(bc BatchCompleter, seq uint64, p *Properties) error {
+	id := ID(bc)
+
+	b.mu.Lock()
+	pb, ok := b.pending[id]
+	if !ok {
+		pb = &pendingBatch{conductor: bc}
+		pb.timer = time.AfterFunc(b.interval, func() {
+			b.flush(b.a.ctx, id, pb)
+		})
+
+		b.pending[id] = pb
+	}
+
+	pb.items = append(pb.items, batched{seq: seq, p: *p})
+	full := len(pb.items) >= b.size
+	b.mu.Unlock()
+
+	if !full {
+		return nil
+	}
+
+	return b.flush(b.a.ctx, id, pb)
+}
+
+// flush completes the batch if it is still pending, in the order
+// the electrons of the batch were received
+func (b *batcher) flush(ctx context.Context, id string, pb *pendingBatch) error {
+	b.mu.Lock()
+	if b.pending[id] != pb {
+		// The batch was already flushed
+		b.mu.Unlock()
+		return nil
+	}
+
+	delete(b.pending, id)
+	pb.timer.Stop()
+	b.mu.Unlock()
+
+	sort.SliceStable(pb.items, func(i, j int) bool {
+		return pb.items[i].seq < pb.items[j].seq
+	})
+
+	properties := make([]Properties, 0, len(pb.items))
+	for _, item := range pb.items {
+		properties = append(properties, item.p)
+	}
+
+	err := pb.conductor.CompleteBatch(ctx, properties)
+	if err != nil {
+		err = &Error{
+			Event: &Event{
+				Message:     "error completing batch",
+				ConductorID: id,
+			},
+			Internal: err,
+		}
+
+		b.a.err(func() error {
+			return err
+		})
+	}
+
+	return err
+}
+
+// drain flushes every pending batch
+func (b *batcher) drain(ctx context.Context) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	pending := make(map[string]*pendingBatch, len(b.pending))
+	for id, pb := range b.pending {
+		pending[id] = pb
+	}
+	b.mu.Unlock()
+
+	for id, pb := range pending {
+		_ = b.flush(ctx, id, pb)
+	}
+}
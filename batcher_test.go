@@ -0,0 +1,179 @@
+package engine
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// delayatom sleeps for the number of milliseconds in its payload
+type delayatom struct{}
+
+func (*delayatom) Process(
+	ctx context.Context,
+	conductor Conductor,
+	electron *Electron,
+) ([]byte, error) {
+	ms, err := strconv.Atoi(string(electron.Payload))
+	if err != nil {
+		return nil, err
+	}
+
+	time.Sleep(time.Duration(ms) * time.Millisecond)
+	return electron.Payload, nil
+}
+
+// batchconductor is a recorder which completes electrons in batches
+type batchconductor struct {
+	*recorder
+	batches chan []Properties
+}
+
+func (c *batchconductor) CompleteBatch(
+	ctx context.Context,
+	properties []Properties,
+) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case c.batches <- properties:
+		return nil
+	}
+}
+
+func TestAtomizer_CompletionBatching(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	c := &batchconductor{
+		recorder: newRecorder(),
+		batches:  make(chan []Properties, 10),
+	}
+
+	atomID := ID(delayatom{})
+	mizer, err := Atomize(
+		ctx,
+		WithCompletionBatching(3, time.Second*10),
+		WithReplicas(atomID, 3),
+		&delayatom{},
+		c,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, _ := mizer.(*atomizer)
+	if err = a.Exec(); err != nil {
+		t.Fatal(err)
+	}
+
+	eventually(t, time.Second, func() bool {
+		return a.route(newElectron(atomID, nil)) != nil
+	})
+
+	// The electrons are partitioned to different replicas so
+	// that they execute concurrently and finish in reverse order
+	var electrons []*Electron
+	routed := make(map[chan<- instance]bool)
+	for i, delay := range []string{"150", "75", "0"} {
+		e := newElectron(atomID, []byte(delay))
+		for k := i; ; k++ {
+			e.PartitionKey = strconv.Itoa(k)
+			if !routed[a.route(e)] {
+				routed[a.route(e)] = true
+				break
+			}
+		}
+
+		electrons = append(electrons, e)
+	}
+
+	for _, e := range electrons {
+		if _, err = c.Send(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var batch []Properties
+	select {
+	case <-ctx.Done():
+		t.Fatal("expected completion batch")
+	case batch = <-c.batches:
+	}
+
+	if len(batch) != len(electrons) {
+		t.Fatalf("expected batch of %v, got %v", len(electrons), len(batch))
+	}
+
+	for i, e := range electrons {
+		if batch[i].ElectronID != e.ID {
+			t.Fatalf("expected electron %v of the batch to be %s", i, e.ID)
+		}
+	}
+
+	if !batch[0].End.After(batch[2].End) {
+		t.Fatal("expected the electrons to finish out of order")
+	}
+
+	select {
+	case p := <-c.completions:
+		t.Fatalf("unexpected individual completion %v", p)
+	default:
+	}
+}
+
+func TestAtomizer_CompletionBatching_Interval(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	c := &batchconductor{
+		recorder: newRecorder(),
+		batches:  make(chan []Properties, 10),
+	}
+
+	mizer, err := Atomize(
+		ctx,
+		WithCompletionBatching(10, time.Millisecond*50),
+		&noopatom{},
+		c,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, _ := mizer.(*atomizer)
+	if err = a.Exec(); err != nil {
+		t.Fatal(err)
+	}
+
+	eventually(t, time.Second, func() bool {
+		return a.route(newElectron(ID(noopatom{}), nil)) != nil
+	})
+
+	e := newElectron(ID(noopatom{}), nil)
+	if _, err = c.Send(ctx, e); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-ctx.Done():
+		t.Fatal("expected the partial batch to flush on the interval")
+	case batch := <-c.batches:
+		if len(batch) != 1 || batch[0].ElectronID != e.ID {
+			t.Fatalf("unexpected batch %v", batch)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+// buffers are the buffer sizes of the internal channels of the atomizer,
+// a zero size is an unbuffered channel
+type buffers struct {
+	electrons     int
+	bonded        int
+	registrations int
+	atoms         int
+}
+
+// WithBuffer sizes the buffers of the internal channels of the atomizer
+// which are unbuffered by default. Buffering the electrons lets the
+// conductors continue receiving while distribute is busy rather than
+// serializing on each hand-off, at the cost of electrons being held in
+// memory. The per-atom channels are sized separately (see WithAtomBuffer).
+func WithBuffer(electrons, bonded, registrations int) Option {
+	return func(a *atomizer) error {
+		if electrons < 0 || bonded < 0 || registrations < 0 {
+			return simple("buffer sizes must not be negative", nil)
+		}
+
+		a.buffers.electrons = electrons
+		a.buffers.bonded = bonded
+		a.buffers.registrations = registrations
+
+		return nil
+	}
+}
+
+// WithAtomBuffer sizes the buffer of the channel of each atom replica
+// which distribute pushes the electrons for the atom to. The channels
+// are unbuffered by default.
+func WithAtomBuffer(size int) Option {
+	return func(a *atomizer) error {
+		if size < 0 {
+			return simple("atom buffer size must not be negative", nil)
+		}
+
+		a.buffers.atoms = size
+		return nil
+	}
+}
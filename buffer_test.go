@@ -0,0 +1,90 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestAtomize_Buffer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	const n = 10
+
+	mizer, err := Atomize(ctx, WithBuffer(n, 1, 1), WithAtomBuffer(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, _ := mizer.(*atomizer)
+	if cap(a.bonded) != 1 || cap(a.registrations) != 1 {
+		t.Fatalf(
+			"expected bonded and registrations buffers of 1, got %v and %v",
+			cap(a.bonded),
+			cap(a.registrations),
+		)
+	}
+
+	if c := a.split(&noopatom{}); cap(c) != 2 {
+		t.Fatalf("expected atom buffer of 2, got %v", cap(c))
+	}
+
+	// Distribute is not running so the electrons are only
+	// enqueued when the channel has room for them
+	enqueue := func() bool {
+		done := make(chan bool, 1)
+		go func() {
+			done <- a.enqueue(instance{electron: newElectron("atom", nil)})
+		}()
+
+		select {
+		case ok := <-done:
+			return ok
+		case <-time.After(time.Millisecond * 50):
+			return false
+		}
+	}
+
+	for i := 0; i < n; i++ {
+		if !enqueue() {
+			t.Fatalf("expected electron %v to be enqueued", i)
+		}
+	}
+
+	if enqueue() {
+		t.Fatal("expected enqueue beyond the buffer to block")
+	}
+}
+
+func TestAtomize_Buffer_Default(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mizer, err := Atomize(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, _ := mizer.(*atomizer)
+	if cap(a.electrons) != 0 || cap(a.bonded) != 0 || cap(a.registrations) != 0 {
+		t.Fatal("expected unbuffered channels")
+	}
+}
+
+func TestWithBuffer_Invalid(t *testing.T) {
+	tests := map[string]Option{
+		"electrons":     WithBuffer(-1, 0, 0),
+		"bonded":        WithBuffer(0, -1, 0),
+		"registrations": WithBuffer(0, 0, -1),
+		"atoms":         WithAtomBuffer(-1),
+	}
+
+	for name, opt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := opt(&atomizer{}); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
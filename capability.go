@@ -0,0 +1,72 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+// Capable is implemented by atoms which advertise the capabilities they
+// provide. Electrons without an AtomID are routed by the capabilities
+// they require (see Electron.Requires) rather than by atom ID, which
+// decouples senders from specific atoms. Of the capable atoms the one
+// with the fewest surplus capabilities is selected, ties are broken by
+// the lowest atom ID.
+type Capable interface {
+	Capabilities() []string
+}
+
+// resolve sets the AtomID of an electron requiring capabilities to the
+// registered atom which best satisfies them. Of the atoms advertising a
+// superset of the required capabilities the one with the fewest surplus
+// capabilities is selected, and ties are broken by the lowest atom ID so
+// that routing is deterministic. The AtomID is left empty when no
+// registered atom is capable.
+func (a *atomizer) resolve(e *Electron) {
+	if e.AtomID != "" || e.ForceAtomKey != "" || len(e.Requires) == 0 {
+		return
+	}
+
+	a.atomsMu.RLock()
+	defer a.atomsMu.RUnlock()
+
+	surplus := -1
+	for id, reps := range a.atoms {
+		c, ok := reps.atom.(Capable)
+		if !ok {
+			continue
+		}
+
+		extra, ok := satisfies(c.Capabilities(), e.Requires)
+		if !ok {
+			continue
+		}
+
+		if surplus < 0 || extra < surplus ||
+			(extra == surplus && id < e.AtomID) {
+			surplus = extra
+			e.AtomID = id
+		}
+	}
+}
+
+// satisfies indicates if the capabilities are a superset of the required
+// capabilities, returning the number of surplus capabilities
+func satisfies(capabilities, required []string) (int, bool) {
+	advertised := make(map[string]bool, len(capabilities))
+	for _, c := range capabilities {
+		advertised[c] = true
+	}
+
+	for _, r := range required {
+		if !advertised[r] {
+			return 0, false
+		}
+	}
+
+	distinct := make(map[string]bool, len(required))
+	for _, r := range required {
+		distinct[r] = true
+	}
+
+	return len(advertised) - len(distinct), true
+}
@@ -0,0 +1,141 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type imageatom struct{ noopatom }
+
+func (*imageatom) Capabilities() []string {
+	return []string{"image"}
+}
+
+type editoratom struct{ noopatom }
+
+func (*editoratom) Capabilities() []string {
+	return []string{"image", "resize", "crop"}
+}
+
+type cropatom struct{ noopatom }
+
+func (*cropatom) Capabilities() []string {
+	return []string{"image", "resize"}
+}
+
+type resizeatom struct{ noopatom }
+
+func (*resizeatom) Capabilities() []string {
+	return []string{"resize", "image"}
+}
+
+func TestAtomizer_Capabilities(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	rec, a := recHarness(ctx, t, &imageatom{}, &editoratom{})
+	rejections := a.Rejections(10)
+
+	eventually(t, time.Second, func() bool {
+		return a.route(newElectron(ID(editoratom{}), nil)) != nil &&
+			a.route(newElectron(ID(imageatom{}), nil)) != nil
+	})
+
+	e := newElectron("", nil)
+	e.Requires = []string{"image", "resize"}
+	if _, err := rec.Send(ctx, e); err != nil {
+		t.Fatal(err)
+	}
+
+	p := rec.next(ctx, t)
+	if p.AtomID != ID(editoratom{}) {
+		t.Fatalf("expected %s, got %s", ID(editoratom{}), p.AtomID)
+	}
+
+	e = newElectron("", nil)
+	e.Requires = []string{"video"}
+	if _, err := rec.Send(ctx, e); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-ctx.Done():
+		t.Fatal("expected rejection for incapable electron")
+	case r := <-rejections:
+		if r.Electron.ID != e.ID {
+			t.Fatalf("expected rejection of %s, got %s", e.ID, r.Electron.ID)
+		}
+	}
+
+	// The sender of the incapable electron is not left waiting
+	if p = rec.next(ctx, t); p.ElectronID != e.ID || p.Error == nil {
+		t.Fatalf("expected error completion of %s, got %+v", e.ID, p)
+	}
+}
+
+func TestAtomizer_Capabilities_TieBreak(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	rec, a := recHarness(ctx, t, &editoratom{}, &resizeatom{})
+
+	eventually(t, time.Second, func() bool {
+		return a.route(newElectron(ID(editoratom{}), nil)) != nil &&
+			a.route(newElectron(ID(resizeatom{}), nil)) != nil
+	})
+
+	// Both atoms are capable, the tightest match is selected
+	e := newElectron("", nil)
+	e.Requires = []string{"image", "resize"}
+	if _, err := rec.Send(ctx, e); err != nil {
+		t.Fatal(err)
+	}
+
+	if p := rec.next(ctx, t); p.AtomID != ID(resizeatom{}) {
+		t.Fatalf("expected %s, got %s", ID(resizeatom{}), p.AtomID)
+	}
+}
+
+func TestAtomizer_Capabilities_TieBreak_ID(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	rec, a := recHarness(ctx, t, &resizeatom{}, &cropatom{})
+
+	eventually(t, time.Second, func() bool {
+		return a.route(newElectron(ID(cropatom{}), nil)) != nil &&
+			a.route(newElectron(ID(resizeatom{}), nil)) != nil
+	})
+
+	// Both atoms match equally tightly, the lowest atom ID is selected
+	for i := 0; i < 5; i++ {
+		e := newElectron("", nil)
+		e.Requires = []string{"resize", "image"}
+		if _, err := rec.Send(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+
+		if p := rec.next(ctx, t); p.AtomID != ID(cropatom{}) {
+			t.Fatalf("expected %s, got %s", ID(cropatom{}), p.AtomID)
+		}
+	}
+}
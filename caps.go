@@ -0,0 +1,90 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import (
+	"fmt"
+)
+
+// WithMaxAtoms caps the number of atoms which can be registered with the
+// atomizer at the same time. Registrations beyond the cap are rejected with
+// an error. Replacing an already registered atom does not count against
+// the cap and deregistering an atom frees its capacity.
+func WithMaxAtoms(n int) Option {
+	return func(a *atomizer) error {
+		if n < 1 {
+			return simple("max atoms must be at least 1", nil)
+		}
+
+		a.maxAtoms = n
+		return nil
+	}
+}
+
+// WithMaxConductors caps the number of conductors which can be registered
+// with the atomizer at the same time. Registrations beyond the cap are
+// rejected with an error. Replacing an already registered conductor does
+// not count against the cap and deregistering a conductor frees its
+// capacity.
+func WithMaxConductors(n int) Option {
+	return func(a *atomizer) error {
+		if n < 1 {
+			return simple("max conductors must be at least 1", nil)
+		}
+
+		a.maxConductors = n
+		return nil
+	}
+}
+
+// atomCapped indicates if registering the atom would exceed the
+// maximum number of atoms. The atoms lock MUST be held.
+func (a *atomizer) atomCapped(atomID string) error {
+	if a.maxAtoms < 1 {
+		return nil
+	}
+
+	if _, ok := a.atoms[atomID]; ok {
+		return nil
+	}
+
+	if _, ok := a.disabled[atomID]; ok {
+		return nil
+	}
+
+	if len(a.atoms)+len(a.disabled) < a.maxAtoms {
+		return nil
+	}
+
+	return &Error{Event: &Event{
+		Message: fmt.Sprintf("max atoms [%v] registered", a.maxAtoms),
+		AtomID:  atomID,
+	}}
+}
+
+// conductorCapped indicates if registering the conductor would exceed
+// the maximum number of conductors. The conductors lock MUST be held.
+func (a *atomizer) conductorCapped(conductorID string) error {
+	if a.maxConductors < 1 {
+		return nil
+	}
+
+	if _, ok := a.conductors[conductorID]; ok {
+		return nil
+	}
+
+	if len(a.conductors) < a.maxConductors {
+		return nil
+	}
+
+	return &Error{Event: &Event{
+		Message: fmt.Sprintf(
+			"max conductors [%v] registered",
+			a.maxConductors,
+		),
+		ConductorID: conductorID,
+	}}
+}
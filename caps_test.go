@@ -0,0 +1,136 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestAtomizer_MaxAtoms(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	mizer, err := Atomize(ctx, WithMaxAtoms(1), &noopatom{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, _ := mizer.(*atomizer)
+	errs := a.Errors(10)
+
+	if err = a.Exec(); err != nil {
+		t.Fatal(err)
+	}
+
+	registered := func(atomID string) func() bool {
+		return func() bool {
+			a.atomsMu.RLock()
+			defer a.atomsMu.RUnlock()
+
+			_, ok := a.atoms[atomID]
+			return ok
+		}
+	}
+
+	eventually(t, time.Second, registered(ID(noopatom{})))
+
+	if err = a.Register(&printer{}); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-ctx.Done():
+		t.Fatal("expected cap error")
+	case err = <-errs:
+		e, ok := err.(*Error)
+		if !ok || e.Event.AtomID != ID(printer{}) {
+			t.Fatalf("expected cap error for printer, got %v", err)
+		}
+	}
+
+	if registered(ID(printer{}))() {
+		t.Fatal("expected printer to be rejected")
+	}
+
+	if err = a.deregister(ID(noopatom{})); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = a.Register(&printer{}); err != nil {
+		t.Fatal(err)
+	}
+
+	eventually(t, time.Second, registered(ID(printer{})))
+}
+
+func TestAtomizer_MaxConductors(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	rec := newRecorder()
+	pass := &passthrough{input: make(chan *Electron)}
+
+	mizer, err := Atomize(ctx, WithMaxConductors(1), rec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, _ := mizer.(*atomizer)
+	errs := a.Errors(10)
+
+	if err = a.Exec(); err != nil {
+		t.Fatal(err)
+	}
+
+	registered := func(conductorID string) func() bool {
+		return func() bool {
+			a.conductorsMu.RLock()
+			defer a.conductorsMu.RUnlock()
+
+			_, ok := a.conductors[conductorID]
+			return ok
+		}
+	}
+
+	eventually(t, time.Second, registered(ID(rec)))
+
+	if err = a.Register(pass); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-ctx.Done():
+		t.Fatal("expected cap error")
+	case err = <-errs:
+		e, ok := err.(*Error)
+		if !ok || e.Event.ConductorID != ID(pass) {
+			t.Fatalf("expected cap error for passthrough, got %v", err)
+		}
+	}
+
+	if err = a.deregister(ID(rec)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = a.Register(pass); err != nil {
+		t.Fatal(err)
+	}
+
+	eventually(t, time.Second, registered(ID(pass)))
+
+	if err = a.deregister("unknown"); err == nil {
+		t.Fatal("expected error deregistering unknown id")
+	}
+}
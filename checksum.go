@@ -0,0 +1,99 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"hash/crc32"
+	"strings"
+)
+
+// ErrPayloadChecksumMismatch is returned when unmarshalling an electron
+// whose payload does not match the checksum it was marshaled with
+var ErrPayloadChecksumMismatch = errors.New("payload checksum mismatch")
+
+// ChecksumAlgorithm is the algorithm used to checksum the payload of an
+// electron when it is marshaled
+type ChecksumAlgorithm string
+
+const (
+	// CRC32 checksums the payload using the IEEE CRC-32 polynomial
+	CRC32 ChecksumAlgorithm = "crc32"
+
+	// SHA256 checksums the payload using SHA-256
+	SHA256 ChecksumAlgorithm = "sha256"
+)
+
+// sum returns the checksum of the data in the form `algorithm:hex`
+func (c ChecksumAlgorithm) sum(data []byte) (string, error) {
+	var sum []byte
+	switch c {
+	case CRC32:
+		sum = make([]byte, 4)
+		binary.BigEndian.PutUint32(sum, crc32.ChecksumIEEE(data))
+	case SHA256:
+		s := sha256.Sum256(data)
+		sum = s[:]
+	default:
+		return "", simple("unknown checksum algorithm "+string(c), nil)
+	}
+
+	return string(c) + ":" + hex.EncodeToString(sum), nil
+}
+
+// checksum returns the checksum of the payload as it is written
+// to the wire so that it can be verified against the wire form on
+// unmarshal regardless of how the payload is decoded
+func checksum(algorithm ChecksumAlgorithm, payload []byte) (string, error) {
+	// Empty payloads are omitted from the wire
+	if len(payload) == 0 {
+		return algorithm.sum(nil)
+	}
+
+	wire, err := json.Marshal(json.RawMessage(payload))
+	if err != nil {
+		return "", err
+	}
+
+	return algorithm.sum(wire)
+}
+
+// verifyChecksum verifies the wire form of a payload against the
+// checksum and returns the algorithm of the checksum. The wire form is
+// compacted the same way the payload was when the checksum was taken so
+// that a payload which was re-serialized, i.e. indented, still verifies.
+func verifyChecksum(sum string, wire []byte) (ChecksumAlgorithm, error) {
+	i := strings.Index(sum, ":")
+	if i < 0 {
+		return "", simple("invalid checksum "+sum, nil)
+	}
+
+	algorithm := ChecksumAlgorithm(sum[:i])
+
+	if len(wire) > 0 {
+		compact, err := json.Marshal(json.RawMessage(wire))
+		if err != nil {
+			return "", simple("electron payload", err)
+		}
+
+		wire = compact
+	}
+
+	expected, err := algorithm.sum(wire)
+	if err != nil {
+		return "", err
+	}
+
+	if expected != sum {
+		return "", simple("electron payload", ErrPayloadChecksumMismatch)
+	}
+
+	return algorithm, nil
+}
@@ -0,0 +1,139 @@
+package engine
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestElectron_Checksum(t *testing.T) {
+	tests := map[string]struct {
+		algorithm ChecksumAlgorithm
+		payload   []byte
+		corrupt   bool
+	}{
+		"crc32 intact":   {CRC32, []byte(`{"test":"test"}`), false},
+		"crc32 corrupt":  {CRC32, []byte(`{"test":"test"}`), true},
+		"sha256 intact":  {SHA256, []byte(`{"test":"test"}`), false},
+		"sha256 corrupt": {SHA256, []byte(`{"test":"test"}`), true},
+		"empty payload":  {SHA256, nil, false},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			e := &Electron{
+				SenderID: "sender",
+				ID:       "id",
+				AtomID:   "atom",
+				Checksum: test.algorithm,
+				Payload:  test.payload,
+			}
+
+			data, err := json.Marshal(e)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if test.corrupt {
+				data = bytes.Replace(
+					data,
+					[]byte(`"test":"test"`),
+					[]byte(`"test":"tset"`),
+					1,
+				)
+			}
+
+			out := &Electron{}
+			err = json.Unmarshal(data, out)
+			if test.corrupt {
+				if !errors.Is(err, ErrPayloadChecksumMismatch) {
+					t.Fatalf("expected checksum mismatch, got %v", err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !bytes.Equal(out.Payload, test.payload) {
+				t.Fatalf("expected %s, got %s", test.payload, out.Payload)
+			}
+
+			if out.Checksum != test.algorithm {
+				t.Fatalf("expected %s, got %s", test.algorithm, out.Checksum)
+			}
+		})
+	}
+}
+
+func TestElectron_Checksum_Unknown(t *testing.T) {
+	e := &Electron{
+		SenderID: "sender",
+		ID:       "id",
+		AtomID:   "atom",
+		Checksum: "md4",
+	}
+
+	if _, err := json.Marshal(e); err == nil {
+		t.Fatal("expected error marshaling unknown checksum algorithm")
+	}
+
+	data := []byte(`{"senderid":"s","id":"i","atomid":"a","checksum":"md4:00"}`)
+	if err := json.Unmarshal(data, &Electron{}); err == nil {
+		t.Fatal("expected error unmarshaling unknown checksum algorithm")
+	}
+}
+
+func TestElectron_Checksum_Reformatted(t *testing.T) {
+	payload := []byte(`{"a":[1,2],"b":"test"}`)
+
+	for _, algorithm := range []ChecksumAlgorithm{CRC32, SHA256} {
+		t.Run(string(algorithm), func(t *testing.T) {
+			e := &Electron{
+				SenderID: "sender",
+				ID:       "id",
+				AtomID:   "atom",
+				Checksum: algorithm,
+				Payload:  payload,
+			}
+
+			// Indented as written by the pretty stream conductor
+			data, err := json.MarshalIndent(e, "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			out := &Electron{}
+			if err = json.Unmarshal(data, out); err != nil {
+				t.Fatal(err)
+			}
+
+			compact := &bytes.Buffer{}
+			if err = json.Compact(compact, out.Payload); err != nil {
+				t.Fatal(err)
+			}
+
+			if compact.String() != string(payload) {
+				t.Fatalf("expected %s, got %s", payload, compact)
+			}
+
+			// Reformatting the payload is not a corruption
+			reformatted := bytes.Replace(
+				data,
+				[]byte(`"a": [`),
+				[]byte("\"a\" :\n [ "),
+				1,
+			)
+			if bytes.Equal(reformatted, data) {
+				t.Fatal("expected the payload to be reformatted")
+			}
+
+			if err = json.Unmarshal(reformatted, out); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
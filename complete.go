@@ -0,0 +1,100 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import (
+	"context"
+	"time"
+)
+
+// complete pushes the properties of a completed instance
+// through to the conductor the electron was received from
+func (a *atomizer) complete(
+	ctx context.Context,
+	inst *instance,
+	p *Properties,
+) error {
+	if inst.electron != nil && !inst.electron.ExpectsReply() {
+		a.event(func() interface{} {
+			return &Event{
+				Message:     "electron completed without reply",
+				ElectronID:  inst.electron.ID,
+				AtomID:      inst.electron.AtomID,
+				ConductorID: ID(inst.conductor),
+			}
+		})
+
+		return nil
+	}
+
+	if p != nil {
+		p.NodeID = a.nodeID
+	}
+
+	a.transform(ctx, inst, p)
+	a.hashResult(p)
+	a.compression.compress(p)
+
+	if a.signer != nil && p != nil {
+		if err := p.Sign(a.signer.id, a.signer.key); err != nil {
+			return err
+		}
+	}
+
+	if inst.electron != nil && inst.electron.GroupID != "" {
+		var gc GroupCompleter
+		if conductorAs(inst.conductor, &gc) {
+			return a.groups.complete(
+				ctx,
+				gc,
+				inst.electron,
+				p,
+				func(err error) {
+					a.err(func() error { return err })
+				},
+			)
+		}
+	}
+
+	var bc BatchCompleter
+	if a.batching != nil && p != nil && conductorAs(inst.conductor, &bc) {
+		return a.batching.add(bc, inst.seq, p)
+	}
+
+	return a.deliver(ctx, inst, p)
+}
+
+// reject completes an electron which will not be processed with
+// the error, delivers it to the rejections and emits the error
+func (a *atomizer) reject(inst instance, stage RejectionStage, err *Error) {
+	defer a.inflight.done(inst.electron)
+
+	a.rejected(inst, stage, err)
+
+	if inst.conductor != nil && inst.electron != nil &&
+		inst.electron.ExpectsReply() {
+		now := time.Now()
+		completion := inst.conductor.Complete(a.ctx, &Properties{
+			ElectronID:    inst.electron.ID,
+			AtomID:        inst.electron.AtomID,
+			CorrelationID: inst.electron.CorrelationID,
+			NodeID:        a.nodeID,
+			Start:         now,
+			End:           now,
+			Error:         err,
+		})
+
+		if completion != nil {
+			a.err(func() error {
+				return completion
+			})
+		}
+	}
+
+	a.err(func() error {
+		return err
+	})
+}
@@ -0,0 +1,164 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"sync"
+)
+
+// Gzip is the name of the gzip compression codec which is
+// registered by default
+const Gzip = "gzip"
+
+// DefaultMaxDecompressed is the maximum number of bytes the default gzip
+// codec decompresses data to
+const DefaultMaxDecompressed = 64 << 20
+
+// ErrDecompressedSize is returned when decompressed data exceeds
+// the maximum size of the codec
+var ErrDecompressedSize = errors.New("decompressed size exceeds maximum")
+
+// Codec compresses and decompresses data for the wire
+type Codec interface {
+	Compress(data []byte) ([]byte, error)
+	Decompress(data []byte) ([]byte, error)
+}
+
+var codecs sync.Map
+
+func init() {
+	_ = RegisterCodec(Gzip, NewGzipCodec(DefaultMaxDecompressed))
+}
+
+// RegisterCodec adds a named compression codec to the atomizer. The name
+// is included in the serialized form of compressed data so the same codec
+// must be registered on the receiving side. Registrations using the same
+// name will be overridden.
+func RegisterCodec(name string, codec Codec) error {
+	if name == "" {
+		return simple("empty codec name", nil)
+	}
+
+	if codec == nil {
+		return simple("nil codec "+name, nil)
+	}
+
+	codecs.Store(name, codec)
+
+	return nil
+}
+
+// codec returns the registered codec with the name
+func codec(name string) (Codec, error) {
+	value, ok := codecs.Load(name)
+	if !ok {
+		return nil, simple("unknown codec "+name, nil)
+	}
+
+	c, _ := value.(Codec)
+	return c, nil
+}
+
+// NewGzipCodec returns a gzip codec which fails to decompress data larger
+// than limit bytes, guarding against decompression bombs. Registering it as
+// Gzip (see RegisterCodec) overrides the maximum of the default codec.
+func NewGzipCodec(limit int64) Codec {
+	if limit <= 0 {
+		limit = DefaultMaxDecompressed
+	}
+
+	return gzipCodec{limit: limit}
+}
+
+// gzipCodec compresses using gzip
+type gzipCodec struct {
+	limit int64
+}
+
+func (gzipCodec) Compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (c gzipCodec) Decompress(data []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	limit := c.limit
+	if limit <= 0 {
+		limit = DefaultMaxDecompressed
+	}
+
+	// Read past the limit to detect data which exceeds it
+	out, err := ioutil.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(out)) > limit {
+		return nil, simple(
+			"gzip data exceeds "+strconv.FormatInt(limit, 10)+" bytes",
+			ErrDecompressedSize,
+		)
+	}
+
+	return out, nil
+}
+
+// resultCompression is the compression of results
+// above the threshold using the codec
+type resultCompression struct {
+	codec     string
+	threshold int
+}
+
+// WithResultCompression compresses the results of electrons which are at
+// least threshold bytes using the named codec when their properties are
+// marshalled. The codec name is included in the marshalled properties and
+// the result is decompressed automatically when unmarshalled.
+func WithResultCompression(name string, threshold int) Option {
+	return func(a *atomizer) error {
+		if _, err := codec(name); err != nil {
+			return err
+		}
+
+		if threshold < 0 {
+			return simple("negative compression threshold", nil)
+		}
+
+		a.compression = &resultCompression{name, threshold}
+		return nil
+	}
+}
+
+// compress marks the properties for compression
+// when the result exceeds the threshold
+func (c *resultCompression) compress(p *Properties) {
+	if c == nil || p == nil || len(p.Result) < c.threshold {
+		return
+	}
+
+	p.codec = c.codec
+}
@@ -0,0 +1,136 @@
+package engine
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAtomizer_ResultCompression(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	rec, _ := recHarness(
+		ctx,
+		t,
+		WithResultCompression(Gzip, 1024),
+		&returner{},
+	)
+
+	tests := map[string]struct {
+		message    string
+		compressed bool
+	}{
+		"large": {`"` + strings.Repeat("a", 4096) + `"`, true},
+		"small": {`"small"`, false},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			payload, err := json.Marshal(&printerdata{Message: test.message})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = rec.Send(ctx, newElectron(ID(returner{}), payload))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			p := rec.next(ctx, t)
+			if p.Error != nil {
+				t.Fatal(p.Error)
+			}
+
+			data, err := json.Marshal(p)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			compressed := bytes.Contains(data, []byte(`"codec":"gzip"`))
+			if compressed != test.compressed {
+				t.Fatalf(
+					"expected compressed %v, got %s",
+					test.compressed,
+					data,
+				)
+			}
+
+			if compressed && len(data) >= len(test.message) {
+				t.Fatalf("expected compressed size, got %v", len(data))
+			}
+
+			received := &Properties{}
+			if err = json.Unmarshal(data, received); err != nil {
+				t.Fatal(err)
+			}
+
+			if string(received.Result) != test.message {
+				t.Fatalf("expected result to round trip, got %s", received.Result)
+			}
+		})
+	}
+}
+
+func TestGzipCodec_MaxDecompressed(t *testing.T) {
+	data := bytes.Repeat([]byte("a"), 1024)
+
+	compressed, err := NewGzipCodec(0).Compress(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		max int64
+		err bool
+	}{
+		"default": {0, false},
+		"at max":  {1024, false},
+		"exceeds": {1023, true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			out, err := NewGzipCodec(test.max).Decompress(compressed)
+			if test.err {
+				if !errors.Is(err, ErrDecompressedSize) {
+					t.Fatalf("expected size error, got %v", err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !bytes.Equal(out, data) {
+				t.Fatal("decompressed data mismatch")
+			}
+		})
+	}
+}
+
+func TestRegisterCodec_Errs(t *testing.T) {
+	if err := RegisterCodec("", gzipCodec{}); err == nil {
+		t.Fatal("expected error for empty name")
+	}
+
+	if err := RegisterCodec("test.nil", nil); err == nil {
+		t.Fatal("expected error for nil codec")
+	}
+
+	if err := WithResultCompression("test.unknown", 0)(&atomizer{}); err == nil {
+		t.Fatal("expected error for unknown codec")
+	}
+}
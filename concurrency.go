@@ -0,0 +1,148 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import (
+	"context"
+	"runtime"
+	"strconv"
+	"sync"
+)
+
+// IOBoundFactor is the multiple of the CPU count used for the replicas
+// of IO-bound atoms under the auto concurrency policy
+const IOBoundFactor = 4
+
+// numCPU returns the number of CPUs available to the process
+var numCPU = runtime.NumCPU
+
+// IOBoundAtom is an optional interface for atoms which indicate that they
+// spend most of their time waiting on IO rather than on the CPU and so
+// benefit from more replicas under the auto concurrency policy
+type IOBoundAtom interface {
+	IOBound() bool
+}
+
+// ConcurrencyLimiter is an optional interface for atoms which declare the
+// maximum number of electrons each processing loop of the atom executes
+// concurrently, taking precedence over WithMaxConcurrency. A maximum less
+// than 1 uses the default.
+type ConcurrencyLimiter interface {
+	MaxConcurrency() int
+}
+
+// WithMaxConcurrency sets the default maximum number of electrons each
+// processing loop of an atom executes concurrently so that a slow electron
+// does not block the electrons queued behind it. By default a processing
+// loop executes a single electron at a time. Electrons executed
+// concurrently by a processing loop may complete out of order, even when
+// they share a PartitionKey.
+func WithMaxConcurrency(n int) Option {
+	return func(a *atomizer) error {
+		if n < 1 {
+			return simple(
+				"invalid max concurrency "+strconv.Itoa(n),
+				nil,
+			)
+		}
+
+		a.maxConcurrency = n
+		return nil
+	}
+}
+
+// WithAutoConcurrency sizes the replicas of each atom relative to the
+// number of CPUs rather than a single replica. Atoms are registered with
+// one replica per CPU, or IOBoundFactor replicas per CPU for atoms which
+// are IO-bound (see IOBoundAtom). Replicas configured for an atom through
+// WithReplicas take precedence.
+func WithAutoConcurrency() Option {
+	return func(a *atomizer) error {
+		a.autoConcurrency = true
+		return nil
+	}
+}
+
+// concurrency returns the number of replicas to register for the atom
+func (a *atomizer) concurrency(atomID string, atom Atom) int {
+	if n := a.replicaCounts[atomID]; n > 0 {
+		return n
+	}
+
+	if !a.autoConcurrency {
+		return 1
+	}
+
+	n := numCPU()
+	if n < 1 {
+		n = 1
+	}
+
+	if io, ok := atom.(IOBoundAtom); ok && io.IOBound() {
+		n *= IOBoundFactor
+	}
+
+	return n
+}
+
+// workers returns the maximum number of electrons each processing
+// loop of the atom executes concurrently
+func (a *atomizer) workers(atom Atom) int {
+	if limiter, ok := atom.(ConcurrencyLimiter); ok {
+		if n := limiter.MaxConcurrency(); n > 0 {
+			return n
+		}
+	}
+
+	if a.maxConcurrency > 0 {
+		return a.maxConcurrency
+	}
+
+	return 1
+}
+
+// pool bounds the number of electrons a processing
+// loop executes concurrently
+type pool struct {
+	slots chan struct{}
+	wg    sync.WaitGroup
+}
+
+func newPool(n int) *pool {
+	return &pool{slots: make(chan struct{}, n)}
+}
+
+// run executes fn once a slot is available, in place when the pool has a
+// single slot, and returns false if the context closed while waiting
+func (p *pool) run(ctx context.Context, fn func()) bool {
+	if cap(p.slots) <= 1 {
+		fn()
+		return true
+	}
+
+	select {
+	case <-ctx.Done():
+		return false
+	case p.slots <- struct{}{}:
+	}
+
+	p.wg.Add(1)
+	go func() {
+		defer func() {
+			<-p.slots
+			p.wg.Done()
+		}()
+
+		fn()
+	}()
+
+	return true
+}
+
+// wait blocks until every electron executing in the pool has finished
+func (p *pool) wait() {
+	p.wg.Wait()
+}
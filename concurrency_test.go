@@ -0,0 +1,177 @@
+package engine
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type ioatom struct{}
+
+func (*ioatom) IOBound() bool { return true }
+
+func (*ioatom) Process(
+	ctx context.Context,
+	conductor Conductor,
+	electron *Electron,
+) ([]byte, error) {
+	return nil, nil
+}
+
+func TestAtomizer_AutoConcurrency(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	cpus := numCPU
+	numCPU = func() int { return 2 }
+	t.Cleanup(func() {
+		numCPU = cpus
+	})
+
+	_, a := recHarness(
+		ctx,
+		t,
+		WithAutoConcurrency(),
+		WithReplicas(ID(limitedatom{}), 3),
+		&ioatom{},
+		&noopatom{},
+		&limitedatom{},
+	)
+
+	expected := map[string]int{
+		ID(noopatom{}):    2,
+		ID(ioatom{}):      2 * IOBoundFactor,
+		ID(limitedatom{}): 3,
+	}
+
+	eventually(t, time.Second, func() bool {
+		a.atomsMu.RLock()
+		defer a.atomsMu.RUnlock()
+
+		for atomID, n := range expected {
+			reps, ok := a.atoms[atomID]
+			if !ok || len(reps.channels) != n {
+				return false
+			}
+		}
+
+		return true
+	})
+}
+
+var (
+	// running is the number of electrons parallelatom is processing
+	running int32
+
+	// parallelrelease blocks parallelatom until it is closed
+	parallelrelease chan struct{}
+)
+
+// parallelatom executes up to 3 electrons concurrently in its
+// processing loop
+type parallelatom struct{}
+
+func (*parallelatom) MaxConcurrency() int { return 3 }
+
+func (*parallelatom) Process(
+	ctx context.Context,
+	conductor Conductor,
+	electron *Electron,
+) ([]byte, error) {
+	atomic.AddInt32(&running, 1)
+	defer atomic.AddInt32(&running, -1)
+
+	<-parallelrelease
+	return nil, nil
+}
+
+func TestAtomizer_MaxConcurrency(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	parallelrelease = make(chan struct{})
+	release := parallelrelease
+
+	var once sync.Once
+	open := func() {
+		once.Do(func() {
+			close(release)
+		})
+	}
+	t.Cleanup(open)
+
+	rec, a := recHarness(ctx, t, &parallelatom{})
+
+	eventually(t, time.Second, func() bool {
+		return a.route(newElectron(ID(parallelatom{}), nil)) != nil
+	})
+
+	for i := 0; i < 3; i++ {
+		e := newElectron(ID(parallelatom{}), nil)
+		if _, err := rec.Send(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Every electron is processing at once in the single loop
+	eventually(t, time.Second, func() bool {
+		return atomic.LoadInt32(&running) == 3
+	})
+
+	open()
+	for i := 0; i < 3; i++ {
+		if p := rec.next(ctx, t); p.Error != nil {
+			t.Fatal(p.Error)
+		}
+	}
+
+	eventually(t, time.Second, func() bool {
+		return atomic.LoadInt32(&running) == 0
+	})
+}
+
+func Test_atomizer_workers(t *testing.T) {
+	tests := map[string]struct {
+		opts     []Option
+		atom     Atom
+		expected int
+	}{
+		"default":   {nil, &noopatom{}, 1},
+		"option":    {[]Option{WithMaxConcurrency(4)}, &noopatom{}, 4},
+		"atom":      {nil, &parallelatom{}, 3},
+		"atom wins": {[]Option{WithMaxConcurrency(4)}, &parallelatom{}, 3},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			a := &atomizer{}
+			for _, opt := range test.opts {
+				if err := opt(a); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if n := a.workers(test.atom); n != test.expected {
+				t.Fatalf("expected %v workers, got %v", test.expected, n)
+			}
+		})
+	}
+
+	if err := WithMaxConcurrency(0)(&atomizer{}); err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -5,7 +5,12 @@
 
 package engine
 
-import "context"
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
 
 // Conductor is the interface that should be implemented for passing
 // electrons to the atomizer that need processing. This should generally be
@@ -27,3 +32,103 @@ type Conductor interface {
 	// Close cleans up the conductor
 	Close()
 }
+
+// Unwrapper is implemented by conductors which wrap another conductor,
+// such as TracingConductor, so that the optional interfaces implemented
+// by the wrapped conductor (i.e. Heartbeater or BatchCompleter) are still
+// used by the atomizer
+type Unwrapper interface {
+	Unwrap() Conductor
+}
+
+// conductorAs finds the first conductor in the chain of wrapped conductors
+// which implements the interface pointed to by target and sets target to
+// that conductor, in the manner of errors.As
+func conductorAs(conductor Conductor, target interface{}) bool {
+	if conductor == nil || target == nil {
+		return false
+	}
+
+	val := reflect.ValueOf(target)
+	if val.Kind() != reflect.Ptr || val.IsNil() ||
+		val.Elem().Kind() != reflect.Interface {
+		return false
+	}
+
+	typ := val.Elem().Type()
+	for depth := 0; conductor != nil && depth < maxUnwrap; depth++ {
+		if reflect.TypeOf(conductor).Implements(typ) {
+			val.Elem().Set(reflect.ValueOf(conductor))
+			return true
+		}
+
+		u, ok := conductor.(Unwrapper)
+		if !ok {
+			return false
+		}
+
+		conductor = u.Unwrap()
+	}
+
+	return false
+}
+
+// maxUnwrap bounds the chain of wrapped conductors
+// followed by conductorAs to protect against cycles
+const maxUnwrap = 16
+
+// Transformer is an optional interface for conductors which serialize
+// the properties of completed electrons into their own format, such as
+// the envelope expected by a legacy system, rather than the standard
+// Properties codec (see MarshalCompletion).
+type Transformer interface {
+	Transform(p Properties) ([]byte, error)
+}
+
+// MarshalCompletion serializes the properties for the conductor using the
+// Transform of the conductor when it implements Transformer and the
+// standard Properties codec otherwise
+func MarshalCompletion(conductor Conductor, p *Properties) ([]byte, error) {
+	if p == nil {
+		return nil, simple("nil properties", nil)
+	}
+
+	var t Transformer
+	if conductorAs(conductor, &t) {
+		return t.Transform(*p)
+	}
+
+	return json.Marshal(p)
+}
+
+// Bounded is an optional interface for conductors which return a bounded
+// (buffered) channel from Receive. Capacity is the number of electrons the
+// conductor can supply before the atomizer must consume them and MUST
+// match the capacity of the channel returned from Receive. The atomizer
+// consumes electrons from the channel only as fast as they are processed
+// so a full channel applies backpressure to the source of the conductor.
+type Bounded interface {
+	Capacity() int
+}
+
+// bounded verifies the capacity of the receive channel of a bounded
+// conductor matches the capacity advertised by the conductor
+func (a *atomizer) bounded(conductor Conductor, receiver <-chan *Electron) {
+	var b Bounded
+	if !conductorAs(conductor, &b) || cap(receiver) == b.Capacity() {
+		return
+	}
+
+	a.err(func() error {
+		return &Error{
+			Event: &Event{
+				Message: fmt.Sprintf(
+					"receive capacity %v does not match advertised capacity %v",
+					cap(receiver),
+					b.Capacity(),
+				),
+				ConductorID: ID(conductor),
+			},
+		}
+	})
+}
@@ -0,0 +1,160 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+// gaterelease blocks gateatom until it is closed
+var gaterelease chan struct{}
+
+type gateatom struct{}
+
+func (*gateatom) Process(
+	ctx context.Context,
+	conductor Conductor,
+	electron *Electron,
+) ([]byte, error) {
+	<-gaterelease
+	return nil, nil
+}
+
+// boundedconductor is a recorder which receives
+// electrons on a bounded channel
+type boundedconductor struct {
+	*recorder
+	receive  chan *Electron
+	capacity int
+}
+
+func (b *boundedconductor) Receive(ctx context.Context) <-chan *Electron {
+	return b.receive
+}
+
+func (b *boundedconductor) Capacity() int {
+	return b.capacity
+}
+
+func TestAtomizer_Bounded_Backpressure(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	gaterelease = make(chan struct{})
+	capacity := 3
+	bounded := &boundedconductor{
+		recorder: newRecorder(),
+		receive:  make(chan *Electron, capacity),
+		capacity: capacity,
+	}
+
+	_, a := recHarness(ctx, t, bounded, &gateatom{})
+	errs := a.Errors(10)
+
+	eventually(t, time.Second, func() bool {
+		return a.route(newElectron(ID(gateatom{}), nil)) != nil
+	})
+
+	// Send until the bounded channel applies backpressure
+	sent := 0
+	for blocked := false; !blocked; {
+		select {
+		case bounded.receive <- newElectron(ID(gateatom{}), nil):
+			sent++
+		case <-time.After(time.Millisecond * 50):
+			blocked = true
+		}
+
+		if sent > 100 {
+			t.Fatal("expected backpressure from the bounded channel")
+		}
+	}
+
+	if len(bounded.receive) != capacity {
+		t.Fatalf(
+			"expected full channel of %v, got %v",
+			capacity,
+			len(bounded.receive),
+		)
+	}
+
+	if sent < capacity {
+		t.Fatalf("expected at least %v electrons sent, got %v", capacity, sent)
+	}
+
+	close(gaterelease)
+
+	for i := 0; i < sent; i++ {
+		if p := bounded.next(ctx, t); p.Error != nil {
+			t.Fatal(p.Error)
+		}
+	}
+
+	select {
+	case err := <-errs:
+		t.Fatalf("unexpected error %v", err)
+	default:
+	}
+}
+
+func TestAtomizer_Bounded_Mismatch(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	bounded := &boundedconductor{
+		recorder: newRecorder(),
+		receive:  make(chan *Electron, 1),
+		capacity: 5,
+	}
+
+	mizer, err := Atomize(ctx, bounded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errs := mizer.Errors(10)
+	if err = mizer.Exec(); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-ctx.Done():
+		t.Fatal("expected capacity mismatch error")
+	case err = <-errs:
+		if !strings.Contains(err.Error(), "capacity") {
+			t.Fatalf("expected capacity mismatch error, got %v", err)
+		}
+	}
+}
+
+func TestMarshalCompletion_Default(t *testing.T) {
+	p := &Properties{ElectronID: "e", AtomID: "a", Result: []byte(`{}`)}
+
+	got, err := MarshalCompletion(newRecorder(), p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected, err := json.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(got) != string(expected) {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+}
@@ -0,0 +1,28 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import "context"
+
+type conductorKey struct{}
+
+// withConductor adds the ID of the conductor an electron was
+// received from to the context of the atom instance
+func withConductor(ctx context.Context, conductor Conductor) context.Context {
+	return context.WithValue(ctx, conductorKey{}, ID(conductor))
+}
+
+// ConductorFromContext returns the ID of the conductor the electron being
+// processed by an atom was received from. The ID is set on the context
+// passed to the Process method of the atom for each electron.
+func ConductorFromContext(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+
+	id, ok := ctx.Value(conductorKey{}).(string)
+	return id, ok
+}
@@ -0,0 +1,86 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// conductoratom returns the ID of the conductor from its context
+type conductoratom struct{}
+
+func (*conductoratom) Process(
+	ctx context.Context,
+	conductor Conductor,
+	electron *Electron,
+) ([]byte, error) {
+	id, ok := ConductorFromContext(ctx)
+	if !ok {
+		return nil, errors.New("conductor not in context")
+	}
+
+	return []byte(id), nil
+}
+
+// altrecorder is a recorder with a distinct conductor ID
+type altrecorder struct {
+	*recorder
+}
+
+func TestConductorFromContext(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	rec := newRecorder()
+	alt := &altrecorder{newRecorder()}
+
+	a, err := Atomize(ctx, rec, alt, &conductoratom{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = a.Exec(); err != nil {
+		t.Fatal(err)
+	}
+
+	total := 10
+	for _, c := range []Conductor{rec, alt} {
+		go func(c Conductor) {
+			for i := 0; i < total; i++ {
+				e := newElectron(ID(conductoratom{}), nil)
+				if _, err := c.Send(ctx, e); err != nil {
+					return
+				}
+			}
+		}(c)
+	}
+
+	for _, r := range []*recorder{rec, alt.recorder} {
+		expected := ID(rec)
+		if r == alt.recorder {
+			expected = ID(alt)
+		}
+
+		for i := 0; i < total; i++ {
+			p := r.next(ctx, t)
+			if p.Error != nil {
+				t.Fatal(p.Error)
+			}
+
+			if string(p.Result) != expected {
+				t.Fatalf("expected %s, got %s", expected, p.Result)
+			}
+		}
+	}
+
+	if _, ok := ConductorFromContext(context.Background()); ok {
+		t.Fatal("expected no conductor outside of atom execution")
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+//go:build nats
+// +build nats
+
+package nats
+
+import (
+	"encoding/json"
+	"sync"
+
+	engine "atomizer.io/engine"
+	natsgo "github.com/nats-io/nats.go"
+)
+
+func init() {
+	_ = engine.RegisterConductorFactory(
+		"nats",
+		func(cfg json.RawMessage) (engine.Conductor, error) {
+			c := struct {
+				URL     string `json:"url"`
+				Subject string `json:"subject"`
+				Queue   string `json:"queue"`
+				Results string `json:"results"`
+			}{URL: natsgo.DefaultURL}
+
+			if len(cfg) > 0 {
+				if err := json.Unmarshal(cfg, &c); err != nil {
+					return nil, err
+				}
+			}
+
+			conn, err := Connect(c.URL)
+			if err != nil {
+				return nil, err
+			}
+
+			var opts []Option
+			if c.Queue != "" {
+				opts = append(opts, Queue(c.Queue))
+			}
+
+			if c.Results != "" {
+				opts = append(opts, Results(c.Results))
+			}
+
+			return New(conn, c.Subject, opts...), nil
+		},
+	)
+}
+
+// client adapts a nats.go connection to Conn
+type client struct {
+	nc *natsgo.Conn
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+// Connect connects to the NATS server at the url, reconnecting without
+// limit unless the options say otherwise. The Closed channel of the
+// returned connection is closed once the connection is permanently lost.
+func Connect(url string, opts ...natsgo.Option) (Conn, error) {
+	c := &client{closed: make(chan struct{})}
+
+	opts = append(
+		[]natsgo.Option{natsgo.MaxReconnects(-1)},
+		append(opts, natsgo.ClosedHandler(func(*natsgo.Conn) {
+			c.closeOnce.Do(func() {
+				close(c.closed)
+			})
+		}))...,
+	)
+
+	nc, err := natsgo.Connect(url, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	c.nc = nc
+	return c, nil
+}
+
+// Subscribe subscribes the handler to the subject
+func (c *client) Subscribe(
+	subject string,
+	handler func(*Msg),
+) (Subscription, error) {
+	return c.nc.Subscribe(subject, adapt(handler))
+}
+
+// QueueSubscribe subscribes the handler to the subject as a member of
+// the queue group
+func (c *client) QueueSubscribe(
+	subject, queue string,
+	handler func(*Msg),
+) (Subscription, error) {
+	return c.nc.QueueSubscribe(subject, queue, adapt(handler))
+}
+
+// Publish publishes the data to the subject
+func (c *client) Publish(subject string, data []byte) error {
+	return c.nc.Publish(subject, data)
+}
+
+// PublishRequest publishes the data to the subject with the reply subject
+func (c *client) PublishRequest(subject, reply string, data []byte) error {
+	return c.nc.PublishRequest(subject, reply, data)
+}
+
+// Closed is closed once the connection is permanently lost
+func (c *client) Closed() <-chan struct{} {
+	return c.closed
+}
+
+func adapt(handler func(*Msg)) natsgo.MsgHandler {
+	return func(msg *natsgo.Msg) {
+		handler(&Msg{
+			Subject: msg.Subject,
+			Reply:   msg.Reply,
+			Data:    msg.Data,
+		})
+	}
+}
@@ -0,0 +1,11 @@
+module atomizer.io/engine/conductors/nats
+
+go 1.16
+
+require (
+	atomizer.io/engine v0.0.0-00010101000000-000000000000
+	github.com/nats-io/nats.go v1.11.0
+	golang.org/x/crypto v0.0.0-20211117183948-ae814b36b871 // indirect
+)
+
+replace atomizer.io/engine => ../../
@@ -0,0 +1,68 @@
+//go:build nats
+// +build nats
+
+package nats
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	engine "atomizer.io/engine"
+)
+
+// TestConductor_Integration runs against the NATS server at NATS_URL,
+// defaulting to a local server, e.g. `docker run -p 4222:4222 nats`
+// followed by `go test -tags nats ./conductors/nats`
+func TestConductor_Integration(t *testing.T) {
+	url := os.Getenv("NATS_URL")
+	if url == "" {
+		url = "nats://127.0.0.1:4222"
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	conn, err := Connect(url)
+	if err != nil {
+		t.Fatalf("unable to connect to %s: %v", url, err)
+	}
+
+	node := New(conn, "atomizer.integration", Queue("atomizer"))
+	defer node.Close()
+
+	electrons := node.Receive(ctx)
+
+	// Allow the subscription to reach the server before sending
+	time.Sleep(time.Millisecond * 100)
+
+	sender := New(conn, "atomizer.integration")
+	results, err := sender.Send(ctx, electron("integration"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := next(t, electrons)
+	err = node.Complete(ctx, &engine.Properties{
+		ElectronID: e.ID,
+		AtomID:     e.AtomID,
+		Result:     []byte(`{"result":"integration"}`),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case p, ok := <-results:
+		if !ok {
+			t.Fatal("results closed without properties")
+		}
+
+		if p.ElectronID != "integration" {
+			t.Fatalf("unexpected properties %+v", p)
+		}
+	case <-ctx.Done():
+		t.Fatal("expected properties")
+	}
+}
@@ -0,0 +1,268 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+// Package nats provides a conductor which receives electrons from a NATS
+// subject and publishes the properties of completed electrons back to the
+// reply subject of each electron. Subscribing through a queue group load
+// balances the electrons across every atomizer node in the group.
+//
+// The conductor is written against the Conn interface so it does not
+// depend on a NATS client directly. Building with the nats tag adds
+// Connect, which adapts a github.com/nats-io/nats.go connection, and the
+// "nats" conductor factory. The package is its own module so the NATS
+// client is not a dependency of the engine.
+package nats
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	engine "atomizer.io/engine"
+	"github.com/google/uuid"
+)
+
+// Msg is a message received from a NATS subject
+type Msg struct {
+	Subject string
+	Reply   string
+	Data    []byte
+}
+
+// Subscription is an active subscription to a NATS subject
+type Subscription interface {
+	Unsubscribe() error
+}
+
+// Conn is the NATS connection used by the conductor. Reconnects are
+// handled by the connection, Closed is closed once the connection is
+// permanently lost.
+type Conn interface {
+	Subscribe(subject string, handler func(*Msg)) (Subscription, error)
+	QueueSubscribe(
+		subject, queue string,
+		handler func(*Msg),
+	) (Subscription, error)
+	Publish(subject string, data []byte) error
+	PublishRequest(subject, reply string, data []byte) error
+	Closed() <-chan struct{}
+}
+
+// Option configures the NATS conductor
+type Option func(c *Conductor)
+
+// Queue subscribes to the electron subject through the queue group so
+// that the electrons are load balanced across the members of the group
+func Queue(group string) Option {
+	return func(c *Conductor) {
+		c.queue = group
+	}
+}
+
+// Results publishes the properties of electrons received without a reply
+// subject to the subject rather than dropping them
+func Results(subject string) Option {
+	return func(c *Conductor) {
+		c.results = subject
+	}
+}
+
+// Inbox sets the prefix of the reply subjects of the electrons sent
+// through the conductor, defaulting to "_INBOX"
+func Inbox(prefix string) Option {
+	return func(c *Conductor) {
+		c.inbox = prefix
+	}
+}
+
+// Conductor receives electrons from a NATS subject and publishes the
+// properties of completed electrons to their reply subjects
+type Conductor struct {
+	conn    Conn
+	subject string
+	queue   string
+	results string
+	inbox   string
+
+	// replies are the reply subjects of the received
+	// electrons keyed by electron ID
+	repliesMu sync.Mutex
+	replies   map[string]string
+
+	receiveOnce sync.Once
+	electrons   chan *engine.Electron
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+// New creates a NATS conductor receiving electrons from the subject
+func New(conn Conn, subject string, opts ...Option) *Conductor {
+	c := &Conductor{
+		conn:      conn,
+		subject:   subject,
+		inbox:     "_INBOX",
+		replies:   make(map[string]string),
+		electrons: make(chan *engine.Electron),
+		closed:    make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// Receive subscribes to the electron subject and pushes the electrons
+// onto the returned channel. Messages which are not valid electrons are
+// dropped. The channel is closed when the subscription fails, the
+// connection is lost, the conductor is closed or the context closes.
+func (c *Conductor) Receive(ctx context.Context) <-chan *engine.Electron {
+	c.receiveOnce.Do(func() {
+		go c.receive(ctx)
+	})
+
+	return c.electrons
+}
+
+func (c *Conductor) receive(ctx context.Context) {
+	defer close(c.electrons)
+
+	handler := func(msg *Msg) {
+		e := &engine.Electron{}
+		if err := json.Unmarshal(msg.Data, e); err != nil {
+			return
+		}
+
+		if msg.Reply != "" {
+			c.repliesMu.Lock()
+			c.replies[e.ID] = msg.Reply
+			c.repliesMu.Unlock()
+		}
+
+		// The handler blocks the subscription until the electron
+		// is received which applies back pressure to the server
+		select {
+		case <-ctx.Done():
+		case <-c.closed:
+		case <-c.conn.Closed():
+		case c.electrons <- e:
+		}
+	}
+
+	var sub Subscription
+	var err error
+	if c.queue != "" {
+		sub, err = c.conn.QueueSubscribe(c.subject, c.queue, handler)
+	} else {
+		sub, err = c.conn.Subscribe(c.subject, handler)
+	}
+
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = sub.Unsubscribe()
+	}()
+
+	select {
+	case <-ctx.Done():
+	case <-c.closed:
+	case <-c.conn.Closed():
+	}
+}
+
+// Complete publishes the properties to the reply subject of the electron,
+// or the results subject when the electron had no reply subject
+func (c *Conductor) Complete(
+	ctx context.Context,
+	p *engine.Properties,
+) error {
+	data, err := engine.MarshalCompletion(c, p)
+	if err != nil {
+		return err
+	}
+
+	c.repliesMu.Lock()
+	reply, ok := c.replies[p.ElectronID]
+	delete(c.replies, p.ElectronID)
+	c.repliesMu.Unlock()
+
+	if !ok {
+		reply = c.results
+	}
+
+	if reply == "" {
+		return nil
+	}
+
+	return c.conn.Publish(reply, data)
+}
+
+// Send publishes the electron to the electron subject and returns the
+// channel which receives the properties of the electron once completed.
+// The channel is closed without properties when the context closes, the
+// conductor is closed or the connection is lost first.
+func (c *Conductor) Send(
+	ctx context.Context,
+	electron *engine.Electron,
+) (<-chan *engine.Properties, error) {
+	data, err := json.Marshal(electron)
+	if err != nil {
+		return nil, err
+	}
+
+	reply := c.inbox + "." + uuid.New().String()
+
+	msgs := make(chan *Msg, 1)
+	sub, err := c.conn.Subscribe(reply, func(msg *Msg) {
+		select {
+		case msgs <- msg:
+		default:
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = c.conn.PublishRequest(c.subject, reply, data); err != nil {
+		_ = sub.Unsubscribe()
+		return nil, err
+	}
+
+	results := make(chan *engine.Properties, 1)
+	go func() {
+		defer close(results)
+		defer func() {
+			_ = sub.Unsubscribe()
+		}()
+
+		select {
+		case <-ctx.Done():
+		case <-c.closed:
+		case <-c.conn.Closed():
+		case msg := <-msgs:
+			p := &engine.Properties{}
+			if json.Unmarshal(msg.Data, p) == nil {
+				results <- p
+			}
+		}
+	}()
+
+	return results, nil
+}
+
+// Close stops receiving electrons, the connection is owned by the caller
+func (c *Conductor) Close() {
+	c.closeOnce.Do(func() {
+		close(c.closed)
+	})
+}
+
+// Validate ensures the conductor has a connection and subject
+func (c *Conductor) Validate() bool {
+	return c != nil && c.conn != nil && c.subject != ""
+}
@@ -0,0 +1,385 @@
+package nats
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	engine "atomizer.io/engine"
+)
+
+type fakesub struct {
+	conn    *fakeconn
+	subject string
+	queue   string
+	handler func(*Msg)
+}
+
+func (s *fakesub) Unsubscribe() error {
+	s.conn.mu.Lock()
+	defer s.conn.mu.Unlock()
+
+	subs := s.conn.subs[s.subject]
+	for i, sub := range subs {
+		if sub == s {
+			s.conn.subs[s.subject] = append(subs[:i], subs[i+1:]...)
+			break
+		}
+	}
+
+	return nil
+}
+
+// fakeconn is an in memory NATS connection which load balances the
+// messages of a queue group round robin across its subscribers
+type fakeconn struct {
+	mu     sync.Mutex
+	subs   map[string][]*fakesub
+	next   map[string]int
+	err    error
+	closed chan struct{}
+}
+
+func newConn() *fakeconn {
+	return &fakeconn{
+		subs:   make(map[string][]*fakesub),
+		next:   make(map[string]int),
+		closed: make(chan struct{}),
+	}
+}
+
+func (c *fakeconn) Subscribe(
+	subject string,
+	handler func(*Msg),
+) (Subscription, error) {
+	return c.QueueSubscribe(subject, "", handler)
+}
+
+func (c *fakeconn) QueueSubscribe(
+	subject, queue string,
+	handler func(*Msg),
+) (Subscription, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	sub := &fakesub{c, subject, queue, handler}
+	c.subs[subject] = append(c.subs[subject], sub)
+	return sub, nil
+}
+
+func (c *fakeconn) Publish(subject string, data []byte) error {
+	return c.PublishRequest(subject, "", data)
+}
+
+func (c *fakeconn) PublishRequest(subject, reply string, data []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	groups := make(map[string][]*fakesub)
+	for _, sub := range c.subs[subject] {
+		groups[sub.queue] = append(groups[sub.queue], sub)
+	}
+
+	msg := &Msg{Subject: subject, Reply: reply, Data: data}
+	for queue, subs := range groups {
+		if queue == "" {
+			for _, sub := range subs {
+				go sub.handler(msg)
+			}
+			continue
+		}
+
+		sub := subs[c.next[queue]%len(subs)]
+		c.next[queue]++
+		go sub.handler(msg)
+	}
+
+	return nil
+}
+
+func (c *fakeconn) Closed() <-chan struct{} {
+	return c.closed
+}
+
+func (c *fakeconn) subscribers(subject string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.subs[subject])
+}
+
+func (c *fakeconn) inboxes() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	count := 0
+	for subject, subs := range c.subs {
+		if strings.HasPrefix(subject, "_INBOX.") {
+			count += len(subs)
+		}
+	}
+
+	return count
+}
+
+func (c *fakeconn) wait(t *testing.T, subject string, count int) {
+	deadline := time.Now().Add(time.Second)
+	for c.subscribers(subject) != count {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %v subscribers of %s", count, subject)
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func electron(id string) *engine.Electron {
+	return &engine.Electron{
+		SenderID: "sender",
+		ID:       id,
+		AtomID:   "atom",
+		Payload:  []byte(`{"test":"test"}`),
+	}
+}
+
+func next(t *testing.T, electrons <-chan *engine.Electron) *engine.Electron {
+	select {
+	case e, ok := <-electrons:
+		if !ok {
+			t.Fatal("electron channel closed")
+		}
+		return e
+	case <-time.After(time.Second):
+		t.Fatal("expected electron")
+	}
+
+	return nil
+}
+
+func TestConductor_RoundTrip(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	conn := newConn()
+	c := New(conn, "electrons")
+	defer c.Close()
+
+	electrons := c.Receive(ctx)
+	conn.wait(t, "electrons", 1)
+
+	results, err := c.Send(ctx, electron("test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := next(t, electrons)
+	if e.ID != "test" || e.AtomID != "atom" {
+		t.Fatalf("unexpected electron %+v", e)
+	}
+
+	err = c.Complete(ctx, &engine.Properties{
+		ElectronID: e.ID,
+		AtomID:     e.AtomID,
+		Result:     []byte(`{"result":"test"}`),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case p, ok := <-results:
+		if !ok {
+			t.Fatal("results closed without properties")
+		}
+
+		if p.ElectronID != "test" ||
+			string(p.Result) != `{"result":"test"}` {
+			t.Fatalf("unexpected properties %+v", p)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected properties")
+	}
+
+	// The reply subscription is removed once the result is received
+	deadline := time.Now().Add(time.Second)
+	for conn.inboxes() != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the reply subscription to be removed")
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestConductor_Results(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	conn := newConn()
+	c := New(conn, "electrons", Results("results"))
+	defer c.Close()
+
+	got := make(chan *Msg, 1)
+	_, err := conn.Subscribe("results", func(msg *Msg) {
+		got <- msg
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = c.Complete(ctx, &engine.Properties{ElectronID: "unknown"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case msg := <-got:
+		p := &engine.Properties{}
+		if err = json.Unmarshal(msg.Data, p); err != nil {
+			t.Fatal(err)
+		}
+
+		if p.ElectronID != "unknown" {
+			t.Fatalf("unexpected properties %+v", p)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected result on the results subject")
+	}
+}
+
+func TestConductor_Queue(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	conn := newConn()
+	nodes := []*Conductor{
+		New(conn, "electrons", Queue("atomizer")),
+		New(conn, "electrons", Queue("atomizer")),
+	}
+
+	var channels []<-chan *engine.Electron
+	for _, node := range nodes {
+		defer node.Close()
+		channels = append(channels, node.Receive(ctx))
+	}
+	conn.wait(t, "electrons", len(nodes))
+
+	sender := New(conn, "electrons")
+	for i := 0; i < len(nodes)*2; i++ {
+		_, err := sender.Send(ctx, electron("test"))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Each node receives its share of the electrons and no more
+	for _, electrons := range channels {
+		next(t, electrons)
+		next(t, electrons)
+
+		select {
+		case e := <-electrons:
+			t.Fatalf("unexpected electron %+v", e)
+		case <-time.After(time.Millisecond * 50):
+		}
+	}
+}
+
+func TestConductor_Receive_Closed(t *testing.T) {
+	tests := []struct {
+		name  string
+		close func(cancel context.CancelFunc, c *Conductor, conn *fakeconn)
+	}{
+		{
+			"context",
+			func(cancel context.CancelFunc, _ *Conductor, _ *fakeconn) {
+				cancel()
+			},
+		},
+		{
+			"conductor",
+			func(_ context.CancelFunc, c *Conductor, _ *fakeconn) {
+				c.Close()
+			},
+		},
+		{
+			"connection",
+			func(_ context.CancelFunc, _ *Conductor, conn *fakeconn) {
+				close(conn.closed)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			conn := newConn()
+			c := New(conn, "electrons")
+			defer c.Close()
+
+			electrons := c.Receive(ctx)
+			conn.wait(t, "electrons", 1)
+
+			test.close(cancel, c, conn)
+
+			select {
+			case _, ok := <-electrons:
+				if ok {
+					t.Fatal("expected closed channel")
+				}
+			case <-time.After(time.Second):
+				t.Fatal("expected the electron channel to close")
+			}
+
+			conn.wait(t, "electrons", 0)
+		})
+	}
+}
+
+func TestConductor_Receive_SubscribeError(t *testing.T) {
+	conn := newConn()
+	conn.err = errors.New("not connected")
+
+	c := New(conn, "electrons")
+	defer c.Close()
+
+	select {
+	case _, ok := <-c.Receive(context.Background()):
+		if ok {
+			t.Fatal("expected closed channel")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the electron channel to close")
+	}
+}
+
+func TestConductor_Validate(t *testing.T) {
+	tests := []struct {
+		name  string
+		c     *Conductor
+		valid bool
+	}{
+		{"valid", New(newConn(), "electrons"), true},
+		{"nil", nil, false},
+		{"nil connection", New(nil, "electrons"), false},
+		{"empty subject", New(newConn(), ""), false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.c.Validate() != test.valid {
+				t.Fatalf("expected valid %v", test.valid)
+			}
+		})
+	}
+}
@@ -0,0 +1,348 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+// Package stream provides a debugging conductor which reads newline
+// delimited JSON electrons from a reader and writes the resulting
+// properties as JSON to a writer.
+package stream
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+
+	engine "atomizer.io/engine"
+)
+
+func init() {
+	_ = engine.RegisterConductorFactory(
+		"stream",
+		func(cfg json.RawMessage) (engine.Conductor, error) {
+			c := struct {
+				Pretty bool `json:"pretty"`
+			}{}
+
+			if len(cfg) > 0 {
+				if err := json.Unmarshal(cfg, &c); err != nil {
+					return nil, err
+				}
+			}
+
+			var opts []Option
+			if c.Pretty {
+				opts = append(opts, Pretty())
+			}
+
+			return New(os.Stdin, os.Stdout, opts...), nil
+		},
+	)
+}
+
+// Option configures the stream conductor
+type Option func(c *Conductor)
+
+// Pretty configures the conductor to write indented multi-line JSON
+// rather than the default compact single line JSON
+func Pretty() Option {
+	return func(c *Conductor) {
+		c.pretty = true
+	}
+}
+
+// Envelope configures the conductor to write the properties of completed
+// electrons in the format returned by the transform rather than the
+// standard properties JSON
+func Envelope(transform func(engine.Properties) ([]byte, error)) Option {
+	return func(c *Conductor) {
+		c.transform = transform
+	}
+}
+
+// Errors configures the function the conductor reports the frames of the
+// input stream which could not be decoded to. Invalid frames are skipped
+// without being reported by default.
+func Errors(report func(err error)) Option {
+	return func(c *Conductor) {
+		c.report = report
+	}
+}
+
+// FrameError is reported for each frame of the input stream which is not
+// a valid electron or batch of electrons. The frame is skipped.
+type FrameError struct {
+	// Frame is the position of the frame in the input stream
+	Frame int
+
+	// Err is the error decoding the frame
+	Err error
+}
+
+func (e *FrameError) Error() string {
+	return "invalid frame " + strconv.Itoa(e.Frame) + ": " + e.Err.Error()
+}
+
+// Unwrap returns the error decoding the frame
+func (e *FrameError) Unwrap() error {
+	return e.Err
+}
+
+// Conductor reads electrons from the input stream and writes the
+// properties of completed electrons to the output stream
+type Conductor struct {
+	in  io.Reader
+	out io.Writer
+
+	// pretty enables indented output
+	pretty bool
+
+	// transform serializes the completed properties
+	transform func(engine.Properties) ([]byte, error)
+
+	// report receives the errors of invalid frames
+	report func(err error)
+
+	outMu sync.Mutex
+
+	// receiveMu serializes the decoding of the input stream across
+	// calls to Receive. The decoder and the electrons decoded but not
+	// received before the context of a Receive closed are kept for the
+	// next Receive.
+	receiveMu sync.Mutex
+	dec       *json.Decoder
+	frames    int
+	pending   []*engine.Electron
+}
+
+// New creates a stream conductor reading electrons from in and writing
+// completed properties to out
+func New(in io.Reader, out io.Writer, opts ...Option) *Conductor {
+	c := &Conductor{
+		in:  in,
+		out: out,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// Receive decodes the electrons from the input stream. Each JSON value
+// in the stream is either a single electron or a batch frame of electrons
+// which are pushed onto the channel individually. Frames which are not
+// valid are skipped along with the rest of their line and reported when
+// configured (see Errors). The returned channel is closed when the input stream is exhausted
+// or fails to be read, or the context closes. Receive may be called again
+// once the context of the previous call closes to continue reading the
+// input stream, such as when the conductor is registered again.
+func (c *Conductor) Receive(ctx context.Context) <-chan *engine.Electron {
+	electrons := make(chan *engine.Electron)
+	go c.decode(ctx, electrons)
+
+	return electrons
+}
+
+func (c *Conductor) decode(ctx context.Context, electrons chan<- *engine.Electron) {
+	defer close(electrons)
+
+	c.receiveMu.Lock()
+	defer c.receiveMu.Unlock()
+
+	if c.dec == nil {
+		c.dec = json.NewDecoder(c.in)
+	}
+
+	for {
+		for len(c.pending) > 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case electrons <- c.pending[0]:
+				c.pending[0] = nil
+				c.pending = c.pending[1:]
+			}
+		}
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		var frame json.RawMessage
+		err := c.dec.Decode(&frame)
+		if err == io.EOF {
+			return
+		}
+
+		c.frames++
+
+		var syntax *json.SyntaxError
+		if errors.As(err, &syntax) {
+			c.invalid(err)
+
+			if !c.skip() {
+				return
+			}
+
+			continue
+		}
+
+		if err != nil {
+			c.invalid(err)
+			return
+		}
+
+		c.pending, err = unpack(frame)
+		if err != nil {
+			c.invalid(err)
+		}
+	}
+}
+
+// invalid reports the error of the current frame
+func (c *Conductor) invalid(err error) {
+	if c.report != nil {
+		c.report(&FrameError{Frame: c.frames, Err: err})
+	}
+}
+
+// skip discards the rest of the line of an invalid frame and resumes
+// decoding after it, returning false once the input stream is exhausted
+func (c *Conductor) skip() bool {
+	r := bufio.NewReader(io.MultiReader(c.dec.Buffered(), c.in))
+
+	// The invalid frame starts at the first non-space byte
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return false
+		}
+
+		if b != ' ' && b != '\t' && b != '\r' && b != '\n' {
+			break
+		}
+	}
+
+	if _, err := r.ReadBytes('\n'); err != nil {
+		return false
+	}
+
+	c.dec = json.NewDecoder(r)
+
+	return true
+}
+
+// unpack decodes a frame holding either a single electron or a batch
+// of electrons (see engine.MarshalBatch)
+func unpack(frame json.RawMessage) ([]*engine.Electron, error) {
+	trimmed := bytes.TrimSpace(frame)
+	if len(trimmed) == 0 || trimmed[0] != '[' {
+		e := &engine.Electron{}
+		if err := json.Unmarshal(frame, e); err != nil {
+			return nil, err
+		}
+
+		return []*engine.Electron{e}, nil
+	}
+
+	batch, err := engine.UnmarshalBatch(trimmed)
+	if err != nil {
+		return nil, err
+	}
+
+	electrons := make([]*engine.Electron, 0, len(batch))
+	for i := range batch {
+		electrons = append(electrons, &batch[i])
+	}
+
+	return electrons, nil
+}
+
+// Complete writes the properties to the output stream
+func (c *Conductor) Complete(
+	ctx context.Context,
+	p *engine.Properties,
+) error {
+	data, err := engine.MarshalCompletion(c, p)
+	if err != nil {
+		return err
+	}
+
+	return c.writeLine(data)
+}
+
+// Transform serializes the properties using the envelope of the conductor
+// when configured and as properties JSON otherwise
+func (c *Conductor) Transform(p engine.Properties) ([]byte, error) {
+	if c.transform != nil {
+		return c.transform(p)
+	}
+
+	return c.marshal(&p)
+}
+
+// Send writes the electron to the output stream. Results are not
+// available for electrons sent through a stream conductor so the
+// returned channel is always nil
+func (c *Conductor) Send(
+	ctx context.Context,
+	electron *engine.Electron,
+) (<-chan *engine.Properties, error) {
+	return nil, c.write(electron)
+}
+
+// SendBatch writes the electrons to the output stream as a single batch
+// frame which is unpacked into the individual electrons when received
+func (c *Conductor) SendBatch(
+	ctx context.Context,
+	electrons []engine.Electron,
+) error {
+	data, err := engine.MarshalBatch(electrons)
+	if err != nil {
+		return err
+	}
+
+	return c.write(json.RawMessage(data))
+}
+
+func (c *Conductor) write(v interface{}) error {
+	data, err := c.marshal(v)
+	if err != nil {
+		return err
+	}
+
+	return c.writeLine(data)
+}
+
+func (c *Conductor) marshal(v interface{}) ([]byte, error) {
+	if c.pretty {
+		return json.MarshalIndent(v, "", "  ")
+	}
+
+	return json.Marshal(v)
+}
+
+func (c *Conductor) writeLine(data []byte) error {
+	c.outMu.Lock()
+	defer c.outMu.Unlock()
+
+	_, err := c.out.Write(append(data, '\n'))
+	return err
+}
+
+// Close is a no-op for the stream conductor since the streams are
+// owned by the caller
+func (c *Conductor) Close() {}
+
+// Validate ensures the conductor has both streams
+func (c *Conductor) Validate() bool {
+	return c != nil && c.in != nil && c.out != nil
+}
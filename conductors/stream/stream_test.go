@@ -0,0 +1,355 @@
+package stream
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	engine "atomizer.io/engine"
+)
+
+var props = &engine.Properties{
+	ElectronID: "test",
+	AtomID:     "test",
+	Start:      time.Time{},
+	End:        time.Time{},
+	Result:     []byte(`{"result":"test"}`),
+}
+
+func TestConductor_Complete(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  []Option
+		lines int
+	}{
+		{"compact", nil, 1},
+		{"pretty", []Option{Pretty()}, 8},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			c := New(strings.NewReader(""), out, test.opts...)
+
+			err := c.Complete(context.Background(), props)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+			if test.lines == 1 && len(lines) != 1 {
+				t.Fatalf("expected single line, got %v", len(lines))
+			}
+
+			if test.lines > 1 && len(lines) < test.lines {
+				t.Fatalf("expected multi-line output, got %s", out)
+			}
+
+			if test.lines > 1 && !strings.HasPrefix(lines[1], "  ") {
+				t.Fatalf("expected indented output, got %s", out)
+			}
+		})
+	}
+}
+
+func TestConductor_Receive(t *testing.T) {
+	in := strings.NewReader(
+		`{"senderid":"s","id":"1","atomid":"a"}` + "\n" +
+			`{"senderid":"s","id":"2","atomid":"a"}` + "\n",
+	)
+
+	c := New(in, &bytes.Buffer{})
+
+	var ids []string
+	for e := range c.Receive(context.Background()) {
+		ids = append(ids, e.ID)
+	}
+
+	if strings.Join(ids, ",") != "1,2" {
+		t.Fatalf("expected electrons 1,2 got %v", ids)
+	}
+}
+
+func TestConductor_Receive_Invalid(t *testing.T) {
+	in := strings.NewReader(
+		`{"senderid":"s","id":"1","atomid":"a"}` + "\n" +
+			`{"senderid":"s", oops}` + "\n" +
+			`"not an electron"` + "\n" +
+			`{"senderid":"s","id":"2","atomid":"a"}` + "\n",
+	)
+
+	var errs []error
+	c := New(in, &bytes.Buffer{}, Errors(func(err error) {
+		errs = append(errs, err)
+	}))
+
+	var ids []string
+	for e := range c.Receive(context.Background()) {
+		ids = append(ids, e.ID)
+	}
+
+	if strings.Join(ids, ",") != "1,2" {
+		t.Fatalf("expected electrons 1,2 got %v", ids)
+	}
+
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 frame errors, got %v", errs)
+	}
+
+	for i, frame := range []int{2, 3} {
+		var fe *FrameError
+		if !errors.As(errs[i], &fe) || fe.Frame != frame {
+			t.Fatalf("expected error for frame %v, got %v", frame, errs[i])
+		}
+	}
+}
+
+func TestConductor_Receive_Invalid_Unreported(t *testing.T) {
+	in := strings.NewReader(
+		`{"senderid":"s", oops}` + "\n" +
+			`{"senderid":"s","id":"1","atomid":"a"}` + "\n",
+	)
+
+	// Invalid frames are skipped without a configured report
+	var ids []string
+	for e := range New(in, &bytes.Buffer{}).Receive(context.Background()) {
+		ids = append(ids, e.ID)
+	}
+
+	if strings.Join(ids, ",") != "1" {
+		t.Fatalf("expected electron 1 got %v", ids)
+	}
+}
+
+func TestConductor_Receive_Again(t *testing.T) {
+	r, w := io.Pipe()
+	defer func() {
+		_ = w.Close()
+	}()
+
+	c := New(r, &bytes.Buffer{})
+
+	write := func(id string) {
+		go func() {
+			_, _ = w.Write([]byte(`{"senderid":"s","id":"` + id + `","atomid":"a"}` + "\n"))
+		}()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	electrons := c.Receive(ctx)
+
+	write("1")
+	if e := <-electrons; e.ID != "1" {
+		t.Fatalf("expected electron 1, got %s", e.ID)
+	}
+
+	// The electron decoded after the context closed
+	// is received by the next call to Receive
+	cancel()
+	write("2")
+
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	select {
+	case <-ctx.Done():
+		t.Fatal("expected electron 2")
+	case e, ok := <-c.Receive(ctx):
+		if !ok || e.ID != "2" {
+			t.Fatalf("expected electron 2, got %+v", e)
+		}
+	}
+}
+
+func TestConductor_Factory(t *testing.T) {
+	conductors, err := engine.BuildConductors(
+		[]byte(`[{"name":"stream","config":{"pretty":true}}]`),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, ok := conductors[0].(*Conductor)
+	if !ok || !c.pretty {
+		t.Fatal("expected pretty stream conductor")
+	}
+}
+
+// lockedBuffer is a buffer which is safe for concurrent use
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) lines() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return strings.Split(strings.TrimSpace(b.buf.String()), "\n")
+}
+
+type echoatom struct{}
+
+func (*echoatom) Process(
+	ctx context.Context,
+	conductor engine.Conductor,
+	electron *engine.Electron,
+) ([]byte, error) {
+	return electron.Payload, nil
+}
+
+func TestConductor_Receive_Batch(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	atomID := engine.ID(echoatom{})
+	electrons := []engine.Electron{
+		{SenderID: "s", ID: "1", AtomID: atomID, Payload: []byte(`{"n":1}`)},
+		{SenderID: "s", ID: "2", AtomID: atomID, Payload: []byte(`{"n":2}`)},
+		{SenderID: "s", ID: "3", AtomID: atomID, Payload: []byte(`{"n":3}`)},
+	}
+
+	frame := &bytes.Buffer{}
+	if err := New(nil, frame).SendBatch(ctx, electrons); err != nil {
+		t.Fatal(err)
+	}
+
+	if lines := strings.Split(strings.TrimSpace(frame.String()), "\n"); len(lines) != 1 {
+		t.Fatalf("expected a single batch frame, got %v lines", len(lines))
+	}
+
+	out := &lockedBuffer{}
+	c := New(frame, out)
+
+	a, err := engine.Atomize(ctx, c, &echoatom{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = a.Exec(); err != nil {
+		t.Fatal(err)
+	}
+
+	results := make(map[string]string)
+	for len(results) < len(electrons) {
+		select {
+		case <-ctx.Done():
+			t.Fatalf("expected %v completions, got %v", len(electrons), results)
+		case <-time.After(time.Millisecond * 10):
+		}
+
+		for _, line := range out.lines() {
+			if line == "" {
+				continue
+			}
+
+			p := &engine.Properties{}
+			if err = json.Unmarshal([]byte(line), p); err != nil {
+				t.Fatal(err)
+			}
+
+			if p.Error != nil {
+				t.Fatalf("unexpected error for %s: %v", p.ElectronID, p.Error)
+			}
+
+			results[p.ElectronID] = string(p.Result)
+		}
+	}
+
+	for _, e := range electrons {
+		if results[e.ID] != string(e.Payload) {
+			t.Fatalf("expected %s for %s, got %s", e.Payload, e.ID, results[e.ID])
+		}
+	}
+}
+
+func TestConductor_Complete_Correlation(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	e := &engine.Electron{
+		SenderID:      "s",
+		ID:            "request",
+		AtomID:        engine.ID(echoatom{}),
+		CorrelationID: "workflow",
+		Payload:       []byte(`{}`),
+	}
+
+	frame := &bytes.Buffer{}
+	if _, err := New(nil, frame).Send(ctx, e); err != nil {
+		t.Fatal(err)
+	}
+
+	out := &lockedBuffer{}
+	a, err := engine.Atomize(ctx, New(frame, out), &echoatom{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = a.Exec(); err != nil {
+		t.Fatal(err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			t.Fatal("expected completion")
+		case <-time.After(time.Millisecond * 10):
+		}
+
+		line := out.lines()[0]
+		if line == "" {
+			continue
+		}
+
+		p := &engine.Properties{}
+		if err = json.Unmarshal([]byte(line), p); err != nil {
+			t.Fatal(err)
+		}
+
+		if p.ElectronID != e.ID || p.CorrelationID != e.CorrelationID {
+			t.Fatalf("unexpected ids %s/%s", p.ElectronID, p.CorrelationID)
+		}
+
+		if id := engine.CorrelateResult(p); id != e.CorrelationID {
+			t.Fatalf("expected request %s, got %s", e.CorrelationID, id)
+		}
+
+		return
+	}
+}
+
+func TestConductor_Complete_Envelope(t *testing.T) {
+	legacy := func(p engine.Properties) ([]byte, error) {
+		return json.Marshal(map[string]interface{}{
+			"request": p.ElectronID,
+			"ok":      p.Error == nil,
+			"data":    json.RawMessage(p.Result),
+		})
+	}
+
+	out := &bytes.Buffer{}
+	c := New(strings.NewReader(""), out, Envelope(legacy))
+
+	if err := c.Complete(context.Background(), props); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"data":{"result":"test"},"ok":true,"request":"test"}`
+	if got := strings.TrimSpace(out.String()); got != expected {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+}
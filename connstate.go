@@ -0,0 +1,63 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+// ConnState is the connection state of a conductor
+type ConnState string
+
+const (
+	// ConnConnected indicates the atomizer is receiving
+	// electrons from the conductor
+	ConnConnected ConnState = "connected"
+
+	// ConnReconnecting indicates the conductor is being re-registered
+	// to re-establish its receiver (see WithHealthChecks)
+	ConnReconnecting ConnState = "reconnecting"
+
+	// ConnFailed indicates the receiver of the conductor closed or
+	// the conductor could not be re-registered
+	ConnFailed ConnState = "failed"
+)
+
+// WithConductorStateCallback invokes the callback each time a conductor
+// transitions between connection states. The callback is invoked on the
+// routine of the transition so it should return quickly. Panics in the
+// callback are recovered and emitted as errors.
+func WithConductorStateCallback(
+	callback func(conductorID string, state ConnState),
+) Option {
+	return func(a *atomizer) error {
+		if callback == nil {
+			return simple("nil conductor state callback", nil)
+		}
+
+		a.connState = callback
+		return nil
+	}
+}
+
+// transition invokes the conductor state callback
+func (a *atomizer) transition(conductorID string, state ConnState) {
+	if a.connState == nil {
+		return
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			a.err(func() error {
+				return &Error{
+					Event: &Event{
+						Message:     "panic in conductor state callback",
+						ConductorID: conductorID,
+					},
+					Internal: ptoe(r),
+				}
+			})
+		}
+	}()
+
+	a.connState(conductorID, state)
+}
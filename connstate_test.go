@@ -0,0 +1,96 @@
+package engine
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// closingconductor is a healthy conductor whose first receiver closes
+type closingconductor struct {
+	*recorder
+	first     chan *Electron
+	receivers int32
+}
+
+func (c *closingconductor) Receive(ctx context.Context) <-chan *Electron {
+	if atomic.AddInt32(&c.receivers, 1) == 1 {
+		return c.first
+	}
+
+	return c.recorder.Receive(ctx)
+}
+
+func (c *closingconductor) Ping(ctx context.Context) error {
+	return nil
+}
+
+func TestAtomizer_ConductorStateCallback(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	closing := &closingconductor{
+		recorder: newRecorder(),
+		first:    make(chan *Electron),
+	}
+
+	states := make(chan ConnState, 10)
+	_, a := recHarness(
+		ctx,
+		t,
+		WithHealthChecks(time.Hour, true),
+		WithConductorStateCallback(func(id string, state ConnState) {
+			if id == ID(closing) {
+				states <- state
+			}
+
+			panic("callback panics are isolated")
+		}),
+		closing,
+		&noopatom{},
+	)
+
+	eventually(t, time.Second, func() bool {
+		return a.route(newElectron(ID(noopatom{}), nil)) != nil
+	})
+
+	expect := func(expected ConnState) {
+		select {
+		case <-ctx.Done():
+			t.Fatalf("expected state %s", expected)
+		case state := <-states:
+			if state != expected {
+				t.Fatalf("expected state %s, got %s", expected, state)
+			}
+		}
+	}
+
+	expect(ConnConnected)
+
+	close(closing.first)
+
+	expect(ConnReconnecting)
+	expect(ConnConnected)
+
+	e := newElectron(ID(noopatom{}), nil)
+	if _, err := closing.Send(ctx, e); err != nil {
+		t.Fatal(err)
+	}
+
+	if p := closing.next(ctx, t); p.ElectronID != e.ID {
+		t.Fatalf("expected %s, got %s", e.ID, p.ElectronID)
+	}
+}
+
+func TestWithConductorStateCallback_Nil(t *testing.T) {
+	if err := WithConductorStateCallback(nil)(&atomizer{}); err == nil {
+		t.Fatal("expected error")
+	}
+}
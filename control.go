@@ -0,0 +1,119 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import "context"
+
+// Action is the operation of a control command
+type Action string
+
+const (
+	// EnableAtom resumes the routing of electrons to a
+	// previously disabled atom
+	EnableAtom Action = "enable"
+
+	// DisableAtom stops the routing of electrons to an atom
+	// while keeping its registration so it can be enabled again
+	DisableAtom Action = "disable"
+)
+
+// Command is the envelope for a control command pushed to the atomizer
+// by a Controller. Commands can only enable or disable atoms which were
+// already registered with the atomizer, they cannot register new code.
+type Command struct {
+	Action Action `json:"action"`
+	AtomID string `json:"atomID"`
+}
+
+// Validate determines if the command is valid
+func (c *Command) Validate() bool {
+	if c == nil || c.AtomID == "" {
+		return false
+	}
+
+	return c.Action == EnableAtom || c.Action == DisableAtom
+}
+
+// Controller is implemented by conductors which act as a control plane
+// for the atomizer. The commands received from the control channel are
+// interpreted by the atomizer registration loop, separate from the
+// electrons received by the conductor, dynamically reconfiguring which
+// of the registered atoms run on this node.
+type Controller interface {
+	Control(ctx context.Context) <-chan *Command
+}
+
+// control reads the commands from a controller and passes them to the
+// registration loop of the atomizer
+func (a *atomizer) control(ctx context.Context, c Controller) {
+	commands := c.Control(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case cmd, ok := <-commands:
+			if !ok {
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case a.registrations <- cmd:
+			}
+		}
+	}
+}
+
+// command applies a control command to the registered atoms
+func (a *atomizer) command(cmd *Command) error {
+	a.atomsMu.Lock()
+	defer a.atomsMu.Unlock()
+
+	if a.disabled == nil {
+		a.disabled = make(map[string]*replicas)
+	}
+
+	from, to := a.atoms, a.disabled
+	if cmd.Action == EnableAtom {
+		from, to = a.disabled, a.atoms
+	}
+
+	reps, ok := from[cmd.AtomID]
+	if !ok {
+		if _, ok = to[cmd.AtomID]; ok {
+			// The atom is already in the requested state
+			return nil
+		}
+
+		return &Error{Event: &Event{
+			Message: "control command for unregistered atom",
+			AtomID:  cmd.AtomID,
+		}}
+	}
+
+	// The processing loops of the replicas are kept running so
+	// that enabling the atom again does not need to restart them
+	delete(from, cmd.AtomID)
+	to[cmd.AtomID] = reps
+	a.rebuild()
+
+	if cmd.Action == EnableAtom {
+		delete(a.quarantined, cmd.AtomID)
+	}
+
+	return nil
+}
+
+// isDisabled indicates if the atom was disabled by a control command
+func (a *atomizer) isDisabled(atomID string) bool {
+	a.atomsMu.RLock()
+	defer a.atomsMu.RUnlock()
+
+	_, ok := a.disabled[atomID]
+	return ok
+}
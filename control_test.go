@@ -0,0 +1,110 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// controlrecorder is a recorder which acts as a control plane
+type controlrecorder struct {
+	*recorder
+	commands chan *Command
+}
+
+func (c *controlrecorder) Control(ctx context.Context) <-chan *Command {
+	return c.commands
+}
+
+func TestAtomizer_Control(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	rec := &controlrecorder{
+		recorder: newRecorder(),
+		commands: make(chan *Command),
+	}
+
+	mizer, err := Atomize(ctx, rec, &noopatom{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, _ := mizer.(*atomizer)
+	if err = a.Exec(); err != nil {
+		t.Fatal(err)
+	}
+
+	eventually(t, time.Second, func() bool {
+		return a.route(newElectron(ID(noopatom{}), nil)) != nil
+	})
+
+	rec.commands <- &Command{Action: DisableAtom, AtomID: ID(noopatom{})}
+	eventually(t, time.Second, func() bool {
+		return a.isDisabled(ID(noopatom{}))
+	})
+
+	_, err = rec.Send(ctx, newElectron(ID(noopatom{}), nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if p := rec.next(ctx, t); p.Error == nil {
+		t.Fatal("expected disabled atom error")
+	}
+
+	rec.commands <- &Command{Action: EnableAtom, AtomID: ID(noopatom{})}
+	eventually(t, time.Second, func() bool {
+		return !a.isDisabled(ID(noopatom{}))
+	})
+
+	_, err = rec.Send(ctx, newElectron(ID(noopatom{}), nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if p := rec.next(ctx, t); p.Error != nil {
+		t.Fatalf("expected success, got %s", p.Error)
+	}
+}
+
+func TestAtomizer_Control_Unregistered(t *testing.T) {
+	a := &atomizer{atoms: make(map[string]*replicas)}
+
+	tests := map[string]*Command{
+		"unregistered enable": {
+			Action: EnableAtom,
+			AtomID: "unknown",
+		},
+		"unregistered disable": {
+			Action: DisableAtom,
+			AtomID: "unknown",
+		},
+	}
+
+	for name, cmd := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := a.command(cmd); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+
+	invalid := []*Command{
+		nil,
+		{Action: EnableAtom},
+		{Action: "register", AtomID: "atom"},
+	}
+
+	for _, cmd := range invalid {
+		if cmd.Validate() {
+			t.Fatalf("expected invalid command %v", cmd)
+		}
+	}
+}
@@ -0,0 +1,284 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DeadLetter is an electron which failed processing along with
+// the failure information
+type DeadLetter struct {
+	// Electron is the original electron as received by the atomizer
+	Electron *Electron
+
+	// ConductorID is the ID of the conductor the electron was
+	// received from
+	ConductorID string
+
+	// Error is the failure returned from processing the electron
+	Error error
+
+	// Time is when the electron failed processing
+	Time time.Time
+}
+
+// DeadLetterFilter selects dead letters. Empty fields match all
+// dead letters
+type DeadLetterFilter struct {
+	// AtomID matches dead letters for the atom
+	AtomID string
+
+	// Error matches dead letters whose failure satisfies the func
+	// (i.e. using errors.Is or errors.As)
+	Error func(err error) bool
+
+	// Since matches dead letters which failed at or after the time
+	Since time.Time
+
+	// Until matches dead letters which failed before the time
+	Until time.Time
+}
+
+// Match determines if the dead letter is selected by the filter
+func (f DeadLetterFilter) Match(dl *DeadLetter) bool {
+	if dl == nil || dl.Electron == nil {
+		return false
+	}
+
+	if f.AtomID != "" && f.AtomID != dl.Electron.AtomID {
+		return false
+	}
+
+	if f.Error != nil && !f.Error(dl.Error) {
+		return false
+	}
+
+	if !f.Since.IsZero() && dl.Time.Before(f.Since) {
+		return false
+	}
+
+	if !f.Until.IsZero() && !dl.Time.Before(f.Until) {
+		return false
+	}
+
+	return true
+}
+
+// DeadLetterStore stores the electrons which failed processing so they
+// can be reprocessed once the cause of the failure is resolved
+type DeadLetterStore interface {
+	// Add stores a failed electron
+	Add(ctx context.Context, dl *DeadLetter) error
+
+	// Take removes and returns the dead letters matching the filter
+	Take(ctx context.Context, filter DeadLetterFilter) ([]*DeadLetter, error)
+}
+
+// WithDeadLetter configures the atomizer to store electrons which fail
+// processing in the dead letter store
+func WithDeadLetter(store DeadLetterStore) Option {
+	return func(a *atomizer) error {
+		if store == nil {
+			return simple("nil dead letter store", nil)
+		}
+
+		a.deadletters = store
+		return nil
+	}
+}
+
+// deadletter stores the instance in the dead letter store if it failed
+func (a *atomizer) deadletter(inst *instance, err error) {
+	if a.deadletters == nil || inst.electron == nil {
+		return
+	}
+
+	if err == nil && inst.properties != nil {
+		err = inst.properties.Error
+	}
+
+	if err == nil {
+		return
+	}
+
+	dlerr := a.deadletters.Add(a.ctx, &DeadLetter{
+		Electron:    inst.electron,
+		ConductorID: ID(inst.conductor),
+		Error:       err,
+		Time:        time.Now(),
+	})
+	if dlerr != nil {
+		a.err(func() error {
+			return &Error{
+				Event: &Event{
+					Message:     "error storing dead letter",
+					ElectronID:  inst.electron.ID,
+					AtomID:      inst.electron.AtomID,
+					ConductorID: ID(inst.conductor),
+				},
+				Internal: dlerr,
+			}
+		})
+	}
+}
+
+// Reprocess takes the dead letters matching the filter from the dead
+// letter store and re-submits the original electrons for processing
+// through the conductor they were originally received from. The electrons
+// are queued for distribution the same as electrons received from the
+// conductor. The number of electrons re-submitted is returned, the dead
+// letters which are not re-submitted before either context closes are
+// returned to the store.
+func (a *atomizer) Reprocess(
+	ctx context.Context,
+	filter DeadLetterFilter,
+) (int, error) {
+	if a.deadletters == nil {
+		return 0, simple("dead letter store not configured", nil)
+	}
+
+	if ctx == nil {
+		ctx = a.ctx
+	}
+
+	dls, err := a.deadletters.Take(ctx, filter)
+	if err != nil {
+		return 0, simple("error taking dead letters", err)
+	}
+
+	var count int
+	for i, dl := range dls {
+		if ctx.Err() != nil || a.ctx.Err() != nil {
+			a.restore(dls[i:])
+			return count, closed(ctx)
+		}
+
+		a.conductorsMu.RLock()
+		conductor, ok := a.conductors[dl.ConductorID]
+		a.conductorsMu.RUnlock()
+
+		if !ok {
+			err = &Error{
+				Event: &Event{
+					Message:     "dead letter conductor not registered",
+					ElectronID:  dl.Electron.ID,
+					AtomID:      dl.Electron.AtomID,
+					ConductorID: dl.ConductorID,
+				},
+			}
+
+			// Return the dead letter to the store so that
+			// it is not lost
+			a.restore(dls[i : i+1])
+			continue
+		}
+
+		if !a.accept(ctx, a.instance(conductor, dl.Electron)) {
+			a.restore(dls[i:])
+			return count, closed(ctx)
+		}
+
+		count++
+		a.event(func() interface{} {
+			return &Event{
+				Message:     "electron reprocessed from dead letter",
+				ElectronID:  dl.Electron.ID,
+				AtomID:      dl.Electron.AtomID,
+				ConductorID: dl.ConductorID,
+			}
+		})
+	}
+
+	return count, err
+}
+
+// restore returns the dead letters which were not reprocessed to the
+// dead letter store so that they are not lost. The background context
+// is used since the reprocessing contexts may be closed.
+func (a *atomizer) restore(dls []*DeadLetter) {
+	for _, dl := range dls {
+		err := a.deadletters.Add(context.Background(), dl)
+		if err == nil {
+			continue
+		}
+
+		a.err(func() error {
+			return &Error{
+				Event: &Event{
+					Message:     "error restoring dead letter",
+					ElectronID:  dl.Electron.ID,
+					AtomID:      dl.Electron.AtomID,
+					ConductorID: dl.ConductorID,
+				},
+				Internal: err,
+			}
+		})
+	}
+}
+
+// closed returns the error of the caller context when it is
+// closed, otherwise the atomizer context is closed
+func closed(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return simple("context closed", nil)
+}
+
+// MemoryDeadLetterStore is an in-memory DeadLetterStore
+type MemoryDeadLetterStore struct {
+	mu          sync.Mutex
+	deadletters []*DeadLetter
+}
+
+// Add stores a failed electron
+func (s *MemoryDeadLetterStore) Add(_ context.Context, dl *DeadLetter) error {
+	if dl == nil || dl.Electron == nil {
+		return simple("invalid dead letter", nil)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.deadletters = append(s.deadletters, dl)
+	return nil
+}
+
+// Take removes and returns the dead letters matching the filter
+func (s *MemoryDeadLetterStore) Take(
+	_ context.Context,
+	filter DeadLetterFilter,
+) ([]*DeadLetter, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var taken []*DeadLetter
+	remaining := s.deadletters[:0]
+	for _, dl := range s.deadletters {
+		if filter.Match(dl) {
+			taken = append(taken, dl)
+			continue
+		}
+
+		remaining = append(remaining, dl)
+	}
+
+	s.deadletters = remaining
+
+	return taken, nil
+}
+
+// Len returns the number of dead letters in the store
+func (s *MemoryDeadLetterStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.deadletters)
+}
@@ -0,0 +1,198 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// attempts tracks the number of times flakyatom processed an electron
+var attempts sync.Map
+
+var errFlaky = errors.New("flaky failure")
+
+// flakyatom fails the first time it processes an electron
+type flakyatom struct{}
+
+func (*flakyatom) Process(
+	ctx context.Context,
+	conductor Conductor,
+	electron *Electron,
+) ([]byte, error) {
+	if _, seen := attempts.LoadOrStore(electron.ID, true); !seen {
+		return nil, errFlaky
+	}
+
+	return []byte(`"ok"`), nil
+}
+
+type failatom struct{}
+
+func (*failatom) Process(
+	ctx context.Context,
+	conductor Conductor,
+	electron *Electron,
+) ([]byte, error) {
+	return nil, errors.New("failure")
+}
+
+func TestAtomizer_Reprocess(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	store := &MemoryDeadLetterStore{}
+	rec, a := recHarness(
+		ctx,
+		t,
+		WithDeadLetter(store),
+		&flakyatom{},
+		&failatom{},
+	)
+
+	electrons := []*Electron{
+		newElectron(ID(flakyatom{}), nil),
+		newElectron(ID(flakyatom{}), nil),
+		newElectron(ID(failatom{}), nil),
+	}
+
+	for _, e := range electrons {
+		if _, err := rec.Send(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+
+		if p := rec.next(ctx, t); p.Error == nil {
+			t.Fatalf("expected failure for %s", e.ID)
+		}
+	}
+
+	eventually(t, time.Second, func() bool {
+		return store.Len() == 3
+	})
+
+	count, err := a.Reprocess(ctx, DeadLetterFilter{
+		AtomID: ID(flakyatom{}),
+		Error: func(err error) bool {
+			return errors.Is(err, errFlaky)
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if count != 2 {
+		t.Fatalf("expected 2 reprocessed electrons, got %v", count)
+	}
+
+	for i := 0; i < count; i++ {
+		p := rec.next(ctx, t)
+		if p.Error != nil {
+			t.Fatalf("unexpected error on reprocess %s", p.Error)
+		}
+
+		if p.AtomID != ID(flakyatom{}) {
+			t.Fatalf("unexpected atom %s", p.AtomID)
+		}
+	}
+
+	if store.Len() != 1 {
+		t.Fatalf("expected failatom dead letter to remain, got %v", store.Len())
+	}
+}
+
+func TestDeadLetterFilter_Match(t *testing.T) {
+	now := time.Now()
+	dl := &DeadLetter{
+		Electron: &Electron{AtomID: "atom"},
+		Error:    errFlaky,
+		Time:     now,
+	}
+
+	tests := []struct {
+		name   string
+		filter DeadLetterFilter
+		match  bool
+	}{
+		{"empty", DeadLetterFilter{}, true},
+		{"atom", DeadLetterFilter{AtomID: "atom"}, true},
+		{"other atom", DeadLetterFilter{AtomID: "other"}, false},
+		{"since", DeadLetterFilter{Since: now.Add(-time.Second)}, true},
+		{"after", DeadLetterFilter{Since: now.Add(time.Second)}, false},
+		{"until", DeadLetterFilter{Until: now}, false},
+		{
+			"error",
+			DeadLetterFilter{Error: func(err error) bool {
+				return errors.Is(err, errFlaky)
+			}},
+			true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.filter.Match(dl) != test.match {
+				t.Fatalf("expected match = %v", test.match)
+			}
+		})
+	}
+}
+
+func TestAtomizer_Reprocess_Closed(t *testing.T) {
+	tests := map[string]func(a *atomizer, cancel context.CancelFunc){
+		"caller context": func(a *atomizer, cancel context.CancelFunc) {
+			cancel()
+		},
+		"atomizer context": func(a *atomizer, cancel context.CancelFunc) {
+			a.cancel()
+		},
+	}
+
+	for name, closer := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			store := &MemoryDeadLetterStore{}
+			for i := 0; i < 3; i++ {
+				err := store.Add(ctx, &DeadLetter{
+					Electron:    newElectron(ID(failatom{}), nil),
+					ConductorID: "conductor",
+					Error:       errFlaky,
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			mizer, err := Atomize(context.Background(), WithDeadLetter(store))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			a, _ := mizer.(*atomizer)
+			defer a.cancel()
+
+			closer(a, cancel)
+
+			count, err := a.Reprocess(ctx, DeadLetterFilter{})
+			if err == nil {
+				t.Fatal("expected error")
+			}
+
+			if count != 0 {
+				t.Fatalf("expected no reprocessed electrons, got %v", count)
+			}
+
+			if store.Len() != 3 {
+				t.Fatalf("expected dead letters restored, got %v", store.Len())
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrDuplicateElectron is set as the error of an electron which was
+// rejected because an electron with the same ID was seen recently
+var ErrDuplicateElectron = errors.New("duplicate electron")
+
+// DedupStore records the IDs of recently received electrons so that
+// redelivered electrons are only processed once. Implementations backed
+// by a shared store (i.e. Redis) provide deduplication across nodes and
+// restarts.
+type DedupStore interface {
+	// SeenRecently indicates if the electron ID was marked
+	// and the mark has not yet expired
+	SeenRecently(id string) (bool, error)
+
+	// Mark records the electron ID for the duration of the ttl
+	Mark(id string, ttl time.Duration) error
+}
+
+// dedup is the deduplication configuration of the atomizer
+type dedup struct {
+	store    DedupStore
+	ttl      time.Duration
+	failOpen bool
+}
+
+// WithDedup rejects electrons with an ID that was received within the ttl
+// with ErrDuplicateElectron. A nil store uses an in-memory store local to
+// this atomizer. When the store returns an error the electron is processed
+// if failOpen is set, otherwise it is rejected.
+func WithDedup(store DedupStore, ttl time.Duration, failOpen bool) Option {
+	return func(a *atomizer) error {
+		if ttl <= 0 {
+			return simple("dedup ttl must be positive", nil)
+		}
+
+		if store == nil {
+			store = NewMemoryDedupStore()
+		}
+
+		a.dedup = &dedup{
+			store:    store,
+			ttl:      ttl,
+			failOpen: failOpen,
+		}
+
+		return nil
+	}
+}
+
+// duplicate checks the electron against the dedup store and marks it as
+// seen. Electrons which should not be processed are rejected and false is
+// returned.
+func (a *atomizer) duplicate(inst instance) bool {
+	if a.dedup == nil {
+		return false
+	}
+
+	seen, err := a.dedup.store.SeenRecently(inst.electron.ID)
+	if err == nil && !seen {
+		err = a.dedup.store.Mark(inst.electron.ID, a.dedup.ttl)
+	}
+
+	event := &Event{
+		ElectronID:  inst.electron.ID,
+		AtomID:      inst.electron.AtomID,
+		ConductorID: ID(inst.conductor),
+	}
+
+	if err != nil {
+		event.Message = "dedup store error"
+		if a.dedup.failOpen {
+			a.err(func() error {
+				return &Error{Event: event, Internal: err}
+			})
+
+			return false
+		}
+
+		a.reject(inst, StageAdmission, &Error{Event: event, Internal: err})
+		return true
+	}
+
+	if !seen {
+		return false
+	}
+
+	event.Message = "duplicate electron"
+	a.reject(inst, StageAdmission, &Error{Event: event, Internal: ErrDuplicateElectron})
+
+	return true
+}
+
+// MemoryDedupStore is an in-memory DedupStore which is local to the
+// process and does not survive restarts
+type MemoryDedupStore struct {
+	mu    sync.Mutex
+	seen  map[string]time.Time
+	swept time.Time
+}
+
+// NewMemoryDedupStore creates an empty in-memory dedup store
+func NewMemoryDedupStore() *MemoryDedupStore {
+	return &MemoryDedupStore{
+		seen:  make(map[string]time.Time),
+		swept: time.Now(),
+	}
+}
+
+// SeenRecently indicates if the ID was marked and has not expired
+func (m *MemoryDedupStore) SeenRecently(id string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	expires, ok := m.seen[id]
+	if !ok {
+		return false, nil
+	}
+
+	if time.Now().After(expires) {
+		delete(m.seen, id)
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// Mark records the ID until the ttl expires
+func (m *MemoryDedupStore) Mark(id string, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	m.seen[id] = now.Add(ttl)
+
+	// Periodically remove the expired IDs so that
+	// the store does not grow without bound
+	if now.Sub(m.swept) < ttl {
+		return nil
+	}
+
+	for seen, expires := range m.seen {
+		if now.After(expires) {
+			delete(m.seen, seen)
+		}
+	}
+
+	m.swept = now
+
+	return nil
+}
+
+// Len returns the number of IDs in the store
+func (m *MemoryDedupStore) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return len(m.seen)
+}
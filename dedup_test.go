@@ -0,0 +1,139 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakededup is a dedup store which outlives the atomizers using it
+type fakededup struct {
+	mu   sync.Mutex
+	seen map[string]bool
+	err  error
+}
+
+func (f *fakededup) SeenRecently(id string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.seen[id], f.err
+}
+
+func (f *fakededup) Mark(id string, ttl time.Duration) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.seen[id] = true
+	return f.err
+}
+
+func TestAtomizer_Dedup_Restart(t *testing.T) {
+	store := &fakededup{seen: make(map[string]bool)}
+	e := newElectron(ID(noopatom{}), nil)
+
+	expected := []error{nil, ErrDuplicateElectron}
+	for i, want := range expected {
+		ctx, cancel := _ctx(context.TODO())
+
+		reset(ctx, t)
+
+		rec, _ := recHarness(
+			ctx,
+			t,
+			WithDedup(store, time.Minute, false),
+			&noopatom{},
+		)
+
+		_, err := rec.Send(ctx, e)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		p := rec.next(ctx, t)
+		if !errors.Is(p.Error, want) || (want == nil && p.Error != nil) {
+			t.Fatalf("run %v: expected %v, got %v", i, want, p.Error)
+		}
+
+		// Simulate a restart of the node
+		cancel()
+	}
+
+	reset(context.TODO(), t)
+}
+
+func TestAtomizer_Dedup_StoreErr(t *testing.T) {
+	tests := map[string]bool{
+		"fail open":   true,
+		"fail closed": false,
+	}
+
+	for name, failOpen := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := _ctx(context.TODO())
+			defer cancel()
+
+			reset(ctx, t)
+			t.Cleanup(func() {
+				reset(context.TODO(), t)
+			})
+
+			store := &fakededup{
+				seen: make(map[string]bool),
+				err:  errors.New("store unavailable"),
+			}
+
+			rec, _ := recHarness(
+				ctx,
+				t,
+				WithDedup(store, time.Minute, failOpen),
+				&noopatom{},
+			)
+
+			_, err := rec.Send(ctx, newElectron(ID(noopatom{}), nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			p := rec.next(ctx, t)
+			if failOpen && p.Error != nil {
+				t.Fatalf("expected electron to process, got %v", p.Error)
+			}
+
+			if !failOpen && !errors.Is(p.Error, store.err) {
+				t.Fatalf("expected store error, got %v", p.Error)
+			}
+		})
+	}
+}
+
+func TestMemoryDedupStore(t *testing.T) {
+	store := NewMemoryDedupStore()
+
+	seen, err := store.SeenRecently("id")
+	if err != nil || seen {
+		t.Fatalf("expected unseen, got %v | %v", seen, err)
+	}
+
+	if err = store.Mark("id", time.Millisecond*10); err != nil {
+		t.Fatal(err)
+	}
+
+	seen, err = store.SeenRecently("id")
+	if err != nil || !seen {
+		t.Fatalf("expected seen, got %v | %v", seen, err)
+	}
+
+	time.Sleep(time.Millisecond * 20)
+
+	seen, err = store.SeenRecently("id")
+	if err != nil || seen {
+		t.Fatalf("expected expired, got %v | %v", seen, err)
+	}
+
+	if store.Len() != 0 {
+		t.Fatalf("expected expired id to be removed, got %v", store.Len())
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// DefaultDrainTimeout is the maximum time a graceful deregistration
+// waits for the queued electrons of the atom to be processed
+const DefaultDrainTimeout = time.Second * 30
+
+// ErrDrainTimeout is returned when the queued electrons of an atom are
+// not processed before the drain timeout of a graceful deregistration
+var ErrDrainTimeout = errors.New("drain timeout")
+
+// DeregisterPolicy determines how the electrons queued for an atom are
+// handled when the atom is deregistered
+type DeregisterPolicy int
+
+const (
+	// DeregisterHard stops routing electrons to the atom immediately,
+	// electrons queued for the atom which have not yet been pushed to
+	// it are rejected as not registered
+	DeregisterHard DeregisterPolicy = iota
+
+	// DeregisterGraceful pushes the electrons queued for the atom before
+	// the deregistration to the atom and waits for them to be processed
+	// before stopping the atom, bounded by the drain timeout (see
+	// WithDrainTimeout). Electrons waiting in the priority queue are not
+	// considered queued until they are dequeued.
+	DeregisterGraceful
+)
+
+// WithDrainTimeout sets the maximum time a graceful deregistration waits
+// for the queued electrons of the atom to be processed, defaulting to
+// DefaultDrainTimeout
+func WithDrainTimeout(timeout time.Duration) Option {
+	return func(a *atomizer) error {
+		if timeout <= 0 {
+			return simple("drain timeout must be positive", nil)
+		}
+
+		a.drainTimeout = timeout
+		return nil
+	}
+}
+
+// Deregister removes the atom or conductor with the ID from the running
+// atomizer using the policy for the electrons queued for an atom.
+// Electrons already pushed to a deregistered atom finish processing
+// before its processing loops are stopped and Deregister returns,
+// electrons received afterwards are no longer routed to it. A
+// deregistered conductor stops being read from.
+//
+// When a graceful drain times out the atom is deregistered regardless,
+// the remaining queued electrons are rejected and ErrDrainTimeout is
+// returned while the electrons already pushed to the atom finish in
+// the background.
+func (a *atomizer) Deregister(id string, policy DeregisterPolicy) error {
+	ctx := a.ctx
+	if policy == DeregisterGraceful {
+		timeout := a.drainTimeout
+		if timeout <= 0 {
+			timeout = DefaultDrainTimeout
+		}
+
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(a.ctx, timeout)
+		defer cancel()
+
+		// Push the electrons queued before the deregistration
+		// to the atom while it is still routable
+		_ = a.barrier(ctx)
+	}
+
+	reps, err := a.detach(id)
+	if err != nil || reps == nil {
+		return err
+	}
+
+	// Ensure distribute is not pushing to the atom
+	// before its processing loops are stopped
+	err = a.barrier(ctx)
+	if err == nil {
+		err = reps.stop(ctx)
+	}
+
+	if err == nil {
+		// Nothing routes to the stopped loops any longer
+		for _, electrons := range reps.loops() {
+			close(electrons)
+		}
+
+		return nil
+	}
+
+	if a.ctx.Err() != nil {
+		return err
+	}
+
+	// Closing the loops once distribute is no longer pushing to them
+	// stops each loop after it processes the electrons pushed to it
+	a.routine(func() {
+		if a.barrier(a.ctx) != nil {
+			return
+		}
+
+		for _, electrons := range reps.loops() {
+			close(electrons)
+		}
+	})
+
+	return &Error{
+		Event: &Event{
+			Message: "graceful deregistration timed out",
+			AtomID:  id,
+		},
+		Internal: ErrDrainTimeout,
+	}
+}
+
+// deregister removes the atom or conductor with the ID from the atomizer.
+// Conductors stop being read from and electrons for a deregistered atom
+// are no longer routed to it.
+func (a *atomizer) deregister(id string) error {
+	_, err := a.detach(id)
+	return err
+}
+
+// detach removes the atom or conductor with the ID from the atomizer
+// returning the replicas of a removed atom so their processing loops
+// can be stopped
+func (a *atomizer) detach(id string) (*replicas, error) {
+	a.atomsMu.Lock()
+	reps, atom := a.atoms[id]
+	if !atom {
+		reps, atom = a.disabled[id]
+	}
+	delete(a.atoms, id)
+	delete(a.disabled, id)
+	a.rebuild()
+	a.atomsMu.Unlock()
+
+	if atom {
+		a.event(func() interface{} {
+			return &Event{
+				Message: "atom deregistered",
+				AtomID:  id,
+			}
+		})
+
+		return reps, nil
+	}
+
+	a.conductorsMu.Lock()
+	_, conductor := a.conductors[id]
+	cancel := a.conducting[id]
+	delete(a.conductors, id)
+	delete(a.conducting, id)
+	delete(a.pumps, id)
+	a.conductorsMu.Unlock()
+
+	if !conductor {
+		return nil, simple("deregister unknown id "+id, nil)
+	}
+
+	if cancel != nil {
+		cancel()
+	}
+
+	a.event(func() interface{} {
+		return &Event{
+			Message:     "conductor deregistered",
+			ConductorID: id,
+		}
+	})
+
+	return nil, nil
+}
@@ -0,0 +1,297 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAtomizer_Deregister(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	gaterelease = make(chan struct{})
+	release := gaterelease
+
+	atomID := ID(gateatom{})
+	rec, a := recHarness(ctx, t, WithAtomBuffer(3), &gateatom{})
+	events := a.Events(1000)
+
+	eventually(t, time.Second, func() bool {
+		return a.route(newElectron(atomID, nil)) != nil
+	})
+
+	for i := 0; i < 3; i++ {
+		if _, err := rec.Send(ctx, newElectron(atomID, nil)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Wait for the electrons to be pushed to the atom
+	for pushed := 0; pushed < 3; {
+		select {
+		case <-ctx.Done():
+			t.Fatal("expected electrons pushed to the atom")
+		case e := <-events:
+			if ev, ok := e.(*Event); ok &&
+				ev.Message == "pushed electron to atom" {
+				pushed++
+			}
+		}
+	}
+
+	deregistered := make(chan error, 1)
+	go func() {
+		deregistered <- a.Deregister(atomID, DeregisterHard)
+	}()
+
+	eventually(t, time.Second, func() bool {
+		return a.route(newElectron(atomID, nil)) == nil
+	})
+
+	select {
+	case err := <-deregistered:
+		t.Fatalf("expected in-flight electrons to drain first, got %v", err)
+	case <-time.After(time.Millisecond * 50):
+	}
+
+	close(release)
+
+	select {
+	case <-ctx.Done():
+		t.Fatal("expected deregistration to complete")
+	case err := <-deregistered:
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i := 0; i < 3; i++ {
+		if p := rec.next(ctx, t); p.Error != nil {
+			t.Fatalf("expected drained electron to succeed, got %v", p.Error)
+		}
+	}
+
+	if err := a.Deregister(atomID, DeregisterHard); err == nil {
+		t.Fatal("expected error deregistering unknown id")
+	}
+
+	if err := a.Deregister(ID(rec), DeregisterHard); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// queueGated sends electrons to the gate atom until one is executing, one
+// is being pushed to the atom by distribute and the rest are queued in
+// the electrons channel
+func queueGated(
+	ctx context.Context,
+	t *testing.T,
+	queued int,
+	opts ...interface{},
+) (*recorder, *atomizer, <-chan RejectedElectron, func()) {
+	t.Helper()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	gaterelease = make(chan struct{})
+	release := gaterelease
+
+	var once sync.Once
+	open := func() {
+		once.Do(func() {
+			close(release)
+		})
+	}
+	t.Cleanup(open)
+
+	atomID := ID(gateatom{})
+	rec, a := recHarness(
+		ctx,
+		t,
+		append(opts, WithBuffer(queued+1, 0, 0), &gateatom{})...,
+	)
+	rejections := a.Rejections(10)
+
+	eventually(t, time.Second, func() bool {
+		return a.route(newElectron(atomID, nil)) != nil
+	})
+
+	for i := 0; i < queued+2; i++ {
+		if _, err := rec.Send(ctx, newElectron(atomID, nil)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	eventually(t, time.Second, func() bool {
+		return a.Diagnostics().Electrons == queued
+	})
+
+	return rec, a, rejections, open
+}
+
+func TestAtomizer_Deregister_Policy(t *testing.T) {
+	tests := []struct {
+		name      string
+		policy    DeregisterPolicy
+		completed int
+		rejected  int
+	}{
+		{"graceful", DeregisterGraceful, 4, 0},
+		{"hard", DeregisterHard, 2, 2},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			d := time.Second * 30
+			ctx, cancel := _ctxT(context.TODO(), &d)
+			defer cancel()
+
+			rec, a, rejections, open := queueGated(ctx, t, 2)
+			atomID := ID(gateatom{})
+
+			deregistered := make(chan error, 1)
+			go func() {
+				deregistered <- a.Deregister(atomID, test.policy)
+			}()
+
+			// The atom stays routable while the queued
+			// electrons are drained gracefully
+			if test.policy == DeregisterHard {
+				eventually(t, time.Second, func() bool {
+					return a.route(newElectron(atomID, nil)) == nil
+				})
+			} else {
+				eventually(t, time.Second, func() bool {
+					return a.Diagnostics().Electrons == 3
+				})
+			}
+
+			open()
+
+			select {
+			case <-ctx.Done():
+				t.Fatal("expected deregistration to complete")
+			case err := <-deregistered:
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			// Rejected electrons are completed with their error
+			var succeeded, failed int
+			for i := 0; i < test.completed+test.rejected; i++ {
+				if p := rec.next(ctx, t); p.Error != nil {
+					failed++
+				} else {
+					succeeded++
+				}
+			}
+
+			if succeeded != test.completed || failed != test.rejected {
+				t.Fatalf(
+					"expected %v succeeded and %v failed, got %v and %v",
+					test.completed,
+					test.rejected,
+					succeeded,
+					failed,
+				)
+			}
+
+			for i := 0; i < test.rejected; i++ {
+				select {
+				case <-ctx.Done():
+					t.Fatal("expected queued electron to be rejected")
+				case r := <-rejections:
+					if r.Stage != StageDistribution {
+						t.Fatalf("unexpected rejection %+v", r)
+					}
+				}
+			}
+
+			select {
+			case p := <-rec.completions:
+				t.Fatalf("unexpected completion %+v", p)
+			case r := <-rejections:
+				t.Fatalf("unexpected rejection %+v", r)
+			case <-time.After(time.Millisecond * 50):
+			}
+		})
+	}
+}
+
+func TestAtomizer_Deregister_DrainTimeout(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	rec, a, rejections, open := queueGated(
+		ctx,
+		t,
+		2,
+		WithDrainTimeout(time.Millisecond*50),
+	)
+	atomID := ID(gateatom{})
+
+	err := a.Deregister(atomID, DeregisterGraceful)
+	if !errors.Is(err, ErrDrainTimeout) {
+		t.Fatalf("expected drain timeout, got %v", err)
+	}
+
+	if a.route(newElectron(atomID, nil)) != nil {
+		t.Fatal("expected atom deregistered after the drain timeout")
+	}
+
+	open()
+
+	// The electrons pushed to the atom finish while
+	// the remaining queued electrons are rejected
+	var succeeded int
+	for i := 0; i < 4; i++ {
+		if p := rec.next(ctx, t); p.Error == nil {
+			succeeded++
+		}
+	}
+
+	if succeeded != 2 {
+		t.Fatalf("expected 2 electrons to succeed, got %v", succeeded)
+	}
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-ctx.Done():
+			t.Fatal("expected queued electron to be rejected")
+		case <-rejections:
+		}
+	}
+
+	eventually(t, time.Second, func() bool {
+		return a.Diagnostics().Splits == 0
+	})
+}
+
+func TestWithDrainTimeout(t *testing.T) {
+	a := &atomizer{}
+	if err := WithDrainTimeout(0)(a); err == nil {
+		t.Fatal("expected error for non-positive drain timeout")
+	}
+
+	if err := WithDrainTimeout(time.Second)(a); err != nil {
+		t.Fatal(err)
+	}
+
+	if a.drainTimeout != time.Second {
+		t.Fatalf("expected drain timeout %v, got %v", time.Second, a.drainTimeout)
+	}
+}
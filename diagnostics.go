@@ -0,0 +1,86 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import "sync/atomic"
+
+// Diagnostics is a read-only snapshot of the internal channels and
+// goroutines of the atomizer for detecting leaks and back pressure
+type Diagnostics struct {
+	// Splits is the number of running atom processing loops
+	Splits int64 `json:"splits"`
+
+	// Conducts is the number of running conductor receive loops
+	Conducts int64 `json:"conducts"`
+
+	// Electrons is the number of electrons waiting
+	// in the electrons channel to be distributed
+	Electrons int `json:"electrons"`
+
+	// ElectronsCapacity is the capacity of the electrons channel
+	ElectronsCapacity int `json:"electronsCapacity"`
+
+	// Bonded is the number of instances waiting in the bonded channel
+	Bonded int `json:"bonded"`
+
+	// Atoms is the number of registered atoms
+	Atoms int `json:"atoms"`
+
+	// Conductors is the number of registered conductors
+	Conductors int `json:"conductors"`
+
+	// Goroutines is the number of running goroutines
+	// started by the atomizer
+	Goroutines int64 `json:"goroutines"`
+
+	// Spawned is the total number of goroutines
+	// started by the atomizer
+	Spawned uint64 `json:"spawned"`
+}
+
+// routines counts the goroutines started by the atomizer
+type routines struct {
+	splits   int64
+	conducts int64
+	running  int64
+	spawned  uint64
+}
+
+// routine runs the function on a goroutine counted by the diagnostics
+func (a *atomizer) routine(fn func()) {
+	atomic.AddUint64(&a.routines.spawned, 1)
+	atomic.AddInt64(&a.routines.running, 1)
+
+	go func() {
+		defer atomic.AddInt64(&a.routines.running, -1)
+
+		fn()
+	}()
+}
+
+// Diagnostics returns the current counts of the
+// internal channels and goroutines of the atomizer
+func (a *atomizer) Diagnostics() Diagnostics {
+	a.atomsMu.RLock()
+	atoms := len(a.atoms)
+	a.atomsMu.RUnlock()
+
+	a.conductorsMu.RLock()
+	conductors := len(a.conductors)
+	a.conductorsMu.RUnlock()
+
+	return Diagnostics{
+		Splits:            atomic.LoadInt64(&a.routines.splits),
+		Conducts:          atomic.LoadInt64(&a.routines.conducts),
+		Electrons:         len(a.electrons),
+		ElectronsCapacity: cap(a.electrons),
+		Bonded:            len(a.bonded),
+		Atoms:             atoms,
+		Conductors:        conductors,
+		Goroutines:        atomic.LoadInt64(&a.routines.running),
+		Spawned:           atomic.LoadUint64(&a.routines.spawned),
+	}
+}
@@ -0,0 +1,63 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestAtomizer_Diagnostics(t *testing.T) {
+	reset(nil, t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	rec, a := recHarness(ctx, t, &noopatom{})
+
+	eventually(t, time.Second, func() bool {
+		d := a.Diagnostics()
+		return d.Atoms == 1 && d.Conductors == 1 &&
+			d.Splits == 1 && d.Conducts == 1
+	})
+
+	d := a.Diagnostics()
+	if d.ElectronsCapacity != cap(a.electrons) {
+		t.Fatalf(
+			"expected electrons capacity %v, got %v",
+			cap(a.electrons),
+			d.ElectronsCapacity,
+		)
+	}
+
+	// receive, distribute, the atom processing
+	// loop and the conductor receive loop
+	if d.Goroutines < 4 || d.Spawned < uint64(d.Goroutines) {
+		t.Fatalf("unexpected goroutine counts %+v", d)
+	}
+
+	for i := 0; i < 3; i++ {
+		rec.input <- newElectron(ID(&noopatom{}), nil)
+		rec.next(ctx, t)
+	}
+
+	d = a.Diagnostics()
+	if d.Electrons != 0 || d.Bonded != 0 {
+		t.Fatalf("expected drained channels, got %+v", d)
+	}
+
+	err := a.Deregister(ID(&noopatom{}), DeregisterHard)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	eventually(t, time.Second, func() bool {
+		d = a.Diagnostics()
+		return d.Atoms == 0 && d.Splits == 0
+	})
+
+	// Every goroutine started by the atomizer exits once closed
+	cancel()
+	eventually(t, time.Second, func() bool {
+		d = a.Diagnostics()
+		return d.Conducts == 0 && d.Goroutines == 0
+	})
+}
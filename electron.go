@@ -6,10 +6,9 @@
 package engine
 
 import (
-	"encoding/base64"
 	"encoding/gob"
 	"encoding/json"
-	"strings"
+	"strconv"
 	"time"
 )
 
@@ -35,7 +34,8 @@ type Electron struct {
 	// Timeout is the maximum time duration that should be allowed
 	// for this instance to process. After the duration is exceeded
 	// the context should be canceled and the processing released
-	// and a failure sent back to the conductor
+	// and a failure sent back to the conductor. A nil, zero or
+	// negative Timeout does not time out.
 	Timeout *time.Duration
 
 	// CopyState lets atomizer know if it should copy the state of the
@@ -47,6 +47,95 @@ type Electron struct {
 	// skipped
 	CopyState bool
 
+	// PartitionKey is used to consistently route electrons with the
+	// same key to the same replica of an atom when the atom is
+	// registered with multiple replicas. The electron ID is used
+	// when no partition key is set.
+	PartitionKey string
+
+	// TraceParent is the propagated trace context of the sender (i.e. a
+	// W3C traceparent header) used to link the processing of the electron
+	// to the trace of the sender
+	TraceParent string
+
+	// GroupID identifies the group of electrons this electron belongs
+	// to for conductors which complete electrons in groups (see
+	// GroupCompleter). The group is completed once GroupSize electrons
+	// with the same GroupID have completed or the group times out (see
+	// WithGroupTimeout).
+	GroupID string
+
+	// GroupSize is the number of electrons in the group
+	GroupSize int
+
+	// ForceAtomKey overrides the routing of the electron sending it
+	// directly to the atom registered with the key rather than the
+	// AtomID. The key is either an atom ID or the name of a specific
+	// replica of an atom (i.e. `package.Type#1`). The electron fails if
+	// the key is not registered. This is intended for debugging and
+	// canary testing.
+	ForceAtomKey string
+
+	// Requires is the set of capabilities the processing atom must
+	// advertise (see Capable). When the AtomID is empty the electron is
+	// routed to a registered atom whose capabilities are a superset of
+	// Requires. When multiple atoms are capable the atom with the fewest
+	// capabilities beyond Requires is selected, then the atom with the
+	// lowest ID. An electron no atom is capable of is completed with an
+	// error.
+	Requires []string
+
+	// Hops is the number of times the electron has been forwarded
+	// between atomizer nodes (see WithForwarding)
+	Hops int
+
+	// CorrelationID correlates the electron with related electrons,
+	// such as the children submitted while processing an electron
+	// (see Submit)
+	CorrelationID string
+
+	// Tags are arbitrary labels for the electron which are carried
+	// through to its children (see Submit)
+	Tags map[string]string
+
+	// Flags are feature flags which vary the behavior of the atom
+	// processing the electron, such as for gradual rollouts. The atom
+	// reads the flags of the electron it is processing from its
+	// context (see FlagsFromContext).
+	Flags map[string]bool
+
+	// Lineage is the chain of ancestors of the electron, oldest
+	// first, when the electron was submitted as a child of another
+	// electron (see Submit). The lineage is bounded to MaxLineage.
+	Lineage []LineageEntry
+
+	// TenantID identifies the tenant submitting the electron. Tenants
+	// share the distribution of electrons by weight when tenant weights
+	// are configured (see WithTenantWeight).
+	TenantID string
+
+	// Priority orders the electron against the other electrons waiting
+	// to be distributed when priority queueing is enabled (see
+	// WithConductorPriority). Higher priorities are dispatched first.
+	Priority int
+
+	// Checksum is the algorithm used to checksum the payload when the
+	// electron is marshaled. The checksum is verified when the electron
+	// is unmarshaled and electrons whose payload does not match are
+	// rejected with ErrPayloadChecksumMismatch. No checksum is included
+	// when empty.
+	Checksum ChecksumAlgorithm
+
+	// ReplyExpected indicates if the conductor expects the properties of
+	// the processed electron. When false the atom is executed but the
+	// properties are not completed on the conductor or stored. A nil
+	// ReplyExpected expects a reply (see ExpectsReply).
+	ReplyExpected *bool
+
+	// Retry is the retry policy for the electron when its processing
+	// fails, overriding the default retry policy (see WithRetryPolicy)
+	Retry *RetryPolicy
+
 	// Payload is to be used by the registered atom to properly unmarshal
 	// the []byte for the actual atom instance. RawMessage is used to
 	// delay unmarshal of the payload information so the atom can do it
@@ -54,19 +143,74 @@ type Electron struct {
 	Payload []byte
 }
 
+// jsonElectron is the wire representation of an electron
+type jsonElectron struct {
+	Version       int               `json:"v,omitempty"`
+	SenderID      string            `json:"senderid"`
+	ID            string            `json:"id"`
+	AtomID        string            `json:"atomid"`
+	Timeout       *jsonDuration     `json:"timeout,omitempty"`
+	CopyState     bool              `json:"copystate,omitempty"`
+	PartitionKey  string            `json:"partitionkey,omitempty"`
+	TraceParent   string            `json:"traceparent,omitempty"`
+	GroupID       string            `json:"groupid,omitempty"`
+	GroupSize     int               `json:"groupsize,omitempty"`
+	ForceAtomKey  string            `json:"forceatomkey,omitempty"`
+	Requires      []string          `json:"requires,omitempty"`
+	Hops          int               `json:"hops,omitempty"`
+	CorrelationID string            `json:"correlationid,omitempty"`
+	Tags          map[string]string `json:"tags,omitempty"`
+	Flags         map[string]bool   `json:"flags,omitempty"`
+	Lineage       []LineageEntry    `json:"lineage,omitempty"`
+	TenantID      string            `json:"tenantid,omitempty"`
+	Priority      int               `json:"priority,omitempty"`
+	Checksum      string            `json:"checksum,omitempty"`
+	NoReply       bool              `json:"noreply,omitempty"`
+	Retry         *RetryPolicy      `json:"retry,omitempty"`
+	Payload       json.RawMessage   `json:"payload,omitempty"`
+}
+
+// jsonDuration is a duration which is marshaled as nanoseconds and can be
+// unmarshaled from either nanoseconds or a duration string (i.e. "5s")
+type jsonDuration time.Duration
+
+// UnmarshalJSON reads in a duration as nanoseconds or a duration string
+func (d *jsonDuration) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		var value string
+		if err := json.Unmarshal(data, &value); err != nil {
+			return err
+		}
+
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+
+		*d = jsonDuration(parsed)
+		return nil
+	}
+
+	var nanos int64
+	if err := json.Unmarshal(data, &nanos); err != nil {
+		return err
+	}
+
+	*d = jsonDuration(nanos)
+	return nil
+}
+
 // UnmarshalJSON reads in a []byte of JSON data and maps it to the Electron
 // struct properly for use throughout Atomizer
 func (e *Electron) UnmarshalJSON(data []byte) error {
-	jsonE := struct {
-		SenderID  string          `json:"senderid"`
-		ID        string          `json:"id"`
-		AtomID    string          `json:"atomid"`
-		Timeout   *time.Duration  `json:"timeout,omitempty"`
-		CopyState bool            `json:"copystate,omitempty"`
-		Payload   json.RawMessage `json:"payload,omitempty"`
-	}{}
-
-	err := json.Unmarshal(data, &jsonE)
+	data, err := unalias(data)
+	if err != nil {
+		return err
+	}
+
+	jsonE := jsonElectron{}
+
+	err = json.Unmarshal(data, &jsonE)
 	if err != nil {
 		return err
 	}
@@ -74,44 +218,105 @@ func (e *Electron) UnmarshalJSON(data []byte) error {
 	e.SenderID = jsonE.SenderID
 	e.ID = jsonE.ID
 	e.AtomID = jsonE.AtomID
-	e.Timeout = jsonE.Timeout
+	e.Timeout = (*time.Duration)(jsonE.Timeout)
+	e.PartitionKey = jsonE.PartitionKey
+	e.TraceParent = jsonE.TraceParent
+	e.GroupID = jsonE.GroupID
+	e.GroupSize = jsonE.GroupSize
+	e.ForceAtomKey = jsonE.ForceAtomKey
+	e.Requires = jsonE.Requires
+	e.Hops = jsonE.Hops
+	e.CorrelationID = jsonE.CorrelationID
+	e.Tags = jsonE.Tags
+	e.Flags = jsonE.Flags
+	e.Lineage = jsonE.Lineage
+	e.TenantID = jsonE.TenantID
+	e.Priority = jsonE.Priority
+	e.Retry = jsonE.Retry
+
+	if jsonE.NoReply {
+		reply := false
+		e.ReplyExpected = &reply
+	}
 
-	if jsonE.Payload != nil {
-		pay := strings.Trim(string(jsonE.Payload), "\"")
-		e.Payload, err = base64.StdEncoding.DecodeString(pay)
+	if jsonE.Checksum != "" {
+		e.Checksum, err = verifyChecksum(jsonE.Checksum, jsonE.Payload)
 		if err != nil {
-			e.Payload = jsonE.Payload
+			return err
 		}
 	}
 
-	return nil
+	decode, ok := decoders[jsonE.Version]
+	if !ok {
+		return simple(
+			"electron format version "+strconv.Itoa(jsonE.Version),
+			ErrUnsupportedVersion,
+		)
+	}
+
+	return decode(&jsonE, e)
 }
 
 // MarshalJSON implements the custom json marshaler for electron
 func (e *Electron) MarshalJSON() ([]byte, error) {
-	return json.Marshal(&struct {
-		SenderID  string          `json:"senderid"`
-		ID        string          `json:"id"`
-		AtomID    string          `json:"atomid"`
-		Timeout   *time.Duration  `json:"timeout,omitempty"`
-		CopyState bool            `json:"copystate,omitempty"`
-		Payload   json.RawMessage `json:"payload,omitempty"`
-	}{
-		SenderID: e.SenderID,
-		ID:       e.ID,
-		AtomID:   e.AtomID,
-		Timeout:  e.Timeout,
-		Payload:  json.RawMessage(e.Payload),
+	var sum string
+	if e.Checksum != "" {
+		var err error
+		sum, err = checksum(e.Checksum, e.Payload)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return json.Marshal(&jsonElectron{
+		Version:       ElectronVersion,
+		SenderID:      e.SenderID,
+		ID:            e.ID,
+		AtomID:        e.AtomID,
+		Timeout:       (*jsonDuration)(e.Timeout),
+		PartitionKey:  e.PartitionKey,
+		TraceParent:   e.TraceParent,
+		GroupID:       e.GroupID,
+		GroupSize:     e.GroupSize,
+		ForceAtomKey:  e.ForceAtomKey,
+		Requires:      e.Requires,
+		Hops:          e.Hops,
+		CorrelationID: e.CorrelationID,
+		Tags:          e.Tags,
+		Flags:         e.Flags,
+		Lineage:       e.Lineage,
+		TenantID:      e.TenantID,
+		Priority:      e.Priority,
+		Checksum:      sum,
+		NoReply:       !e.ExpectsReply(),
+		Retry:         e.Retry,
+		Payload:       json.RawMessage(e.Payload),
 	})
 }
 
+// ExpectsReply indicates if the properties of the processed electron
+// are completed on the conductor
+func (e *Electron) ExpectsReply() bool {
+	return e.ReplyExpected == nil || *e.ReplyExpected
+}
+
+// timeout returns the timeout of the electron, if it has one
+func (e *Electron) timeout() (time.Duration, bool) {
+	if e.Timeout == nil || *e.Timeout <= 0 {
+		return 0, false
+	}
+
+	return *e.Timeout, true
+}
+
 // Validate ensures that the electron information is intact for proper
-// execution
+// execution. The SenderID, ID and AtomID are required, an electron
+// routed by capability (see Requires) may omit the AtomID.
 func (e *Electron) Validate() (valid bool) {
 	if e != nil &&
 		e.SenderID != "" &&
 		e.ID != "" &&
-		e.AtomID != "" {
+		(e.AtomID != "" || len(e.Requires) > 0) {
 		valid = true
 	}
 
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"devnw.com/validator"
 	"github.com/google/go-cmp/cmp"
@@ -30,13 +31,13 @@ func TestElectron_MarshalJSON(t *testing.T) {
 		{
 			"valid electron",
 			noopelectron,
-			`{"senderid":"empty","id":"empty","atomid":"empty"}`,
+			`{"v":2,"senderid":"empty","id":"empty","atomid":"empty"}`,
 			false,
 		},
 		{
 			"valid electron w/ payload",
 			nonb64,
-			fmt.Sprintf(`{"senderid":"empty","id":"empty","atomid":"empty","payload":%s}`, pay),
+			fmt.Sprintf(`{"v":2,"senderid":"empty","id":"empty","atomid":"empty","payload":%s}`, pay),
 			false,
 		},
 	}
@@ -166,6 +167,16 @@ func TestElectron_Validate(t *testing.T) {
 			&Electron{ID: "test", AtomID: "test"},
 			false,
 		},
+		{
+			"valid electron / ID & sender & requires",
+			&Electron{ID: "test", SenderID: "test", Requires: []string{"test"}},
+			true,
+		},
+		{
+			"invalid electron / ID & requires",
+			&Electron{ID: "test", Requires: []string{"test"}},
+			false,
+		},
 	}
 
 	for _, test := range tests {
@@ -176,3 +187,77 @@ func TestElectron_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestElectron_Timeout_RoundTrip(t *testing.T) {
+	timeout := time.Second * 5
+
+	tests := []struct {
+		name     string
+		timeout  *time.Duration
+		expected string
+	}{
+		{
+			"5s timeout",
+			&timeout,
+			`{"v":2,"senderid":"empty","id":"empty","atomid":"empty","timeout":5000000000}`,
+		},
+		{
+			"nil timeout",
+			nil,
+			`{"v":2,"senderid":"empty","id":"empty","atomid":"empty"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := &Electron{
+				SenderID: "empty",
+				ID:       "empty",
+				AtomID:   "empty",
+				Timeout:  test.timeout,
+			}
+
+			data, err := json.Marshal(e)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(data) != test.expected {
+				t.Fatalf("mismatch: e[%s] != r[%s]", test.expected, data)
+			}
+
+			out := &Electron{}
+			if err = json.Unmarshal(data, out); err != nil {
+				t.Fatal(err)
+			}
+
+			diff := cmp.Diff(e, out)
+			if diff != "" {
+				t.Fatalf("expected equality %s", diff)
+			}
+		})
+	}
+}
+
+func TestElectron_UnmarshalJSON_TimeoutString(t *testing.T) {
+	e := &Electron{}
+	err := json.Unmarshal(
+		[]byte(`{"senderid":"empty","id":"empty","atomid":"empty","timeout":"5s"}`),
+		e,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if e.Timeout == nil || *e.Timeout != time.Second*5 {
+		t.Fatalf("expected 5s timeout, got %v", e.Timeout)
+	}
+
+	err = json.Unmarshal(
+		[]byte(`{"senderid":"empty","id":"empty","atomid":"empty","timeout":"soon"}`),
+		e,
+	)
+	if err == nil {
+		t.Fatal("expected error for invalid duration")
+	}
+}
@@ -0,0 +1,97 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// echoatom returns the payload of the electron as its result
+type echoatom struct{}
+
+func (*echoatom) Process(
+	ctx context.Context,
+	conductor Conductor,
+	electron *Electron,
+) ([]byte, error) {
+	return electron.Payload, nil
+}
+
+func TestAtomizer_EmptyResult(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	rec, a := recHarness(ctx, t, &noopatom{}, &echoatom{}, &failatom{})
+	events := a.Events(100)
+
+	tests := map[string]struct {
+		electron *Electron
+		empty    bool
+	}{
+		"nil result": {
+			newElectron(ID(noopatom{}), nil),
+			true,
+		},
+		"result": {
+			newElectron(ID(echoatom{}), []byte(`"ok"`)),
+			false,
+		},
+		"error": {
+			newElectron(ID(failatom{}), nil),
+			false,
+		},
+	}
+
+	emitted := make(map[string]bool)
+	for name, test := range tests {
+		if _, err := rec.Send(ctx, test.electron); err != nil {
+			t.Fatal(err)
+		}
+
+		p := rec.next(ctx, t)
+		if p.Empty != test.empty {
+			t.Fatalf("%s: expected empty %v, got %v", name, test.empty, p.Empty)
+		}
+
+		if test.empty && (p.Error != nil || p.Status != StatusSucceeded) {
+			t.Fatalf("%s: expected success, got %v %v", name, p.Status, p.Error)
+		}
+
+		// The completion precedes the event so
+		// wait for the execution to finish
+		for done := false; !done; {
+			var ev interface{}
+			select {
+			case <-ctx.Done():
+				t.Fatalf("%s: expected execution events", name)
+			case ev = <-events:
+			}
+
+			event, ok := ev.(*Event)
+			if !ok || event.ElectronID != test.electron.ID {
+				continue
+			}
+
+			switch event.Message {
+			case "empty result":
+				emitted[name] = true
+			case "atom execution complete":
+				if !test.empty {
+					done = true
+				}
+			}
+
+			done = done || emitted[name]
+		}
+
+		if emitted[name] != test.empty {
+			t.Fatalf("%s: expected empty result event %v", name, test.empty)
+		}
+	}
+}
@@ -8,11 +8,13 @@ package engine
 import (
 	"encoding/gob"
 	"fmt"
+	"runtime/debug"
 	"strings"
 )
 
 func init() {
 	gob.Register(&Error{})
+	gob.Register(&PanicError{})
 }
 
 type wrappedErr interface {
@@ -42,6 +44,30 @@ func ptos(r interface{}) string {
 	return fmt.Sprintf("%v", r)
 }
 
+// PanicError carries the value recovered from a panic along with
+// the stack trace of the goroutine which panicked
+type PanicError struct {
+
+	// Value is the recovered panic value
+	Value string `json:"value"`
+
+	// Stack is the stack trace captured when the panic was recovered
+	Stack string `json:"stack"`
+}
+
+// ptope takes a result of a recover and captures it along
+// with the current stack trace
+func ptope(r interface{}) *PanicError {
+	return &PanicError{
+		Value: ptos(r),
+		Stack: string(debug.Stack()),
+	}
+}
+
+func (p *PanicError) Error() string {
+	return "panic: " + p.Value + "\n" + p.Stack
+}
+
 // Error is an error type which provides specific
 // atomizer information as part of an error
 type Error struct {
@@ -0,0 +1,95 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import "time"
+
+// DefaultErrorRateWindow is the window of the rolling error rate of
+// the atoms when no window is configured
+const DefaultErrorRateWindow = time.Minute
+
+// rateBuckets is the number of buckets the error rate window is
+// divided into, the window slides forward one bucket at a time
+const rateBuckets = 10
+
+// WithErrorRateWindow sets the window of the rolling error rate reported
+// for each atom through Stats
+func WithErrorRateWindow(window time.Duration) Option {
+	return func(a *atomizer) error {
+		if window <= 0 {
+			return simple("error rate window must be positive", nil)
+		}
+
+		a.rateWindow = window
+		return nil
+	}
+}
+
+// rateBucket counts the executions of an atom within a slice
+// of the error rate window
+type rateBucket struct {
+	start  time.Time
+	total  uint64
+	errors uint64
+}
+
+// errorRate is the rolling error rate of an atom. The window is divided
+// into equal buckets and executions older than the window are dropped a
+// bucket at a time so the rate is smoothed across the bucket width.
+type errorRate struct {
+	width   time.Duration
+	buckets [rateBuckets]rateBucket
+}
+
+func newErrorRate(window time.Duration) *errorRate {
+	if window <= 0 {
+		window = DefaultErrorRateWindow
+	}
+
+	width := window / rateBuckets
+	if width <= 0 {
+		width = 1
+	}
+
+	return &errorRate{width: width}
+}
+
+// add counts an execution at the time
+func (r *errorRate) add(now time.Time, failed bool) {
+	start := now.Truncate(r.width)
+	b := &r.buckets[(start.UnixNano()/int64(r.width))%rateBuckets]
+
+	if !b.start.Equal(start) {
+		*b = rateBucket{start: start}
+	}
+
+	b.total++
+	if failed {
+		b.errors++
+	}
+}
+
+// rate returns the ratio of failed executions to total executions
+// within the window as of the time
+func (r *errorRate) rate(now time.Time) float64 {
+	var total, errors uint64
+
+	oldest := now.Truncate(r.width).Add(-r.width * (rateBuckets - 1))
+	for _, b := range r.buckets {
+		if b.start.Before(oldest) || b.start.After(now) {
+			continue
+		}
+
+		total += b.total
+		errors += b.errors
+	}
+
+	if total == 0 {
+		return 0
+	}
+
+	return float64(errors) / float64(total)
+}
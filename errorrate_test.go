@@ -0,0 +1,72 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestAtomizer_ErrorRate(t *testing.T) {
+	tests := map[string]struct {
+		successes int
+		failures  int
+		expected  float64
+	}{
+		"no failures":   {10, 0, 0},
+		"all failures":  {0, 4, 1},
+		"mixed":         {7, 3, 0.3},
+		"mostly failed": {1, 3, 0.75},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, cancel, a := unexpHarness(t)
+			defer cancel()
+
+			for i := 0; i < test.successes; i++ {
+				a.record("test.Atom", &Properties{}, nil)
+			}
+
+			for i := 0; i < test.failures; i++ {
+				a.record("test.Atom", &Properties{}, errors.New("failed"))
+			}
+
+			rate := a.Stats()["test.Atom"].ErrorRate
+			if math.Abs(rate-test.expected) > 0.001 {
+				t.Fatalf("expected error rate %v, got %v", test.expected, rate)
+			}
+		})
+	}
+}
+
+func TestAtomizer_ErrorRate_Window(t *testing.T) {
+	ctx, cancel := _ctx(context.TODO())
+	defer cancel()
+
+	window := time.Millisecond * 50
+	mizer, err := Atomize(ctx, WithErrorRateWindow(window))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, _ := mizer.(*atomizer)
+
+	a.record("test.Atom", &Properties{Error: errors.New("failed")}, nil)
+	if rate := a.Stats()["test.Atom"].ErrorRate; rate != 1 {
+		t.Fatalf("expected error rate 1, got %v", rate)
+	}
+
+	// Failures older than the window no longer count
+	time.Sleep(window * 2)
+
+	a.record("test.Atom", &Properties{}, nil)
+	if rate := a.Stats()["test.Atom"].ErrorRate; rate != 0 {
+		t.Fatalf("expected error rate 0, got %v", rate)
+	}
+
+	if stats := a.Stats()["test.Atom"]; stats.Errors != 1 {
+		t.Fatalf("expected 1 total error, got %v", stats.Errors)
+	}
+}
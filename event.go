@@ -35,6 +35,20 @@ type Event struct {
 	// ConductorID is the conductor which was being
 	// used for receiving instructions
 	ConductorID string `json:"conductorID"`
+
+	// SenderID is the node which sent the electron, if any
+	SenderID string `json:"senderID,omitempty"`
+
+	// TenantID is the tenant of the electron, if any
+	TenantID string `json:"tenantID,omitempty"`
+
+	// NodeID is the node of the atomizer which
+	// emitted the event (see WithNodeID)
+	NodeID string `json:"nodeID,omitempty"`
+
+	// Stage is the timing of the pipeline stage this event
+	// represents for an electron, if any
+	Stage *Stage `json:"stage,omitempty"`
 }
 
 func (e *Event) String() string {
@@ -76,6 +90,11 @@ func (e *Event) ids() string {
 		ids = append(ids, "eid:"+e.ElectronID)
 	}
 
+	// Include the node id if it is part of the event
+	if e.NodeID != "" {
+		ids = append(ids, "nid:"+e.NodeID)
+	}
+
 	return strings.Join(ids, " | ")
 }
 
@@ -0,0 +1,114 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"sigs.k8s.io/yaml"
+)
+
+// ConductorFactory creates a new conductor from the raw configuration
+// supplied for it in a deployment configuration
+type ConductorFactory func(cfg json.RawMessage) (Conductor, error)
+
+var factories sync.Map
+
+// RegisterConductorFactory adds a named conductor factory to the atomizer
+// so that conductors can be constructed from configuration rather than
+// in code. Conductor packages should register their factory in an init
+// function. Registrations using the same name will be overridden.
+func RegisterConductorFactory(
+	name string,
+	f func(cfg json.RawMessage) (Conductor, error),
+) error {
+	if name == "" {
+		return simple("empty conductor factory name", nil)
+	}
+
+	if f == nil {
+		return simple(
+			fmt.Sprintf("nil conductor factory %s", name),
+			nil,
+		)
+	}
+
+	factories.Store(name, ConductorFactory(f))
+
+	return nil
+}
+
+// conductorConfig is a single entry in a conductor configuration array
+type conductorConfig struct {
+	Name   string          `json:"name"`
+	Config json.RawMessage `json:"config,omitempty"`
+}
+
+// BuildConductors constructs the conductors defined in a JSON array, or a
+// YAML sequence, of `{"name": ..., "config": ...}` entries using the
+// registered conductor factories. The config of each entry is passed to
+// its factory as JSON. The conductors are returned in the same order they
+// are defined in the configuration.
+func BuildConductors(config []byte) ([]Conductor, error) {
+	data := bytes.TrimSpace(config)
+	if len(data) > 0 && data[0] != '[' {
+		converted, err := yaml.YAMLToJSON(data)
+		if err != nil {
+			return nil, simple("invalid conductor configuration", err)
+		}
+
+		data = converted
+	}
+
+	var entries []conductorConfig
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, simple("invalid conductor configuration", err)
+	}
+
+	conductors := make([]Conductor, 0, len(entries))
+	for _, entry := range entries {
+		value, ok := factories.Load(entry.Name)
+		if !ok {
+			return nil, simple(
+				fmt.Sprintf(
+					"unknown conductor factory %s",
+					entry.Name,
+				),
+				nil,
+			)
+		}
+
+		f, _ := value.(ConductorFactory)
+
+		conductor, err := f(entry.Config)
+		if err != nil {
+			return nil, &Error{
+				Event: &Event{
+					Message: "error building conductor " +
+						entry.Name,
+				},
+				Internal: err,
+			}
+		}
+
+		if conductor == nil {
+			return nil, simple(
+				fmt.Sprintf(
+					"nil conductor from factory %s",
+					entry.Name,
+				),
+				nil,
+			)
+		}
+
+		conductors = append(conductors, conductor)
+	}
+
+	return conductors, nil
+}
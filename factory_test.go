@@ -0,0 +1,136 @@
+package engine
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestBuildConductors(t *testing.T) {
+	err := RegisterConductorFactory(
+		"test.valid",
+		func(cfg json.RawMessage) (Conductor, error) {
+			return &validconductor{make(chan *Electron), true}, nil
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = RegisterConductorFactory(
+		"test.passthrough",
+		func(cfg json.RawMessage) (Conductor, error) {
+			c := struct {
+				Buffer int `json:"buffer"`
+			}{}
+
+			if err := json.Unmarshal(cfg, &c); err != nil {
+				return nil, err
+			}
+
+			return &passthrough{
+				input: make(chan *Electron, c.Buffer),
+			}, nil
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conductors, err := BuildConductors([]byte(`[
+		{"name": "test.valid"},
+		{"name": "test.passthrough", "config": {"buffer": 5}}
+	]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(conductors) != 2 {
+		t.Fatalf("expected 2 conductors, got %v", len(conductors))
+	}
+
+	if _, ok := conductors[0].(*validconductor); !ok {
+		t.Fatalf("expected validconductor, got %s", ID(conductors[0]))
+	}
+
+	pass, ok := conductors[1].(*passthrough)
+	if !ok {
+		t.Fatalf("expected passthrough, got %s", ID(conductors[1]))
+	}
+
+	if cap(pass.input) != 5 {
+		t.Fatalf("expected buffer of 5, got %v", cap(pass.input))
+	}
+}
+
+func TestBuildConductors_YAML(t *testing.T) {
+	var config []byte
+	err := RegisterConductorFactory(
+		"test.yaml",
+		func(cfg json.RawMessage) (Conductor, error) {
+			config = cfg
+			return &passthrough{input: make(chan *Electron)}, nil
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conductors, err := BuildConductors([]byte(`
+# conductors built from yaml
+- name: test.yaml
+- name: test.yaml
+  config:
+    buffer: 5
+    subject: "atoms.in"
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(conductors) != 2 {
+		t.Fatalf("expected 2 conductors, got %v", len(conductors))
+	}
+
+	if string(config) != `{"buffer":5,"subject":"atoms.in"}` {
+		t.Fatalf("unexpected config %s", config)
+	}
+}
+
+func TestBuildConductors_Errs(t *testing.T) {
+	err := RegisterConductorFactory(
+		"test.err",
+		func(cfg json.RawMessage) (Conductor, error) {
+			return nil, errors.New("factory error")
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]string{
+		"invalid json":    `{"name": "test.err"}`,
+		"unknown factory": `[{"name": "test.unknown"}]`,
+		"factory error":   `[{"name": "test.err"}]`,
+		"invalid yaml":    "- name: test.valid\n\tconfig: {}",
+	}
+
+	for name, config := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := BuildConductors([]byte(config))
+			if err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
+
+func TestRegisterConductorFactory_Errs(t *testing.T) {
+	if err := RegisterConductorFactory("", nil); err == nil {
+		t.Fatal("expected error for empty name")
+	}
+
+	if err := RegisterConductorFactory("test.nil", nil); err == nil {
+		t.Fatal("expected error for nil factory")
+	}
+}
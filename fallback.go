@@ -0,0 +1,101 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import "time"
+
+// fallback is the atom which receives the electrons of an atom which
+// cannot accept them within the handoff timeout
+type fallback struct {
+	atomID  string
+	timeout time.Duration
+}
+
+// WithAtomFallback hands electrons for the atom to the fallback atom when
+// the atom does not accept them within the timeout, such as when every
+// instance of the atom is wedged. The electrons are processed by the
+// fallback atom, which must be registered, with their AtomID unchanged.
+// Electrons forced to an atom key (see Electron.ForceAtomKey) are never
+// handed to the fallback.
+func WithAtomFallback(
+	atomID, fallbackID string,
+	timeout time.Duration,
+) Option {
+	return func(a *atomizer) error {
+		if atomID == "" || fallbackID == "" {
+			return simple("empty fallback atom id", nil)
+		}
+
+		if atomID == fallbackID {
+			return simple("atom cannot fall back to itself", nil)
+		}
+
+		if timeout <= 0 {
+			return simple("fallback timeout must be positive", nil)
+		}
+
+		if a.fallbacks == nil {
+			a.fallbacks = make(map[string]fallback)
+		}
+
+		a.fallbacks[atomID] = fallback{fallbackID, timeout}
+
+		return nil
+	}
+}
+
+// handoff pushes the instance to the atom channel, falling back to the
+// fallback atom when the atom does not accept the instance within the
+// handoff timeout, and returns false if the atomizer closed
+func (a *atomizer) handoff(inst instance, achan chan<- instance) bool {
+	fb, ok := a.fallbacks[inst.electron.AtomID]
+	if !ok || inst.electron.ForceAtomKey != "" {
+		select {
+		case <-a.ctx.Done():
+			return false
+		case achan <- inst:
+			return true
+		}
+	}
+
+	t := time.NewTimer(fb.timeout)
+	defer t.Stop()
+
+	select {
+	case <-a.ctx.Done():
+		return false
+	case achan <- inst:
+		return true
+	case <-t.C:
+	}
+
+	routed := *inst.electron
+	routed.AtomID = fb.atomID
+
+	fchan := a.route(&routed)
+	if fchan == nil {
+		// Without a registered fallback continue waiting on the atom
+		fchan = achan
+	}
+
+	a.event(func() interface{} {
+		return &Event{
+			Message:     "atom handoff timed out, falling back to " + fb.atomID,
+			ElectronID:  inst.electron.ID,
+			AtomID:      inst.electron.AtomID,
+			ConductorID: ID(inst.conductor),
+		}
+	})
+
+	select {
+	case <-a.ctx.Done():
+		return false
+	case achan <- inst:
+		return true
+	case fchan <- inst:
+		return true
+	}
+}
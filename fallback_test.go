@@ -0,0 +1,90 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestAtomizer_AtomFallback(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	gaterelease = make(chan struct{})
+	t.Cleanup(func() {
+		close(gaterelease)
+	})
+
+	primary := ID(gateatom{})
+	rec, a := recHarness(
+		ctx,
+		t,
+		WithAtomFallback(primary, ID(noopatom{}), time.Millisecond*50),
+		&gateatom{},
+		&noopatom{},
+	)
+
+	eventually(t, time.Second, func() bool {
+		return a.route(newElectron(primary, nil)) != nil &&
+			a.route(newElectron(ID(noopatom{}), nil)) != nil
+	})
+
+	// Wedge the primary atom
+	wedged := newElectron(primary, nil)
+	if _, err := rec.Send(ctx, wedged); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		e := newElectron(primary, nil)
+		start := time.Now()
+		if _, err := rec.Send(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+
+		p := rec.next(ctx, t)
+		if p.ElectronID != e.ID {
+			t.Fatalf("expected %s, got %s", e.ID, p.ElectronID)
+		}
+
+		if p.Error != nil {
+			t.Fatalf("unexpected error %v", p.Error)
+		}
+
+		if elapsed := time.Since(start); elapsed < time.Millisecond*50 {
+			t.Fatalf("expected fallback after the handoff timeout, took %s", elapsed)
+		}
+	}
+}
+
+func TestWithAtomFallback_Invalid(t *testing.T) {
+	tests := map[string]struct {
+		atomID   string
+		fallback string
+		timeout  time.Duration
+	}{
+		"empty atom":     {"", "fallback", time.Second},
+		"empty fallback": {"atom", "", time.Second},
+		"self":           {"atom", "atom", time.Second},
+		"zero timeout":   {"atom", "fallback", 0},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := WithAtomFallback(
+				test.atomID,
+				test.fallback,
+				test.timeout,
+			)(&atomizer{})
+			if err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// electronFields are the JSON keys of the electron fields
+var electronFields = func() map[string]bool {
+	fields := make(map[string]bool)
+
+	t := reflect.TypeOf(jsonElectron{})
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		fields[name] = true
+	}
+
+	return fields
+}()
+
+var (
+	aliasesMu sync.RWMutex
+	aliases   map[string]string
+)
+
+// AliasElectronField adds an alternate spelling of the JSON key of an
+// electron field which is accepted when unmarshaling electrons (i.e.
+// `atom_id` for `atomid`) so that electrons from producers using other
+// naming conventions parse correctly. Electrons are always marshaled
+// using the default keys. When both spellings are present the default
+// key is used.
+func AliasElectronField(field, alias string) error {
+	if !electronFields[field] {
+		return simple("unknown electron field "+field, nil)
+	}
+
+	if alias == "" || electronFields[alias] {
+		return simple("invalid electron field alias "+alias, nil)
+	}
+
+	aliasesMu.Lock()
+	defer aliasesMu.Unlock()
+
+	if aliases == nil {
+		aliases = make(map[string]string)
+	}
+
+	aliases[alias] = field
+
+	return nil
+}
+
+// unalias rewrites the aliased keys of the electron JSON to
+// the default keys
+func unalias(data []byte) ([]byte, error) {
+	aliasesMu.RLock()
+	defer aliasesMu.RUnlock()
+
+	if len(aliases) == 0 {
+		return data, nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	aliased := false
+	for key, value := range fields {
+		field, ok := aliases[key]
+		if !ok {
+			continue
+		}
+
+		delete(fields, key)
+		aliased = true
+
+		if _, exists := fields[field]; !exists {
+			fields[field] = value
+		}
+	}
+
+	if !aliased {
+		return data, nil
+	}
+
+	return json.Marshal(fields)
+}
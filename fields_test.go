@@ -0,0 +1,79 @@
+package engine
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestAliasElectronField(t *testing.T) {
+	t.Cleanup(func() {
+		aliasesMu.Lock()
+		aliases = nil
+		aliasesMu.Unlock()
+	})
+
+	if err := AliasElectronField("atomid", "atom_id"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := AliasElectronField("senderid", "sender_id"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		data string
+	}{
+		"default": {
+			`{"senderid":"sender","id":"id","atomid":"test.Atom"}`,
+		},
+		"alias": {
+			`{"sender_id":"sender","id":"id","atom_id":"test.Atom"}`,
+		},
+		"both": {
+			`{"senderid":"sender","id":"id","atomid":"test.Atom","atom_id":"other"}`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			e := &Electron{}
+			if err := json.Unmarshal([]byte(test.data), e); err != nil {
+				t.Fatal(err)
+			}
+
+			if e.AtomID != "test.Atom" || e.SenderID != "sender" {
+				t.Fatalf("expected aliased fields, got %+v", e)
+			}
+
+			// Electrons are always marshaled with the default keys
+			data, err := json.Marshal(e)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expected := `{"v":2,"senderid":"sender","id":"id","atomid":"test.Atom"}`
+			if string(data) != expected {
+				t.Fatalf("expected %s, got %s", expected, data)
+			}
+		})
+	}
+}
+
+func TestAliasElectronField_Invalid(t *testing.T) {
+	tests := map[string]struct {
+		field string
+		alias string
+	}{
+		"unknown field":    {"atom_id", "atomID"},
+		"empty alias":      {"atomid", ""},
+		"default as alias": {"atomid", "senderid"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := AliasElectronField(test.field, test.alias); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
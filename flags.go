@@ -0,0 +1,47 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import "context"
+
+type flagsKey struct{}
+
+// withFlags adds a copy of the flags of the electron to the context
+// of the atom instance so that each instance has its own flags
+func withFlags(ctx context.Context, e *Electron) context.Context {
+	if len(e.Flags) == 0 {
+		return ctx
+	}
+
+	return context.WithValue(ctx, flagsKey{}, copyFlags(e.Flags))
+}
+
+// FlagsFromContext returns the feature flags of the electron being
+// processed by an atom (see Electron.Flags) using the context passed to
+// the Process method of the atom. The returned flags are a copy which
+// the atom may modify. A nil map is returned when the electron has no
+// flags, for which every flag reads as false.
+func FlagsFromContext(ctx context.Context) map[string]bool {
+	if ctx == nil {
+		return nil
+	}
+
+	flags, ok := ctx.Value(flagsKey{}).(map[string]bool)
+	if !ok {
+		return nil
+	}
+
+	return copyFlags(flags)
+}
+
+func copyFlags(flags map[string]bool) map[string]bool {
+	out := make(map[string]bool, len(flags))
+	for k, v := range flags {
+		out[k] = v
+	}
+
+	return out
+}
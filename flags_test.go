@@ -0,0 +1,137 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+)
+
+var (
+	// flagarrived receives each flagatom instance once it started
+	// and flagrelease blocks the instances until it is closed
+	flagarrived chan struct{}
+	flagrelease chan struct{}
+)
+
+// flagatom returns the flags of its electron once every
+// concurrent instance has read its flags
+type flagatom struct{}
+
+func (*flagatom) Process(
+	ctx context.Context,
+	conductor Conductor,
+	electron *Electron,
+) ([]byte, error) {
+	flags := FlagsFromContext(ctx)
+
+	// Modifying the returned flags does not affect other readers
+	FlagsFromContext(ctx)["mutated"] = true
+
+	flagarrived <- struct{}{}
+	<-flagrelease
+
+	return json.Marshal(flags)
+}
+
+func TestAtomizer_Flags(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	flagarrived = make(chan struct{}, 2)
+	flagrelease = make(chan struct{})
+
+	atomID := ID(flagatom{})
+	rec, a := recHarness(ctx, t, WithReplicas(atomID, 2), &flagatom{})
+
+	eventually(t, time.Second, func() bool {
+		return a.route(newElectron(atomID, nil)) != nil
+	})
+
+	// Partition the electrons to different replicas
+	// so that they are processed concurrently
+	first := newElectron(atomID, nil)
+	first.PartitionKey = "0"
+	first.Flags = map[string]bool{"new-path": true}
+
+	second := newElectron(atomID, nil)
+	second.Flags = map[string]bool{"new-path": false, "verbose": true}
+	for i := 1; ; i++ {
+		second.PartitionKey = strconv.Itoa(i)
+		if a.route(second) != a.route(first) {
+			break
+		}
+	}
+
+	for _, e := range []*Electron{first, second} {
+		if _, err := rec.Send(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-ctx.Done():
+			t.Fatal("expected concurrent instances")
+		case <-flagarrived:
+		}
+	}
+	close(flagrelease)
+
+	expected := map[string]map[string]bool{
+		first.ID:  first.Flags,
+		second.ID: second.Flags,
+	}
+
+	for i := 0; i < 2; i++ {
+		p := rec.next(ctx, t)
+
+		var flags map[string]bool
+		if err := json.Unmarshal(p.Result, &flags); err != nil {
+			t.Fatal(err)
+		}
+
+		want := expected[p.ElectronID]
+		if len(flags) != len(want) {
+			t.Fatalf("expected flags %v, got %v", want, flags)
+		}
+
+		for k, v := range want {
+			if flags[k] != v {
+				t.Fatalf("expected flags %v, got %v", want, flags)
+			}
+		}
+	}
+}
+
+func TestFlagsFromContext_None(t *testing.T) {
+	if flags := FlagsFromContext(context.Background()); flags["any"] {
+		t.Fatal("expected unset flag to read as false")
+	}
+}
+
+func TestElectron_Flags_RoundTrip(t *testing.T) {
+	e := newElectron("atom", nil)
+	e.Flags = map[string]bool{"new-path": true, "verbose": false}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := &Electron{}
+	if err = json.Unmarshal(data, out); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(out.Flags) != 2 || !out.Flags["new-path"] || out.Flags["verbose"] {
+		t.Fatalf("expected flags %v, got %v", e.Flags, out.Flags)
+	}
+}
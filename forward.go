@@ -0,0 +1,158 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"strconv"
+)
+
+// ErrMaxHops is set as the error of an electron which was not forwarded
+// because it has already been forwarded the maximum number of times
+var ErrMaxHops = errors.New("electron exceeded max hops")
+
+// forwarding is the routing table of atoms hosted on other nodes
+type forwarding struct {
+	routes  map[string]Conductor
+	maxHops int
+}
+
+// WithForwarding forwards electrons for atoms which are not registered
+// locally to the outbound conductor for the atom in the routing table,
+// enabling a mesh of atomizer nodes. The completion of a forwarded electron
+// is relayed to the conductor it was received from. Electrons are rejected
+// with ErrMaxHops once forwarded maxHops times to prevent forwarding loops.
+func WithForwarding(routes map[string]Conductor, maxHops int) Option {
+	return func(a *atomizer) error {
+		if maxHops < 1 {
+			return simple("max hops must be at least 1", nil)
+		}
+
+		table := make(map[string]Conductor, len(routes))
+		for atomID, conductor := range routes {
+			if conductor == nil {
+				return simple("nil forwarding conductor "+atomID, nil)
+			}
+
+			table[atomID] = conductor
+		}
+
+		a.forwarding = &forwarding{table, maxHops}
+		return nil
+	}
+}
+
+// forward sends the instance electron to the outbound conductor hosting
+// its atom and returns false when the atom has no route
+func (a *atomizer) forward(inst instance) bool {
+	if a.forwarding == nil {
+		return false
+	}
+
+	out, ok := a.forwarding.routes[inst.electron.AtomID]
+	if !ok {
+		return false
+	}
+
+	if inst.electron.Hops >= a.forwarding.maxHops {
+		a.reject(inst, StageDistribution, &Error{
+			Event: &Event{
+				Message: "electron not forwarded after " +
+					strconv.Itoa(inst.electron.Hops) + " hops",
+				ElectronID:  inst.electron.ID,
+				AtomID:      inst.electron.AtomID,
+				ConductorID: ID(inst.conductor),
+			},
+			Internal: ErrMaxHops,
+		})
+
+		return true
+	}
+
+	fwd := *inst.electron
+	fwd.Hops++
+
+	a.routine(func() {
+		a.relay(inst, out, &fwd)
+	})
+
+	return true
+}
+
+// relay sends the forwarded electron through the outbound conductor and
+// completes its result with the conductor the electron was received from
+func (a *atomizer) relay(inst instance, out Conductor, fwd *Electron) {
+	defer a.inflight.done(inst.electron)
+
+	results, err := out.Send(a.ctx, fwd)
+	if err != nil {
+		a.reject(inst, StageDistribution, &Error{
+			Event: &Event{
+				Message:     "error forwarding electron to " + ID(out),
+				ElectronID:  inst.electron.ID,
+				AtomID:      inst.electron.AtomID,
+				ConductorID: ID(inst.conductor),
+			},
+			Internal: err,
+		})
+
+		return
+	}
+
+	a.event(func() interface{} {
+		return &Event{
+			Message:     "electron forwarded to " + ID(out),
+			ElectronID:  inst.electron.ID,
+			AtomID:      inst.electron.AtomID,
+			ConductorID: ID(inst.conductor),
+		}
+	})
+
+	// A nil results channel indicates the outbound conductor does
+	// not return results so the electron is fire-and-forget
+	if results == nil || !inst.electron.ExpectsReply() {
+		return
+	}
+
+	// The wait for the result is bounded by the electron timeout
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if !inst.deadline.IsZero() {
+		ctx, cancel = context.WithDeadline(a.ctx, inst.deadline)
+	} else {
+		timeout, _ := inst.electron.timeout()
+		ctx, cancel = _ctxT(a.ctx, &timeout)
+	}
+	defer cancel()
+
+	select {
+	case <-ctx.Done():
+		if a.ctx.Err() != nil {
+			return
+		}
+
+		a.reject(inst, StageDistribution, &Error{
+			Event: &Event{
+				Message:     "forwarded electron timed out waiting for " + ID(out),
+				ElectronID:  inst.electron.ID,
+				AtomID:      inst.electron.AtomID,
+				ConductorID: ID(inst.conductor),
+			},
+			Internal: ctx.Err(),
+		})
+	case p, ok := <-results:
+		if !ok || p == nil {
+			return
+		}
+
+		if err = inst.conductor.Complete(a.ctx, p); err != nil {
+			a.err(func() error {
+				return err
+			})
+		}
+	}
+}
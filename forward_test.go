@@ -0,0 +1,161 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAtomizer_Forwarding(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	out := newRecorder()
+	rec, _ := recHarness(
+		ctx,
+		t,
+		WithForwarding(map[string]Conductor{"remote.Atom": out}, 2),
+	)
+
+	e := newElectron("remote.Atom", nil)
+	if _, err := rec.Send(ctx, e); err != nil {
+		t.Fatal(err)
+	}
+
+	var fwd *Electron
+	select {
+	case <-ctx.Done():
+		t.Fatal("electron not forwarded")
+	case fwd = <-out.input:
+	}
+
+	if fwd.ID != e.ID || fwd.Hops != 1 {
+		t.Fatalf("expected forwarded electron with 1 hop, got %+v", fwd)
+	}
+
+	// Complete the electron on the remote node
+	out.completions <- &Properties{
+		ElectronID: fwd.ID,
+		AtomID:     fwd.AtomID,
+		Result:     []byte("remote"),
+	}
+
+	p := rec.next(ctx, t)
+	if p.ElectronID != e.ID || string(p.Result) != "remote" {
+		t.Fatalf("expected remote completion, got %+v", p)
+	}
+
+	// Electrons which reached the max hops are not forwarded again
+	looped := newElectron("remote.Atom", nil)
+	looped.Hops = 2
+
+	if _, err := rec.Send(ctx, looped); err != nil {
+		t.Fatal(err)
+	}
+
+	p = rec.next(ctx, t)
+	if !errors.Is(p.Error, ErrMaxHops) {
+		t.Fatalf("expected max hops error, got %v", p.Error)
+	}
+
+	select {
+	case fwd = <-out.input:
+		t.Fatalf("unexpected forward of %s", fwd.ID)
+	default:
+	}
+}
+
+// dropconductor forwards electrons without returning their results
+type dropconductor struct {
+	*recorder
+}
+
+func (d dropconductor) Send(
+	ctx context.Context,
+	electron *Electron,
+) (<-chan *Properties, error) {
+	_, err := d.recorder.Send(ctx, electron)
+	return nil, err
+}
+
+func TestAtomizer_Forwarding_FireAndForget(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	out := dropconductor{newRecorder()}
+	rec, a := recHarness(
+		ctx,
+		t,
+		WithForwarding(map[string]Conductor{"remote.Atom": out}, 2),
+	)
+
+	e := newElectron("remote.Atom", nil)
+	if _, err := rec.Send(ctx, e); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-ctx.Done():
+		t.Fatal("electron not forwarded")
+	case <-out.input:
+	}
+
+	// The electron is no longer in flight once forwarded
+	eventually(t, time.Second, func() bool {
+		a.inflight.mu.Lock()
+		defer a.inflight.mu.Unlock()
+
+		return len(a.inflight.pending) == 0
+	})
+}
+
+func TestAtomizer_Forwarding_Timeout(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	out := newRecorder()
+	rec, _ := recHarness(
+		ctx,
+		t,
+		WithForwarding(map[string]Conductor{"remote.Atom": out}, 2),
+	)
+
+	timeout := time.Millisecond * 50
+	e := newElectron("remote.Atom", nil)
+	e.Timeout = &timeout
+
+	if _, err := rec.Send(ctx, e); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-ctx.Done():
+		t.Fatal("electron not forwarded")
+	case <-out.input:
+	}
+
+	// The remote node never completes the electron
+	p := rec.next(ctx, t)
+	if p.ElectronID != e.ID || !errors.Is(p.Error, context.DeadlineExceeded) {
+		t.Fatalf("expected forwarding timeout, got %+v", p)
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import "sync"
+
+// gate is a toggle which can be waited on in a select for
+// either the open or the closed state
+type gate struct {
+	mu     sync.Mutex
+	isOpen bool
+	opened chan struct{}
+	shut   chan struct{}
+}
+
+func newGate(open bool) *gate {
+	g := &gate{
+		opened: make(chan struct{}),
+		shut:   make(chan struct{}),
+	}
+
+	if open {
+		g.isOpen = true
+		close(g.opened)
+	} else {
+		close(g.shut)
+	}
+
+	return g
+}
+
+// set opens or closes the gate, returning true if the state changed
+func (g *gate) set(open bool) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.isOpen == open {
+		return false
+	}
+
+	g.isOpen = open
+	if open {
+		g.shut = make(chan struct{})
+		close(g.opened)
+	} else {
+		g.opened = make(chan struct{})
+		close(g.shut)
+	}
+
+	return true
+}
+
+// open returns a channel which is closed while the gate is open
+func (g *gate) open() <-chan struct{} {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.opened
+}
+
+// closed returns a channel which is closed while the gate is closed
+func (g *gate) closed() <-chan struct{} {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.shut
+}
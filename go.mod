@@ -12,4 +12,5 @@ require (
 	github.com/mohae/deepcopy v0.0.0-20170929034955-c48cc78d4826
 	github.com/pkg/errors v0.9.1
 	golang.org/x/text v0.3.2 // indirect
+	sigs.k8s.io/yaml v1.3.0
 )
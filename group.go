@@ -0,0 +1,208 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrGroupFailed is set as the error of the members of an electron group
+// which succeeded when another member of the group failed
+var ErrGroupFailed = errors.New("electron group failed")
+
+// GroupCompleter is an optional interface for conductors which commit
+// electrons in groups. Electrons with a GroupID received from a
+// GroupCompleter are held by the atomizer until every member of the group
+// has completed and then completed together through CompleteGroup rather
+// than individually through Complete. If any member of the group failed
+// then every member of the group is reported as failed.
+type GroupCompleter interface {
+	CompleteGroup(
+		ctx context.Context,
+		groupID string,
+		properties []*Properties,
+	) error
+}
+
+// ErrGroupIncomplete is set as the error of the members of an electron
+// group which succeeded when the group timed out before every member of
+// the group completed
+var ErrGroupIncomplete = errors.New("electron group incomplete")
+
+// DefaultGroupTimeout is the time an electron group waits for all of its
+// members to complete, from the completion of its first member, before
+// the partial group is completed
+const DefaultGroupTimeout = time.Minute * 5
+
+// WithGroupTimeout sets the time an electron group waits for all of its
+// members to complete, from the completion of its first member, defaulting
+// to DefaultGroupTimeout. Once the timeout passes the members which have
+// completed are completed through CompleteGroup as a partial group and the
+// members which succeeded are failed with ErrGroupIncomplete. Members
+// completing after the timeout start a new partial group.
+func WithGroupTimeout(timeout time.Duration) Option {
+	return func(a *atomizer) error {
+		if timeout <= 0 {
+			return simple("invalid group timeout "+timeout.String(), nil)
+		}
+
+		a.groups.timeout = timeout
+		return nil
+	}
+}
+
+type groupKey struct {
+	conductor string
+	group     string
+}
+
+// pendingGroup holds the completed members of a group
+type pendingGroup struct {
+	members []*Properties
+	timer   *time.Timer
+}
+
+// groups holds the completions of electron groups
+type groups struct {
+	mu      sync.Mutex
+	pending map[groupKey]*pendingGroup
+	timeout time.Duration
+}
+
+// complete holds the properties until every member of the group has
+// completed then completes the group through the conductor. Groups which
+// time out are completed partially, reporting the timeout through expired.
+func (g *groups) complete(
+	ctx context.Context,
+	gc GroupCompleter,
+	e *Electron,
+	p *Properties,
+	expired func(err error),
+) error {
+	key := groupKey{ID(gc), e.GroupID}
+
+	// Hold a copy of the properties since the group failure is set on
+	// the members while their instances may still be finishing
+	member := *p
+
+	g.mu.Lock()
+	if g.pending == nil {
+		g.pending = make(map[groupKey]*pendingGroup)
+	}
+
+	pg, ok := g.pending[key]
+	if !ok {
+		timeout := g.timeout
+		if timeout <= 0 {
+			timeout = DefaultGroupTimeout
+		}
+
+		pg = &pendingGroup{}
+		pg.timer = time.AfterFunc(timeout, func() {
+			g.expire(ctx, gc, e, key, pg, expired)
+		})
+
+		g.pending[key] = pg
+	}
+
+	pg.members = append(pg.members, &member)
+	if len(pg.members) < e.GroupSize {
+		g.mu.Unlock()
+		return nil
+	}
+
+	delete(g.pending, key)
+	pg.timer.Stop()
+	g.mu.Unlock()
+
+	var failed bool
+	for _, m := range pg.members {
+		if m.Error != nil {
+			failed = true
+			break
+		}
+	}
+
+	if failed {
+		for _, m := range pg.members {
+			if m.Error != nil {
+				continue
+			}
+
+			m.Error = &Error{
+				Event: &Event{
+					Message:    "group member failed",
+					ElectronID: m.ElectronID,
+					AtomID:     m.AtomID,
+				},
+				Internal: ErrGroupFailed,
+			}
+		}
+	}
+
+	return gc.CompleteGroup(ctx, e.GroupID, pg.members)
+}
+
+// expire completes the group with the members which completed
+// before the group timed out if it is still pending
+func (g *groups) expire(
+	ctx context.Context,
+	gc GroupCompleter,
+	e *Electron,
+	key groupKey,
+	pg *pendingGroup,
+	expired func(err error),
+) {
+	g.mu.Lock()
+	if g.pending[key] != pg {
+		// The group already completed
+		g.mu.Unlock()
+		return
+	}
+
+	delete(g.pending, key)
+	g.mu.Unlock()
+
+	for _, m := range pg.members {
+		if m.Error != nil {
+			continue
+		}
+
+		m.Error = &Error{
+			Event: &Event{
+				Message:    "group timed out",
+				ElectronID: m.ElectronID,
+				AtomID:     m.AtomID,
+			},
+			Internal: ErrGroupIncomplete,
+		}
+	}
+
+	expired(&Error{
+		Event: &Event{
+			Message: "group " + e.GroupID + " timed out with " +
+				strconv.Itoa(len(pg.members)) + " of " +
+				strconv.Itoa(e.GroupSize) + " members completed",
+			ConductorID: key.conductor,
+		},
+		Internal: ErrGroupIncomplete,
+	})
+
+	err := gc.CompleteGroup(ctx, e.GroupID, pg.members)
+	if err != nil {
+		expired(&Error{
+			Event: &Event{
+				Message:     "error completing group " + e.GroupID,
+				ConductorID: key.conductor,
+			},
+			Internal: err,
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type groupcompletion struct {
+	group      string
+	properties []*Properties
+}
+
+// grouprecorder is a recorder which completes electron groups
+type grouprecorder struct {
+	*recorder
+	groups chan groupcompletion
+}
+
+func (g *grouprecorder) CompleteGroup(
+	ctx context.Context,
+	groupID string,
+	properties []*Properties,
+) error {
+	g.groups <- groupcompletion{groupID, properties}
+	return nil
+}
+
+func TestAtomizer_GroupCompletion(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	rec := &grouprecorder{
+		recorder: newRecorder(),
+		groups:   make(chan groupcompletion, 10),
+	}
+
+	a, err := Atomize(ctx, rec, &noopatom{}, &failatom{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = a.Exec(); err != nil {
+		t.Fatal(err)
+	}
+
+	success := newElectron(ID(noopatom{}), nil)
+	failure := newElectron(ID(failatom{}), nil)
+
+	for _, e := range []*Electron{success, failure} {
+		e.GroupID = "group"
+		e.GroupSize = 2
+
+		if _, err = rec.Send(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var completion groupcompletion
+	select {
+	case <-ctx.Done():
+		t.Fatal("context closed, test failed")
+	case completion = <-rec.groups:
+	}
+
+	if completion.group != "group" {
+		t.Fatalf("expected group [group], got [%s]", completion.group)
+	}
+
+	if len(completion.properties) != 2 {
+		t.Fatalf("expected 2 members, got %v", len(completion.properties))
+	}
+
+	for _, p := range completion.properties {
+		if p.Error == nil {
+			t.Fatalf("expected electron %s to be failed", p.ElectronID)
+		}
+
+		if p.ElectronID == success.ID && !errors.Is(p.Error, ErrGroupFailed) {
+			t.Fatalf("expected ErrGroupFailed, got %s", p.Error)
+		}
+	}
+
+	select {
+	case p := <-rec.completions:
+		t.Fatalf("unexpected individual completion %s", p.ElectronID)
+	default:
+	}
+}
+
+func TestAtomizer_GroupTimeout(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	rec := &grouprecorder{
+		recorder: newRecorder(),
+		groups:   make(chan groupcompletion, 10),
+	}
+
+	a, err := Atomize(
+		ctx,
+		rec,
+		WithGroupTimeout(time.Millisecond*50),
+		&noopatom{},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = a.Exec(); err != nil {
+		t.Fatal(err)
+	}
+
+	errs := a.Errors(10)
+
+	// Only one of the three members of the group is sent
+	e := newElectron(ID(noopatom{}), nil)
+	e.GroupID = "partial"
+	e.GroupSize = 3
+
+	if _, err = rec.Send(ctx, e); err != nil {
+		t.Fatal(err)
+	}
+
+	var completion groupcompletion
+	select {
+	case <-ctx.Done():
+		t.Fatal("context closed, test failed")
+	case completion = <-rec.groups:
+	}
+
+	if completion.group != "partial" || len(completion.properties) != 1 {
+		t.Fatalf("expected partial group of 1, got %+v", completion)
+	}
+
+	if !errors.Is(completion.properties[0].Error, ErrGroupIncomplete) {
+		t.Fatalf("expected ErrGroupIncomplete, got %v", completion.properties[0].Error)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			t.Fatal("expected group timeout error")
+		case err = <-errs:
+		}
+
+		if errors.Is(err, ErrGroupIncomplete) {
+			break
+		}
+	}
+}
+
+func TestWithGroupTimeout_Invalid(t *testing.T) {
+	if err := WithGroupTimeout(0)(&atomizer{}); err == nil {
+		t.Fatal("expected error")
+	}
+}
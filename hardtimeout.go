@@ -0,0 +1,79 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrHardTimeout is set as the error of an electron which was forcibly
+// completed because its atom exceeded the hard timeout of the atomizer
+var ErrHardTimeout = errors.New("hard timeout exceeded")
+
+// WithHardTimeout sets the maximum lifetime of an atom instance regardless
+// of the timeout of its electron. Instances exceeding the hard timeout have
+// their context cancelled and their electron completed to the conductor
+// with ErrHardTimeout so that no electron is orphaned by an atom which
+// never returns. Any later completion by the atom is discarded.
+func WithHardTimeout(timeout time.Duration) Option {
+	return func(a *atomizer) error {
+		if timeout <= 0 {
+			return simple("hard timeout must be positive", nil)
+		}
+
+		a.hardTimeout = timeout
+		return nil
+	}
+}
+
+// expire forcibly completes an instance which exceeded the hard timeout
+func (a *atomizer) expire(
+	inst *instance,
+	atomID string,
+	start time.Time,
+	cancel context.CancelFunc,
+	complete func(ctx context.Context, p *Properties) error,
+) {
+	cancel()
+
+	err := &Error{
+		Event: &Event{
+			Message:     "hard timeout exceeded",
+			ElectronID:  inst.electron.ID,
+			AtomID:      atomID,
+			ConductorID: ID(inst.conductor),
+		},
+		Internal: ErrHardTimeout,
+	}
+
+	completion := complete(a.ctx, &Properties{
+		ElectronID:    inst.electron.ID,
+		AtomID:        atomID,
+		CorrelationID: inst.electron.CorrelationID,
+		Start:         start,
+		End:           time.Now(),
+		Error:         err,
+		Status:        StatusTimedOut,
+		Timeout:       a.hardTimeout,
+		Partials:      inst.partials.get(),
+	})
+
+	if completion != nil {
+		a.err(func() error {
+			return completion
+		})
+	}
+
+	// The atom may never return so the electron
+	// is no longer tracked as in-flight
+	a.inflight.done(inst.electron)
+
+	a.err(func() error {
+		return err
+	})
+}
@@ -0,0 +1,84 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+var (
+	// stuck blocks stuckatom until it is closed
+	stuck   chan struct{}
+	stuckMu sync.Mutex
+)
+
+// stick replaces the channel blocking stuckatom; abandoned instances
+// of earlier tests may still be reading the previous channel
+func stick() chan struct{} {
+	stuckMu.Lock()
+	defer stuckMu.Unlock()
+
+	stuck = make(chan struct{})
+	return stuck
+}
+
+// stuckatom ignores its context and never returns until released
+type stuckatom struct{}
+
+func (*stuckatom) Process(
+	ctx context.Context,
+	conductor Conductor,
+	electron *Electron,
+) ([]byte, error) {
+	stuckMu.Lock()
+	release := stuck
+	stuckMu.Unlock()
+
+	<-release
+	return []byte("late"), nil
+}
+
+func TestAtomizer_HardTimeout(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	release := stick()
+	rec, _ := recHarness(
+		ctx,
+		t,
+		WithHardTimeout(time.Millisecond*50),
+		&stuckatom{},
+	)
+
+	e := newElectron(ID(stuckatom{}), nil)
+	if _, err := rec.Send(ctx, e); err != nil {
+		t.Fatal(err)
+	}
+
+	p := rec.next(ctx, t)
+	if p.ElectronID != e.ID {
+		t.Fatalf("expected electron %s, got %s", e.ID, p.ElectronID)
+	}
+
+	if !errors.Is(p.Error, ErrHardTimeout) {
+		t.Fatalf("expected hard timeout, got %v", p.Error)
+	}
+
+	// Release the atom and ensure the late
+	// completion is not sent to the conductor
+	close(release)
+
+	select {
+	case p = <-rec.completions:
+		t.Fatalf("unexpected second completion %v", p)
+	case <-time.After(time.Millisecond * 50):
+	}
+}
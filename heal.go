@@ -0,0 +1,138 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultHealInterval is the minimum interval between the heals of a
+// conductor whose heartbeat fails
+const DefaultHealInterval = time.Second
+
+// Heartbeater is an optional interface for conductors which report the
+// liveness of their connection. The heartbeat channel is monitored for
+// as long as the conductor is registered and when it closes or emits an
+// error the conductor is healed by registering it again, re-initializing
+// its receiver. A nil error on the channel indicates a healthy heartbeat.
+type Heartbeater interface {
+	Heartbeat(ctx context.Context) <-chan error
+}
+
+// healing limits how often a conductor is healed
+type healing struct {
+	interval time.Duration
+
+	mu     sync.Mutex
+	healed map[string]time.Time
+}
+
+// WithHealInterval sets the minimum interval between the heals of a
+// conductor whose heartbeat fails (see Heartbeater) so that a flapping
+// conductor is not registered again in a tight loop
+func WithHealInterval(interval time.Duration) Option {
+	return func(a *atomizer) error {
+		if interval <= 0 {
+			return simple("heal interval must be positive", nil)
+		}
+
+		a.healing.interval = interval
+		return nil
+	}
+}
+
+// pulse monitors the heartbeat of the conductor until the conductor is
+// deregistered, healing the conductor when its heartbeat fails
+func (a *atomizer) pulse(
+	ctx context.Context,
+	conductor Conductor,
+	hb Heartbeater,
+) {
+	beats := hb.Heartbeat(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case err, ok := <-beats:
+			if ok && err == nil {
+				continue
+			}
+
+			if !ok {
+				err = simple("heartbeat closed", nil)
+			}
+
+			a.heal(ctx, conductor, err)
+			return
+		}
+	}
+}
+
+// heal registers the conductor again once the heal interval has passed
+// since it was last healed so that its stale receiver can be collected
+func (a *atomizer) heal(ctx context.Context, conductor Conductor, err error) {
+	id := ID(conductor)
+
+	a.err(func() error {
+		return &Error{
+			Event: &Event{
+				Message:     "conductor heartbeat failed",
+				ConductorID: id,
+			},
+			Internal: err,
+		}
+	})
+
+	wait := a.healing.wait(id)
+	if wait > 0 {
+		t := time.NewTimer(wait)
+		defer t.Stop()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+
+	a.event(func() interface{} {
+		return &Event{
+			Message:     "healing conductor",
+			ConductorID: id,
+		}
+	})
+
+	a.reregister(id, conductor)
+}
+
+// wait returns the time left before the conductor can be healed
+// and reserves the next heal of the conductor
+func (h *healing) wait(id string) time.Duration {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	interval := h.interval
+	if interval <= 0 {
+		interval = DefaultHealInterval
+	}
+
+	if h.healed == nil {
+		h.healed = make(map[string]time.Time)
+	}
+
+	now := time.Now()
+	next := h.healed[id].Add(interval)
+	if next.Before(now) {
+		next = now
+	}
+
+	h.healed[id] = next
+
+	return next.Sub(now)
+}
@@ -0,0 +1,127 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// beatrecorder is a recorder whose heartbeat fails the first
+// failures times it is monitored
+type beatrecorder struct {
+	*recorder
+	failures  int32
+	beats     int32
+	receivers int32
+}
+
+func (b *beatrecorder) Receive(ctx context.Context) <-chan *Electron {
+	atomic.AddInt32(&b.receivers, 1)
+	return b.recorder.Receive(ctx)
+}
+
+func (b *beatrecorder) Heartbeat(ctx context.Context) <-chan error {
+	beats := make(chan error, 2)
+	beats <- nil
+
+	if atomic.AddInt32(&b.beats, 1) <= atomic.LoadInt32(&b.failures) {
+		beats <- errors.New("heartbeat missed")
+	}
+
+	return beats
+}
+
+func TestAtomizer_Heal(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	beater := &beatrecorder{recorder: newRecorder(), failures: 1}
+
+	mizer, err := Atomize(ctx, &noopatom{}, beater)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, _ := mizer.(*atomizer)
+	events := a.Events(1000)
+	if err = a.Exec(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The failed heartbeat re-registers the conductor which
+	// re-establishes its receiver and heartbeat
+	eventually(t, time.Second, func() bool {
+		return atomic.LoadInt32(&beater.receivers) == 2 &&
+			atomic.LoadInt32(&beater.beats) == 2
+	})
+
+	for healed := false; !healed; {
+		select {
+		case <-ctx.Done():
+			t.Fatal("expected heal event")
+		case e := <-events:
+			ev, ok := e.(*Event)
+			healed = ok && ev.Message == "healing conductor" &&
+				ev.ConductorID == ID(beater)
+		}
+	}
+
+	eventually(t, time.Second, func() bool {
+		return a.route(newElectron(ID(noopatom{}), nil)) != nil
+	})
+
+	p := sendAndWait(ctx, t, beater, newElectron(ID(noopatom{}), nil))
+	if p.Error != nil {
+		t.Fatal(p.Error)
+	}
+
+	if n := atomic.LoadInt32(&beater.receivers); n != 2 {
+		t.Fatalf("expected the recovered conductor to stay registered, got %v receivers", n)
+	}
+}
+
+func TestAtomizer_Heal_Flapping(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	beater := &beatrecorder{recorder: newRecorder(), failures: 2}
+
+	_, a := recHarness(ctx, t, WithHealInterval(time.Millisecond*300))
+	if err := a.Register(beater); err != nil {
+		t.Fatal(err)
+	}
+
+	eventually(t, time.Second, func() bool {
+		return atomic.LoadInt32(&beater.receivers) == 2
+	})
+
+	// The second heal waits for the heal interval
+	time.Sleep(time.Millisecond * 100)
+	if n := atomic.LoadInt32(&beater.receivers); n != 2 {
+		t.Fatalf("expected the heal to wait for the interval, got %v receivers", n)
+	}
+
+	eventually(t, time.Second, func() bool {
+		return atomic.LoadInt32(&beater.receivers) == 3
+	})
+}
+
+func TestWithHealInterval_Invalid(t *testing.T) {
+	if err := WithHealInterval(0)(&atomizer{}); err == nil {
+		t.Fatal("expected error")
+	}
+}
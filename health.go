@@ -0,0 +1,203 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Pinger is an optional interface for conductors which can report on the
+// health of their connection. Ping is called periodically when health
+// checks are enabled (see WithHealthChecks) to detect conductors which
+// are connected but no longer delivering electrons.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// ConductorHealth is the health of a conductor as of its last Ping
+type ConductorHealth struct {
+	// Healthy indicates if the last Ping succeeded
+	Healthy bool
+
+	// Error is the error returned by the last Ping
+	Error error
+
+	// Checked is the time of the last Ping
+	Checked time.Time
+
+	// Failures is the number of consecutive failed pings
+	Failures int
+}
+
+// healthChecks tracks the health of the conductors implementing Pinger
+type healthChecks struct {
+	interval   time.Duration
+	reregister bool
+
+	mu     sync.RWMutex
+	status map[string]*ConductorHealth
+}
+
+// WithHealthChecks pings the registered conductors which implement
+// Pinger on the interval and reports their health through Health. When
+// reregister is set a conductor which becomes unhealthy, or whose
+// receiver closes, is deregistered and registered again to re-establish
+// its receiver.
+func WithHealthChecks(interval time.Duration, reregister bool) Option {
+	return func(a *atomizer) error {
+		if interval <= 0 {
+			return simple("health check interval must be positive", nil)
+		}
+
+		a.health = &healthChecks{
+			interval:   interval,
+			reregister: reregister,
+			status:     make(map[string]*ConductorHealth),
+		}
+
+		return nil
+	}
+}
+
+// Health returns the health of each conductor implementing Pinger as
+// of its last health check keyed by conductor ID. The map is empty when
+// health checks are not enabled.
+func (a *atomizer) Health() map[string]ConductorHealth {
+	health := make(map[string]ConductorHealth)
+	if a.health == nil {
+		return health
+	}
+
+	a.health.mu.RLock()
+	defer a.health.mu.RUnlock()
+
+	for id, h := range a.health.status {
+		health[id] = *h
+	}
+
+	return health
+}
+
+// check pings the conductors on the health check interval
+func (a *atomizer) check() {
+	ticker := time.NewTicker(a.health.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-a.ctx.Done():
+			return
+		case <-ticker.C:
+			a.conductorsMu.RLock()
+			pingers := make(map[string]Pinger, len(a.conductors))
+			for id, c := range a.conductors {
+				var p Pinger
+				if conductorAs(c, &p) {
+					pingers[id] = p
+				}
+			}
+			a.conductorsMu.RUnlock()
+
+			for id, p := range pingers {
+				a.ping(id, p)
+			}
+		}
+	}
+}
+
+// ping checks the health of the conductor and updates its status
+func (a *atomizer) ping(id string, p Pinger) {
+	ctx, cancel := context.WithTimeout(a.ctx, a.health.interval)
+	defer cancel()
+
+	err := a.safePing(ctx, p)
+
+	a.health.mu.Lock()
+	h, ok := a.health.status[id]
+	if !ok {
+		h = &ConductorHealth{Healthy: true}
+		a.health.status[id] = h
+	}
+
+	wasHealthy := h.Healthy
+	h.Healthy = err == nil
+	h.Error = err
+	h.Checked = time.Now()
+	if err != nil {
+		h.Failures++
+	} else {
+		h.Failures = 0
+	}
+	a.health.mu.Unlock()
+
+	switch {
+	case err != nil && wasHealthy:
+		a.err(func() error {
+			return &Error{
+				Event: &Event{
+					Message:     "conductor unhealthy",
+					ConductorID: id,
+				},
+				Internal: err,
+			}
+		})
+
+		if c, ok := p.(Conductor); ok && a.health.reregister {
+			a.reregister(id, c)
+		}
+	case err == nil && !wasHealthy:
+		a.event(func() interface{} {
+			return &Event{
+				Message:     "conductor healthy",
+				ConductorID: id,
+			}
+		})
+	}
+}
+
+// safePing pings the conductor recovering any panic as an error
+func (a *atomizer) safePing(ctx context.Context, p Pinger) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = ptoe(r)
+		}
+	}()
+
+	return p.Ping(ctx)
+}
+
+// reregister deregisters the conductor and registers it again so
+// its receiver is re-established
+func (a *atomizer) reregister(id string, c Conductor) {
+	a.transition(id, ConnReconnecting)
+
+	a.event(func() interface{} {
+		return &Event{
+			Message:     "re-registering unhealthy conductor",
+			ConductorID: id,
+		}
+	})
+
+	err := a.deregister(id)
+	if err == nil {
+		err = a.Register(c)
+	}
+
+	if err != nil {
+		a.transition(id, ConnFailed)
+		a.err(func() error {
+			return &Error{
+				Event: &Event{
+					Message:     "unable to re-register conductor",
+					ConductorID: id,
+				},
+				Internal: err,
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// pingrecorder is a recorder which fails its health
+// checks once failing is set
+type pingrecorder struct {
+	*recorder
+	failing   int32
+	receivers int32
+}
+
+func (p *pingrecorder) Receive(ctx context.Context) <-chan *Electron {
+	atomic.AddInt32(&p.receivers, 1)
+	return p.recorder.Receive(ctx)
+}
+
+func (p *pingrecorder) Ping(ctx context.Context) error {
+	if atomic.LoadInt32(&p.failing) == 1 {
+		return errors.New("connection lost")
+	}
+
+	return nil
+}
+
+func TestAtomizer_HealthChecks(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	pinger := &pingrecorder{recorder: newRecorder()}
+
+	_, a := recHarness(
+		ctx,
+		t,
+		WithHealthChecks(time.Millisecond*10, true),
+		pinger,
+	)
+
+	eventually(t, time.Second, func() bool {
+		h, ok := a.Health()[ID(pinger)]
+		return ok && h.Healthy &&
+			atomic.LoadInt32(&pinger.receivers) == 1
+	})
+
+	atomic.StoreInt32(&pinger.failing, 1)
+
+	eventually(t, time.Second, func() bool {
+		h := a.Health()[ID(pinger)]
+		return !h.Healthy && h.Error != nil
+	})
+
+	// The unhealthy conductor is re-registered which
+	// re-establishes its receiver
+	eventually(t, time.Second, func() bool {
+		return atomic.LoadInt32(&pinger.receivers) == 2
+	})
+
+	atomic.StoreInt32(&pinger.failing, 0)
+
+	eventually(t, time.Second, func() bool {
+		h := a.Health()[ID(pinger)]
+		return h.Healthy && h.Failures == 0
+	})
+
+	if _, ok := a.Health()[ID(newRecorder())]; ok {
+		t.Fatal("expected no health for conductor without Ping")
+	}
+}
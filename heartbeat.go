@@ -0,0 +1,115 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import (
+	"context"
+	"time"
+)
+
+// DeadlineExtender is an optional interface for conductors which lease
+// electrons to the atomizer (i.e. visibility timeouts or acknowledgment
+// deadlines). ExtendDeadline is called each time an atom processing an
+// electron from the conductor calls Heartbeat.
+type DeadlineExtender interface {
+	ExtendDeadline(ctx context.Context, electron *Electron) error
+}
+
+type livenessKey struct{}
+
+// liveness tracks the heartbeats of an executing instance
+type liveness struct {
+	beats     chan struct{}
+	electron  *Electron
+	conductor Conductor
+}
+
+// WithLeakWarning enables an event to be emitted each time an executing
+// atom exceeds the timeout without calling Heartbeat. Long running atoms
+// should call Heartbeat periodically to indicate they are still alive.
+func WithLeakWarning(timeout time.Duration) Option {
+	return func(a *atomizer) error {
+		if timeout <= 0 {
+			return simple("leak warning timeout must be positive", nil)
+		}
+
+		a.leakTimeout = timeout
+		return nil
+	}
+}
+
+// Heartbeat signals that the atom executing with the supplied context is
+// still alive. This resets the leak warning timer for the instance and
+// extends the deadline of the electron with the conductor if the conductor
+// implements DeadlineExtender.
+func Heartbeat(ctx context.Context) error {
+	if ctx == nil {
+		return simple("nil heartbeat context", nil)
+	}
+
+	l, ok := ctx.Value(livenessKey{}).(*liveness)
+	if !ok {
+		return simple("heartbeat outside of atom execution", nil)
+	}
+
+	select {
+	case l.beats <- struct{}{}:
+	default:
+	}
+
+	var ext DeadlineExtender
+	if conductorAs(l.conductor, &ext) {
+		return ext.ExtendDeadline(ctx, l.electron)
+	}
+
+	return nil
+}
+
+// alive adds the liveness tracking for the instance to the context
+func alive(ctx context.Context, inst *instance) (context.Context, *liveness) {
+	l := &liveness{
+		beats:     make(chan struct{}, 1),
+		electron:  inst.electron,
+		conductor: inst.conductor,
+	}
+
+	return context.WithValue(ctx, livenessKey{}, l), l
+}
+
+// watch emits a leak warning event each time the instance exceeds the
+// leak timeout without a heartbeat until the done channel is closed
+func (a *atomizer) watch(
+	done <-chan struct{},
+	atomID string,
+	l *liveness,
+) {
+	timer := time.NewTimer(a.leakTimeout)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-a.ctx.Done():
+			return
+		case <-done:
+			return
+		case <-l.beats:
+			if !timer.Stop() {
+				<-timer.C
+			}
+		case <-timer.C:
+			a.event(func() interface{} {
+				return &Event{
+					Message:     "possible leaked instance, no heartbeat",
+					ElectronID:  l.electron.ID,
+					AtomID:      atomID,
+					ConductorID: ID(l.conductor),
+				}
+			})
+		}
+
+		timer.Reset(a.leakTimeout)
+	}
+}
@@ -0,0 +1,151 @@
+package engine
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const (
+	leakTimeout = time.Millisecond * 50
+	longRunning = time.Millisecond * 200
+)
+
+type heartbeatatom struct{}
+
+func (*heartbeatatom) Process(
+	ctx context.Context,
+	conductor Conductor,
+	electron *Electron,
+) ([]byte, error) {
+	ticker := time.NewTicker(leakTimeout / 5)
+	defer ticker.Stop()
+
+	done := time.After(longRunning)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-done:
+			return nil, nil
+		case <-ticker.C:
+			if err := Heartbeat(ctx); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+type silentatom struct{}
+
+func (*silentatom) Process(
+	ctx context.Context,
+	conductor Conductor,
+	electron *Electron,
+) ([]byte, error) {
+	time.Sleep(longRunning)
+	return nil, nil
+}
+
+type extenderconductor struct {
+	passthrough
+	extensions int64
+}
+
+func (c *extenderconductor) ExtendDeadline(
+	ctx context.Context,
+	electron *Electron,
+) error {
+	atomic.AddInt64(&c.extensions, 1)
+	return nil
+}
+
+func leaked(events <-chan interface{}, electronID string) bool {
+	for {
+		select {
+		case e := <-events:
+			event, ok := e.(*Event)
+			if ok && event.ElectronID == electronID &&
+				strings.Contains(event.Message, "leaked") {
+				return true
+			}
+		default:
+			return false
+		}
+	}
+}
+
+func TestAtomizer_Heartbeat_LeakWarning(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	conductor, events, _, err := optHarness(
+		ctx,
+		1000,
+		[]Option{WithLeakWarning(leakTimeout)},
+		&heartbeatatom{},
+		&silentatom{},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := newElectron(ID(heartbeatatom{}), nil)
+	p := sendAndWait(ctx, t, conductor, e)
+	if p.Error != nil {
+		t.Fatal(p.Error)
+	}
+
+	if leaked(events, e.ID) {
+		t.Fatal("unexpected leak warning for heartbeating atom")
+	}
+
+	e = newElectron(ID(silentatom{}), nil)
+	p = sendAndWait(ctx, t, conductor, e)
+	if p.Error != nil {
+		t.Fatal(p.Error)
+	}
+
+	if !leaked(events, e.ID) {
+		t.Fatal("expected leak warning for silent atom")
+	}
+}
+
+func TestHeartbeat_ExtendDeadline(t *testing.T) {
+	c := &extenderconductor{}
+	ctx, _ := alive(context.Background(), &instance{
+		electron:  noopelectron,
+		conductor: c,
+	})
+
+	for i := 0; i < 3; i++ {
+		if err := Heartbeat(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if atomic.LoadInt64(&c.extensions) != 3 {
+		t.Fatalf("expected 3 extensions, got %v", c.extensions)
+	}
+}
+
+func TestHeartbeat_NoInstance(t *testing.T) {
+	if err := Heartbeat(context.Background()); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestWithLeakWarning_Invalid(t *testing.T) {
+	_, err := Atomize(context.Background(), WithLeakWarning(0))
+	if err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -23,7 +23,8 @@ func _ctx(c context.Context) (context.Context, context.CancelFunc) {
 	return context.WithCancel(c)
 }
 
-// _ctxT returns a context with a timeout that is passed in as a time.Duration
+// _ctxT returns a context with a timeout that is passed in as a time.Duration.
+// A nil, zero or negative duration returns a context without a timeout.
 func _ctxT(
 	c context.Context,
 	duration *time.Duration,
@@ -32,14 +33,25 @@ func _ctxT(
 		c = context.Background()
 	}
 
-	if duration == nil {
+	if duration == nil || *duration <= 0 {
 		return _ctx(c)
 	}
 
 	return context.WithTimeout(c, *duration)
 }
 
+// Identifier is implemented by types which supply their own registration
+// id rather than the id derived from their type, such as TracingConductor
+// which registers under the id of the conductor it wraps
+type Identifier interface {
+	ID() string
+}
+
 // ID returns the registration id for the passed in object type
 func ID(v interface{}) string {
+	if i, ok := v.(Identifier); ok {
+		return i.ID()
+	}
+
 	return strings.Trim(fmt.Sprintf("%T", v), "*")
 }
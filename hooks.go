@@ -0,0 +1,78 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+// execHooks are the callbacks invoked around the execution of atoms
+type execHooks struct {
+	start func(Electron)
+	end   func(Electron, Properties)
+}
+
+// WithExecHooks registers callbacks which are invoked synchronously
+// before and after each atom execution. The end hook receives the final
+// properties of the execution including any error. Panics in the hooks
+// are recovered and emitted as errors without affecting the execution.
+// Either hook can be nil.
+func WithExecHooks(
+	onStart func(Electron),
+	onEnd func(Electron, Properties),
+) Option {
+	return func(a *atomizer) error {
+		if onStart == nil && onEnd == nil {
+			return simple("nil exec hooks", nil)
+		}
+
+		a.hooks = &execHooks{onStart, onEnd}
+		return nil
+	}
+}
+
+// hookStart invokes the start hook for the instance
+func (a *atomizer) hookStart(inst *instance) {
+	if a.hooks == nil || a.hooks.start == nil {
+		return
+	}
+
+	defer a.hookRecover("start", inst)
+
+	a.hooks.start(*inst.electron)
+}
+
+// hookEnd invokes the end hook for the instance
+func (a *atomizer) hookEnd(inst *instance) {
+	if a.hooks == nil || a.hooks.end == nil {
+		return
+	}
+
+	defer a.hookRecover("end", inst)
+
+	var p Properties
+	if inst.properties != nil {
+		p = *inst.properties
+	}
+
+	a.hooks.end(*inst.electron, p)
+}
+
+// hookRecover recovers a panic in an exec hook and emits it as an error
+func (a *atomizer) hookRecover(hook string, inst *instance) {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	a.err(func() error {
+		return &Error{
+			Event: &Event{
+				Message:     "panic in " + hook + " exec hook",
+				ElectronID:  inst.electron.ID,
+				AtomID:      inst.electron.AtomID,
+				ConductorID: ID(inst.conductor),
+			},
+			Internal: ptoe(r),
+		}
+	})
+}
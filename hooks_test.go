@@ -0,0 +1,107 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type hookcall struct {
+	electron   Electron
+	properties Properties
+}
+
+func TestAtomizer_ExecHooks(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	starts := make(chan Electron, 10)
+	ends := make(chan hookcall, 10)
+
+	rec, a := recHarness(
+		ctx,
+		t,
+		WithExecHooks(
+			func(e Electron) {
+				starts <- e
+			},
+			func(e Electron, p Properties) {
+				ends <- hookcall{e, p}
+
+				// A panicking hook must not affect execution
+				panic("bad hook")
+			},
+		),
+		&noopatom{},
+		&failatom{},
+	)
+
+	errs := a.Errors(10)
+
+	tests := map[string]struct {
+		electron *Electron
+		err      bool
+	}{
+		"success": {newElectron(ID(noopatom{}), nil), false},
+		"error":   {newElectron(ID(failatom{}), nil), true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := rec.Send(ctx, test.electron); err != nil {
+				t.Fatal(err)
+			}
+
+			p := rec.next(ctx, t)
+			if (p.Error != nil) != test.err {
+				t.Fatalf("expected error %v, got %v", test.err, p.Error)
+			}
+
+			select {
+			case <-ctx.Done():
+				t.Fatal("start hook not called")
+			case e := <-starts:
+				if e.ID != test.electron.ID {
+					t.Fatalf("expected start %s, got %s", test.electron.ID, e.ID)
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				t.Fatal("end hook not called")
+			case call := <-ends:
+				if call.electron.ID != test.electron.ID ||
+					call.properties.ElectronID != test.electron.ID {
+					t.Fatalf("unexpected end hook call %+v", call)
+				}
+
+				if (call.properties.Error != nil) != test.err {
+					t.Fatalf(
+						"expected end error %v, got %v",
+						test.err,
+						call.properties.Error,
+					)
+				}
+			}
+
+			// The panic in the end hook is emitted as an error
+			for {
+				select {
+				case <-ctx.Done():
+					t.Fatal("hook panic not emitted")
+				case err := <-errs:
+					e, ok := err.(*Error)
+					if ok && e.Event.Message == "panic in end exec hook" {
+						return
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import "sort"
+
+// RegistrationKind is the kind of a registration with the atomizer
+type RegistrationKind string
+
+const (
+	// AtomRegistration is the kind of a registered atom
+	AtomRegistration RegistrationKind = "atom"
+
+	// ConductorRegistration is the kind of a registered conductor
+	ConductorRegistration RegistrationKind = "conductor"
+)
+
+// Registration is a snapshot of an atom or conductor registered with
+// the atomizer and its metadata
+type Registration struct {
+	// ID is the registration ID of the atom or conductor
+	ID string
+
+	// Kind indicates if the registration is an atom or conductor
+	Kind RegistrationKind
+
+	// Value is the registered Atom or Conductor
+	Value interface{}
+
+	// Replicas is the number of processing loops of an atom
+	Replicas int
+
+	// Disabled indicates the atom is disabled (see Command)
+	Disabled bool
+
+	// Quarantined indicates the atom exceeded its panic budget
+	Quarantined bool
+
+	// Stats are the execution statistics of an atom
+	Stats AtomStats
+
+	// ConductorStats are the receive statistics of a conductor
+	ConductorStats ConductorStats
+
+	// Health is the health of a conductor when health checks are
+	// enabled and the conductor implements Pinger
+	Health *ConductorHealth
+}
+
+// Inspect invokes the callback with a snapshot of each registered atom
+// followed by each registered conductor, ordered by ID, until the
+// callback returns false. The snapshot is taken before the callback is
+// invoked so the callback is free to call the atomizer.
+func (a *atomizer) Inspect(fn func(Registration) bool) {
+	if fn == nil {
+		return
+	}
+
+	for _, r := range a.snapshot() {
+		if !fn(r) {
+			return
+		}
+	}
+}
+
+// snapshot returns a snapshot of the registrations
+func (a *atomizer) snapshot() []Registration {
+	var regs []Registration
+
+	a.atomsMu.RLock()
+	for id, reps := range a.atoms {
+		regs = append(regs, Registration{
+			ID:       id,
+			Kind:     AtomRegistration,
+			Value:    reps.atom,
+			Replicas: reps.size(),
+		})
+	}
+
+	for id, reps := range a.disabled {
+		_, quarantined := a.quarantined[id]
+		regs = append(regs, Registration{
+			ID:          id,
+			Kind:        AtomRegistration,
+			Value:       reps.atom,
+			Replicas:    reps.size(),
+			Disabled:    true,
+			Quarantined: quarantined,
+		})
+	}
+	a.atomsMu.RUnlock()
+
+	a.conductorsMu.RLock()
+	for id, c := range a.conductors {
+		regs = append(regs, Registration{
+			ID:    id,
+			Kind:  ConductorRegistration,
+			Value: c,
+		})
+	}
+	a.conductorsMu.RUnlock()
+
+	stats := a.Stats()
+	cstats := a.ConductorStats()
+	health := a.Health()
+
+	for i := range regs {
+		if regs[i].Kind == AtomRegistration {
+			regs[i].Stats = stats[regs[i].ID]
+			continue
+		}
+
+		regs[i].ConductorStats = cstats[regs[i].ID]
+		if h, ok := health[regs[i].ID]; ok {
+			regs[i].Health = &h
+		}
+	}
+
+	sort.SliceStable(regs, func(i, j int) bool {
+		if regs[i].Kind != regs[j].Kind {
+			return regs[i].Kind == AtomRegistration
+		}
+
+		return regs[i].ID < regs[j].ID
+	})
+
+	return regs
+}
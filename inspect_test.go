@@ -0,0 +1,65 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestAtomizer_Inspect(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	alt := &altrecorder{newRecorder()}
+	rec, a := recHarness(ctx, t, alt, &noopatom{}, &returner{})
+
+	expected := map[string]RegistrationKind{
+		ID(noopatom{}): AtomRegistration,
+		ID(returner{}): AtomRegistration,
+		ID(rec):        ConductorRegistration,
+		ID(alt):        ConductorRegistration,
+	}
+
+	eventually(t, time.Second, func() bool {
+		visited := map[string]RegistrationKind{}
+		a.Inspect(func(r Registration) bool {
+			visited[r.ID] = r.Kind
+			return true
+		})
+
+		for id, kind := range expected {
+			if visited[id] != kind {
+				return false
+			}
+		}
+
+		return true
+	})
+
+	// The callback is free to call back into the atomizer
+	// since no locks are held while it is invoked
+	a.Inspect(func(r Registration) bool {
+		if r.Kind == AtomRegistration && r.Replicas != 1 {
+			t.Fatalf("expected 1 replica for %s, got %v", r.ID, r.Replicas)
+		}
+
+		_ = a.deregister(ID(returner{}))
+		return true
+	})
+
+	visits := 0
+	a.Inspect(func(r Registration) bool {
+		visits++
+		return false
+	})
+
+	if visits != 1 {
+		t.Fatalf("expected inspection to stop after 1 visit, got %v", visits)
+	}
+}
@@ -7,6 +7,7 @@ package engine
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"devnw.com/validator"
@@ -20,6 +21,46 @@ type instance struct {
 	ctx        context.Context
 	cancel     context.CancelFunc
 
+	// profileMem indicates that the allocations made during
+	// the execution of the atom should be sampled
+	profileMem bool
+
+	// completer overrides the completion of the instance
+	// with the conductor when set
+	completer func(ctx context.Context, p *Properties) error
+
+	// timing tracks the pipeline stages of the electron
+	timing *timing
+
+	// seq is the order the electron was received in
+	seq uint64
+
+	// received is the time the electron was received
+	// from the conductor
+	received time.Time
+
+	// deadline overrides the timeout of the electron when the
+	// timeout starts from submission (see WithTimeoutSemantics)
+	deadline time.Time
+
+	// partials collects the partial results
+	// emitted by the atom
+	partials *partials
+
+	// results streams or buffers the result
+	// written by the atom
+	results *resultWriter
+
+	// panicked indicates the atom panicked during execution
+	panicked bool
+
+	// retry re-runs the atom when processing fails, if set
+	retry *RetryPolicy
+
+	// barrier is closed by the receiver of the instance rather
+	// than the instance being distributed or processed
+	barrier chan struct{}
+
 	// TODO: add an actions channel here that the monitor can keep
 	// an eye on for this bonded electron/atom combo
 }
@@ -52,9 +93,14 @@ func (i *instance) bond(atom Atom) (err error) {
 
 // complete marks the completion of execution and pushes
 // the results to the conductor
-func (i *instance) complete() error {
+func (i *instance) complete(ctx context.Context) error {
 	// Set the end time and status in the properties
 	i.properties.End = time.Now()
+	i.results.finish(i.properties)
+	i.properties.Partials = i.partials.get()
+	i.properties.Status = i.status()
+	i.properties.Empty = i.properties.Status == StatusSucceeded &&
+		len(i.properties.Result) == 0 && !i.results.wrote()
 
 	if !validator.Valid(i.conductor) {
 		return &Error{
@@ -67,20 +113,32 @@ func (i *instance) complete() error {
 	}
 
 	// Push the completed instance properties to the conductor
-	return i.conductor.Complete(i.ctx, i.properties)
+	if i.completer != nil {
+		return i.completer(ctx, i.properties)
+	}
+
+	return i.conductor.Complete(ctx, i.properties)
 }
 
 // execute runs the process method on the bonded atom / electron pair
 func (i *instance) execute(ctx context.Context) (err error) {
 	defer func() {
 		if r := recover(); r != nil {
+			i.panicked = true
 			err = &Error{
 				Event: &Event{
 					Message:    "panic in atomizer",
 					AtomID:     ID(i.atom),
 					ElectronID: i.electron.ID,
 				},
-				Internal: ptoe(r),
+				Internal: ptope(r),
+			}
+
+			// The atomizer completes the conductor with the
+			// panic so the properties must reflect the failure
+			if i.properties != nil {
+				i.properties.End = time.Now()
+				i.properties.Status = StatusFailed
 			}
 
 			return
@@ -88,8 +146,10 @@ func (i *instance) execute(ctx context.Context) (err error) {
 
 		// ensure that when this method exits the completion
 		// of this instance takes place and is pushed to the
-		// conductor
-		err = i.complete()
+		// conductor. The parent context is used so that the
+		// result of an instance which exceeded its timeout is
+		// still delivered.
+		err = i.complete(ctx)
 	}()
 
 	// ensure the instance is valid before attempting
@@ -104,20 +164,56 @@ func (i *instance) execute(ctx context.Context) (err error) {
 	}
 
 	// Establish internal context
-	i.ctx, i.cancel = _ctxT(ctx, i.electron.Timeout)
+	if i.deadline.IsZero() {
+		i.ctx, i.cancel = _ctxT(ctx, i.electron.Timeout)
+	} else {
+		i.ctx, i.cancel = context.WithDeadline(ctx, i.deadline)
+	}
 
 	i.properties = &Properties{
-		ElectronID: i.electron.ID,
-		AtomID:     ID(i.atom),
-		Start:      time.Now(),
+		ElectronID:    i.electron.ID,
+		AtomID:        ID(i.atom),
+		CorrelationID: i.electron.CorrelationID,
+		Lineage:       lineage(i.electron),
+		Start:         time.Now(),
+		Timeout:       i.timeout(),
 	}
 
 	// TODO: Setup with a heartbeat for monitoring processing of the
 	// bonded atom stream in from the process method
 
-	// Execute the process method of the atom
-	i.properties.Result, i.properties.Error = i.atom.Process(
-		i.ctx, i.conductor, i.electron)
+	var before uint64
+	if i.profileMem {
+		before = allocated()
+	}
+
+	// Execute the process method of the atom, retrying
+	// failures according to the retry policy
+	for retry := true; retry; {
+		i.properties.Attempts++
+		i.properties.Result, i.properties.Error = i.atom.Process(
+			i.ctx, i.conductor, i.electron)
+
+		var wait time.Duration
+		wait, retry = i.retry.backoff(
+			i.ctx,
+			i.properties.Attempts,
+			i.properties.Error,
+		)
+		if !retry {
+			break
+		}
+
+		select {
+		case <-i.ctx.Done():
+			retry = false
+		case <-time.After(wait):
+		}
+	}
+
+	if i.profileMem {
+		i.properties.Allocated = allocated() - before
+	}
 
 	// TODO: The processing has finished for this bonded atom and the
 	// results need to be calculated and the properties sent back to the
@@ -141,6 +237,50 @@ func (i *instance) execute(ctx context.Context) (err error) {
 	return nil
 }
 
+// timeout returns the timeout the instance is processed with
+func (i *instance) timeout() time.Duration {
+	if !i.deadline.IsZero() {
+		return i.deadline.Sub(i.received)
+	}
+
+	timeout, _ := i.electron.timeout()
+	return timeout
+}
+
+// status determines the status of the executed instance, replacing the
+// error of a timed out instance with the details of the timeout
+func (i *instance) status() Status {
+	if i.ctx != nil && i.ctx.Err() == context.DeadlineExceeded {
+		internal := i.properties.Error
+		if internal == nil {
+			internal = context.DeadlineExceeded
+		}
+
+		i.properties.Error = &Error{
+			Event: &Event{
+				Message: fmt.Sprintf(
+					"electron timed out after %s with a timeout of %s"+
+						" and %v partial results",
+					i.properties.Elapsed(),
+					i.properties.Timeout,
+					len(i.properties.Partials),
+				),
+				AtomID:     ID(i.atom),
+				ElectronID: i.electron.ID,
+			},
+			Internal: internal,
+		}
+
+		return StatusTimedOut
+	}
+
+	if i.properties.Error != nil {
+		return StatusFailed
+	}
+
+	return StatusSucceeded
+}
+
 // Validate ensures that the instance has the correct
 // non-nil values internally so that it functions properly
 func (i *instance) Validate() (valid bool) {
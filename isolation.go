@@ -0,0 +1,234 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import "time"
+
+// DefaultTenantIdleTimeout is the time a tenant processing loop is kept
+// without electrons for the tenant before it is stopped
+const DefaultTenantIdleTimeout = time.Minute * 5
+
+// WithTenantIsolation processes the electrons of each tenant on processing
+// loops dedicated to the tenant, started the first time the tenant sends an
+// electron for an atom, so that a tenant whose electrons are slow, stuck or
+// crashing cannot exhaust the processing loops of the other tenants. The
+// electrons waiting for a tenant loop are queued without bound rather than
+// holding up the distribution of the other tenants' electrons. Each tenant
+// loop executes up to the max concurrency of the atom (see
+// WithMaxConcurrency) and is stopped once the tenant has sent no electrons
+// for the atom within the idle timeout (see WithTenantIdleTimeout).
+// Electrons without a TenantID share the replicas of the atom. The events
+// of an electron include its TenantID.
+func WithTenantIsolation() Option {
+	return func(a *atomizer) error {
+		a.isolateTenants = true
+		return nil
+	}
+}
+
+// WithTenantIdleTimeout sets the time a tenant processing loop is kept
+// without electrons for the tenant before it is stopped, defaulting to
+// DefaultTenantIdleTimeout. The loop is started again with the next
+// electron of the tenant.
+func WithTenantIdleTimeout(timeout time.Duration) Option {
+	return func(a *atomizer) error {
+		if timeout <= 0 {
+			return simple("invalid tenant idle timeout "+timeout.String(), nil)
+		}
+
+		a.tenantIdle = timeout
+		return nil
+	}
+}
+
+// replica returns the channel of the processing loop of the replicas
+// which processes the electron. The atoms lock MUST NOT be held since
+// tenant processing loops are started on demand.
+func (a *atomizer) replica(reps *replicas, e *Electron) chan<- instance {
+	if !a.isolateTenants || e.TenantID == "" {
+		return reps.route(e)
+	}
+
+	tenant := e.TenantID
+
+	reps.tenantsMu.Lock()
+	electrons, ok := reps.tenants[tenant]
+	reps.tenantsMu.Unlock()
+
+	if ok {
+		return electrons
+	}
+
+	idle := a.tenantIdle
+	if idle <= 0 {
+		idle = DefaultTenantIdleTimeout
+	}
+
+	started := a.unbounded(
+		a.split(reps.atom),
+		idle,
+		func(in chan<- instance) {
+			a.reap(reps, tenant, in)
+		},
+	)
+
+	reps.tenantsMu.Lock()
+	electrons, ok = reps.tenants[tenant]
+	stopping := reps.stopping
+	if !ok && !stopping {
+		if reps.tenants == nil {
+			reps.tenants = make(map[string]chan<- instance)
+		}
+
+		electrons = started
+		reps.tenants[tenant] = electrons
+	}
+	reps.tenantsMu.Unlock()
+
+	if ok || stopping {
+		// Another loop was started for the tenant
+		// or the atom is no longer routable
+		a.retire(started)
+		return electrons
+	}
+
+	a.event(func() interface{} {
+		return &Event{
+			Message:  "started tenant processing loop",
+			AtomID:   reps.id,
+			TenantID: tenant,
+		}
+	})
+
+	return electrons
+}
+
+// reap stops the idle processing loop of the tenant unless the replicas
+// are stopping, in which case the loop is stopped with the replicas
+func (a *atomizer) reap(reps *replicas, tenant string, in chan<- instance) {
+	reps.tenantsMu.Lock()
+	electrons, ok := reps.tenants[tenant]
+	reaped := ok && electrons == in && !reps.stopping
+	if reaped {
+		delete(reps.tenants, tenant)
+	}
+	reps.tenantsMu.Unlock()
+
+	if !reaped {
+		return
+	}
+
+	a.event(func() interface{} {
+		return &Event{
+			Message:  "stopped idle tenant processing loop",
+			AtomID:   reps.id,
+			TenantID: tenant,
+		}
+	})
+
+	a.routine(func() {
+		// Ensure distribute is not pushing to
+		// the loop before it is stopped
+		if a.barrier(a.ctx) != nil {
+			return
+		}
+
+		a.retire(in)
+	})
+}
+
+// retire stops the processing loop of an unbounded queue once the
+// instances queued before it are processed. Nothing may push to the
+// queue once it is retired.
+func (a *atomizer) retire(in chan<- instance) {
+	select {
+	case <-a.ctx.Done():
+		return
+	case in <- instance{barrier: make(chan struct{})}:
+	}
+
+	close(in)
+}
+
+// unbounded queues the instances pushed to the returned channel in order
+// without bound while they wait to be pushed to the processing loop.
+// Closing the returned channel closes the loop once the queued instances
+// are pushed to it. The expire function is called with the returned
+// channel each time the queue has been empty for the idle duration.
+func (a *atomizer) unbounded(
+	loop chan<- instance,
+	idle time.Duration,
+	expire func(in chan<- instance),
+) chan<- instance {
+	in := make(chan instance)
+
+	a.routine(func() {
+		timer := time.NewTimer(idle)
+		defer timer.Stop()
+
+		var pending []instance
+		for {
+			var next instance
+			var out chan<- instance
+			var expired <-chan time.Time
+			if len(pending) > 0 {
+				next, out = pending[0], loop
+			} else {
+				expired = timer.C
+			}
+
+			select {
+			case <-a.ctx.Done():
+				return
+			case inst, ok := <-in:
+				if !ok {
+					a.flush(pending, loop)
+					return
+				}
+
+				pending = append(pending, inst)
+			case out <- next:
+				pending[0] = instance{}
+				pending = pending[1:]
+
+				if len(pending) == 0 {
+					restart(timer, idle)
+				}
+			case <-expired:
+				expire(in)
+				timer.Reset(idle)
+			}
+		}
+	})
+
+	return in
+}
+
+// restart restarts the timer with the duration, draining the
+// channel of the timer when it already fired
+func restart(timer *time.Timer, d time.Duration) {
+	if !timer.Stop() {
+		select {
+		case <-timer.C:
+		default:
+		}
+	}
+
+	timer.Reset(d)
+}
+
+// flush pushes the pending instances to the loop and closes the loop
+func (a *atomizer) flush(pending []instance, loop chan<- instance) {
+	for _, inst := range pending {
+		select {
+		case <-a.ctx.Done():
+			return
+		case loop <- inst:
+		}
+	}
+
+	close(loop)
+}
@@ -0,0 +1,157 @@
+package engine
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// noisyrelease blocks the electrons of the noisy tenant in tenantatom
+// until it is closed
+var noisyrelease chan struct{}
+
+// tenantatom panics on the electrons of the noisy tenant once they
+// are released and succeeds for every other tenant
+type tenantatom struct{}
+
+func (*tenantatom) Process(
+	ctx context.Context,
+	conductor Conductor,
+	electron *Electron,
+) ([]byte, error) {
+	if electron.TenantID == "noisy" {
+		<-noisyrelease
+		panic("noisy tenant")
+	}
+
+	return nil, nil
+}
+
+func TestAtomizer_TenantIsolation(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	noisyrelease = make(chan struct{})
+	release := noisyrelease
+
+	var once sync.Once
+	open := func() {
+		once.Do(func() {
+			close(release)
+		})
+	}
+	t.Cleanup(open)
+
+	rec, a := recHarness(ctx, t, WithTenantIsolation(), &tenantatom{})
+
+	eventually(t, time.Second, func() bool {
+		return a.route(newElectron(ID(tenantatom{}), nil)) != nil
+	})
+
+	send := func(tenant string) *Electron {
+		e := newElectron(ID(tenantatom{}), nil)
+		e.TenantID = tenant
+
+		if _, err := rec.Send(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+
+		return e
+	}
+
+	// Exhaust the processing loop of the noisy tenant
+	for i := 0; i < 3; i++ {
+		send("noisy")
+	}
+
+	for i := 0; i < 3; i++ {
+		e := send("quiet")
+
+		select {
+		case <-time.After(time.Second):
+			t.Fatal("expected quiet tenant to be processed")
+		case p := <-rec.completions:
+			if p.ElectronID != e.ID || p.Error != nil {
+				t.Fatalf("expected %s to succeed, got %+v", e.ID, p)
+			}
+		}
+	}
+
+	open()
+	for i := 0; i < 3; i++ {
+		if p := rec.next(ctx, t); p.Error == nil {
+			t.Fatal("expected noisy tenant to panic")
+		}
+	}
+}
+
+func TestAtomizer_TenantIsolation_Idle(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	rec, a := recHarness(
+		ctx,
+		t,
+		WithTenantIsolation(),
+		WithTenantIdleTimeout(time.Millisecond*50),
+		&noopatom{},
+	)
+
+	atomID := ID(noopatom{})
+	eventually(t, time.Second, func() bool {
+		return a.route(newElectron(atomID, nil)) != nil
+	})
+
+	splits := atomic.LoadInt64(&a.routines.splits)
+
+	tenants := func() int {
+		a.atomsMu.RLock()
+		reps := a.atoms[atomID]
+		a.atomsMu.RUnlock()
+
+		reps.tenantsMu.Lock()
+		defer reps.tenantsMu.Unlock()
+
+		return len(reps.tenants)
+	}
+
+	for i := 0; i < 2; i++ {
+		e := newElectron(atomID, nil)
+		e.TenantID = "tenant"
+
+		if _, err := rec.Send(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+
+		if p := rec.next(ctx, t); p.ElectronID != e.ID || p.Error != nil {
+			t.Fatalf("expected %s to succeed, got %+v", e.ID, p)
+		}
+
+		// The idle tenant loop is stopped and started
+		// again by the next electron of the tenant
+		eventually(t, time.Second*5, func() bool {
+			return tenants() == 0 &&
+				atomic.LoadInt64(&a.routines.splits) == splits
+		})
+	}
+}
+
+func TestWithTenantIdleTimeout_Invalid(t *testing.T) {
+	if err := WithTenantIdleTimeout(0)(&atomizer{}); err == nil {
+		t.Fatal("expected error")
+	}
+}
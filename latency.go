@@ -0,0 +1,113 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import (
+	"context"
+	"time"
+)
+
+// ConductorStats contains the receive statistics gathered by the
+// atomizer for a registered conductor
+type ConductorStats struct {
+	// Received is the number of electrons received from the conductor
+	Received uint64 `json:"received"`
+
+	// ReceiveLatency is the total time electrons from the conductor
+	// were available before they were picked up by the atomizer. This
+	// is only populated when enabled using WithReceiveLatency
+	ReceiveLatency time.Duration `json:"receiveLatency"`
+
+	// MaxReceiveLatency is the longest time an electron from the
+	// conductor was available before it was picked up
+	MaxReceiveLatency time.Duration `json:"maxReceiveLatency"`
+}
+
+// WithReceiveLatency enables measuring the time between an electron being
+// available from a conductor and the atomizer picking it up for processing.
+// Consistently high receive latency indicates the atomizer rather than the
+// conductor is the bottleneck. Measuring latency reads one electron ahead
+// from each conductor.
+func WithReceiveLatency() Option {
+	return func(a *atomizer) error {
+		a.receiveLatency = true
+		return nil
+	}
+}
+
+// stamp reads the electrons from the receiver as soon as they are
+// available and records the receive latency once each electron is
+// picked up from the returned channel
+func (a *atomizer) stamp(
+	ctx context.Context,
+	conductorID string,
+	receiver <-chan *Electron,
+) <-chan *Electron {
+	out := make(chan *Electron)
+
+	a.routine(func() {
+		defer close(out)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case e, ok := <-receiver:
+				if !ok {
+					return
+				}
+
+				available := time.Now()
+
+				select {
+				case <-ctx.Done():
+					return
+				case out <- e:
+					a.received(conductorID, time.Since(available))
+				}
+			}
+		}
+	})
+
+	return out
+}
+
+// received updates the statistics of the conductor
+func (a *atomizer) received(conductorID string, latency time.Duration) {
+	a.statsMu.Lock()
+	defer a.statsMu.Unlock()
+
+	if a.cstats == nil {
+		a.cstats = make(map[string]*ConductorStats)
+	}
+
+	stats, ok := a.cstats[conductorID]
+	if !ok {
+		stats = &ConductorStats{}
+		a.cstats[conductorID] = stats
+	}
+
+	stats.Received++
+	stats.ReceiveLatency += latency
+
+	if latency > stats.MaxReceiveLatency {
+		stats.MaxReceiveLatency = latency
+	}
+}
+
+// ConductorStats returns a snapshot of the receive statistics for each
+// conductor which has delivered electrons, keyed by conductor ID
+func (a *atomizer) ConductorStats() map[string]ConductorStats {
+	a.statsMu.RLock()
+	defer a.statsMu.RUnlock()
+
+	stats := make(map[string]ConductorStats, len(a.cstats))
+	for id, s := range a.cstats {
+		stats[id] = *s
+	}
+
+	return stats
+}
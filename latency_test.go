@@ -0,0 +1,77 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestAtomizer_ReceiveLatency(t *testing.T) {
+	delay := time.Millisecond * 100
+
+	tests := map[string]struct {
+		delayed bool
+	}{
+		"immediate pickup": {false},
+		"delayed pickup":   {true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			d := time.Second * 30
+			ctx, cancel := _ctxT(context.TODO(), &d)
+			defer cancel()
+
+			reset(ctx, t)
+			t.Cleanup(func() {
+				reset(context.TODO(), t)
+			})
+
+			elector := &MemoryElector{}
+			if !test.delayed {
+				elector.Acquire()
+			}
+
+			rec, a := recHarness(
+				ctx,
+				t,
+				WithReceiveLatency(),
+				WithLeaderElection(elector),
+				&noopatom{},
+			)
+
+			eventually(t, time.Second, func() bool {
+				return a.route(newElectron(ID(noopatom{}), nil)) != nil
+			})
+
+			_, err := rec.Send(ctx, newElectron(ID(noopatom{}), nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if test.delayed {
+				// Delay the pickup of the available electron
+				// by withholding leadership from the conduct loop
+				time.Sleep(delay)
+				elector.Acquire()
+			}
+
+			if p := rec.next(ctx, t); p.Error != nil {
+				t.Fatal(p.Error)
+			}
+
+			eventually(t, time.Second, func() bool {
+				return a.ConductorStats()[ID(rec)].Received == 1
+			})
+
+			latency := a.ConductorStats()[ID(rec)].MaxReceiveLatency
+			if test.delayed && latency < delay {
+				t.Fatalf("expected latency >= %v, got %v", delay, latency)
+			}
+
+			if !test.delayed && latency >= delay {
+				t.Fatalf("expected latency < %v, got %v", delay, latency)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import (
+	"context"
+	"sync"
+)
+
+// Elector provides leadership election between atomizer instances (i.e.
+// an active and a warm standby) backed by a distributed lock
+type Elector interface {
+	// Leadership returns a channel which receives the leadership state
+	// of this instance initially and each time it changes. The channel
+	// is closed when the context is canceled.
+	Leadership(ctx context.Context) <-chan bool
+}
+
+// WithLeaderElection configures the atomizer to only consume electrons
+// from its conductors while this instance holds leadership. Consumption
+// pauses when leadership is lost and resumes when it is acquired again.
+func WithLeaderElection(elector Elector) Option {
+	return func(a *atomizer) error {
+		if elector == nil {
+			return simple("nil elector", nil)
+		}
+
+		a.elector = elector
+		a.leader = newGate(false)
+		return nil
+	}
+}
+
+// lead follows the leadership state of the elector
+func (a *atomizer) lead() {
+	leadership := a.elector.Leadership(a.ctx)
+
+	for {
+		select {
+		case <-a.ctx.Done():
+			return
+		case leader, ok := <-leadership:
+			if !ok {
+				// Stop consuming since leadership
+				// can no longer be verified
+				a.leader.set(false)
+				return
+			}
+
+			if !a.leader.set(leader) {
+				continue
+			}
+
+			msg := "leadership lost, pausing consumption"
+			if leader {
+				msg = "leadership acquired, consuming electrons"
+			}
+
+			a.event(func() interface{} {
+				return makeEvent(msg)
+			})
+		}
+	}
+}
+
+// MemoryElector is an in-memory Elector where leadership is
+// explicitly acquired and resigned
+type MemoryElector struct {
+	mu          sync.Mutex
+	leader      bool
+	subscribers []chan bool
+}
+
+// Leadership returns a channel which receives the leadership state
+func (e *MemoryElector) Leadership(ctx context.Context) <-chan bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	sub := make(chan bool, 1)
+	sub <- e.leader
+	e.subscribers = append(e.subscribers, sub)
+
+	go func() {
+		<-ctx.Done()
+
+		e.mu.Lock()
+		defer e.mu.Unlock()
+
+		for i, s := range e.subscribers {
+			if s == sub {
+				e.subscribers = append(
+					e.subscribers[:i],
+					e.subscribers[i+1:]...,
+				)
+				break
+			}
+		}
+
+		close(sub)
+	}()
+
+	return sub
+}
+
+// Acquire gives leadership to the subscribers of this elector
+func (e *MemoryElector) Acquire() {
+	e.set(true)
+}
+
+// Resign removes leadership from the subscribers of this elector
+func (e *MemoryElector) Resign() {
+	e.set(false)
+}
+
+func (e *MemoryElector) set(leader bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.leader = leader
+	for _, sub := range e.subscribers {
+		// Replace any unread state with the latest state
+		select {
+		case <-sub:
+		default:
+		}
+
+		sub <- leader
+	}
+}
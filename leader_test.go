@@ -0,0 +1,82 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestAtomizer_LeaderElection(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	elector := &MemoryElector{}
+	rec, _ := recHarness(
+		ctx,
+		t,
+		WithLeaderElection(elector),
+		&noopatom{},
+	)
+
+	send := func() {
+		go func() {
+			_, _ = rec.Send(ctx, newElectron(ID(noopatom{}), nil))
+		}()
+	}
+
+	send()
+
+	select {
+	case p := <-rec.completions:
+		t.Fatalf("follower consumed electron %s", p.ElectronID)
+	case <-time.After(time.Millisecond * 100):
+	}
+
+	elector.Acquire()
+	rec.next(ctx, t)
+
+	elector.Resign()
+
+	// Allow the resignation to propagate to the atomizer
+	time.Sleep(time.Millisecond * 50)
+	send()
+
+	select {
+	case p := <-rec.completions:
+		t.Fatalf("resigned instance consumed electron %s", p.ElectronID)
+	case <-time.After(time.Millisecond * 100):
+	}
+
+	elector.Acquire()
+	rec.next(ctx, t)
+}
+
+func TestGate(t *testing.T) {
+	g := newGate(false)
+
+	select {
+	case <-g.open():
+		t.Fatal("expected closed gate")
+	case <-g.closed():
+	}
+
+	if !g.set(true) {
+		t.Fatal("expected state change")
+	}
+
+	if g.set(true) {
+		t.Fatal("expected no state change")
+	}
+
+	select {
+	case <-g.closed():
+		t.Fatal("expected open gate")
+	case <-g.open():
+	}
+}
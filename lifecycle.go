@@ -0,0 +1,94 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import (
+	"fmt"
+	"time"
+)
+
+// LifecycleState is a state transition of an electron instance
+type LifecycleState string
+
+const (
+	// Received indicates the electron was received from the conductor
+	Received LifecycleState = "received"
+
+	// Queued indicates the electron was queued for distribution
+	Queued LifecycleState = "queued"
+
+	// Bonded indicates the electron was bonded to an atom instance
+	Bonded LifecycleState = "bonded"
+
+	// ProcessingStart indicates the atom started processing
+	ProcessingStart LifecycleState = "processing-start"
+
+	// ProcessingEnd indicates the atom finished processing
+	ProcessingEnd LifecycleState = "processing-end"
+
+	// Completed indicates the properties of the processed electron
+	// were completed
+	Completed LifecycleState = "completed"
+)
+
+// Lifecycle is the record of a state transition of an electron instance
+// emitted through the events of the atomizer when the lifecycle trace is
+// enabled (see WithLifecycleTrace)
+type Lifecycle struct {
+	State       LifecycleState `json:"state"`
+	ElectronID  string         `json:"electronID"`
+	AtomID      string         `json:"atomID"`
+	ConductorID string         `json:"conductorID"`
+	Time        time.Time      `json:"time"`
+}
+
+func (l *Lifecycle) String() string {
+	if l == nil {
+		return ""
+	}
+
+	return fmt.Sprintf(
+		"[%s|%s|%s] %s %s",
+		l.ConductorID,
+		l.AtomID,
+		l.ElectronID,
+		l.State,
+		l.Time.Format(time.RFC3339Nano),
+	)
+}
+
+// WithLifecycleTrace enables a verbose audit trail of every electron
+// where a Lifecycle record is emitted as an event for each state
+// transition of the electron
+func WithLifecycleTrace() Option {
+	return func(a *atomizer) error {
+		a.lifecycleTrace = true
+		return nil
+	}
+}
+
+// lifecycle emits the state transition of the instance
+func (a *atomizer) lifecycle(state LifecycleState, inst *instance) {
+	if !a.lifecycleTrace || inst.electron == nil {
+		return
+	}
+
+	now := time.Now()
+	a.event(func() interface{} {
+		atomID := inst.electron.AtomID
+		if inst.atom != nil {
+			atomID = ID(inst.atom)
+		}
+
+		return &Lifecycle{
+			State:       state,
+			ElectronID:  inst.electron.ID,
+			AtomID:      atomID,
+			ConductorID: ID(inst.conductor),
+			Time:        now,
+		}
+	})
+}
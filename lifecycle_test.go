@@ -0,0 +1,70 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestAtomizer_LifecycleTrace(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	rec, a := recHarness(ctx, t, WithLifecycleTrace(), &noopatom{})
+	events := a.Events(1000)
+
+	eventually(t, time.Second, func() bool {
+		return a.route(newElectron(ID(noopatom{}), nil)) != nil
+	})
+
+	e := newElectron(ID(noopatom{}), nil)
+	if _, err := rec.Send(ctx, e); err != nil {
+		t.Fatal(err)
+	}
+
+	if p := rec.next(ctx, t); p.ElectronID != e.ID {
+		t.Fatalf("expected %s, got %s", e.ID, p.ElectronID)
+	}
+
+	expected := []LifecycleState{
+		Received,
+		Queued,
+		Bonded,
+		ProcessingStart,
+		ProcessingEnd,
+		Completed,
+	}
+
+	var records []*Lifecycle
+	for len(records) < len(expected) {
+		select {
+		case <-ctx.Done():
+			t.Fatalf("expected %v lifecycle records, got %v", len(expected), records)
+		case event := <-events:
+			l, ok := event.(*Lifecycle)
+			if ok && l.ElectronID == e.ID {
+				records = append(records, l)
+			}
+		}
+	}
+
+	for i, state := range expected {
+		if records[i].State != state {
+			t.Fatalf("expected %s at %v, got %s", state, i, records[i].State)
+		}
+
+		if records[i].AtomID != ID(noopatom{}) {
+			t.Fatalf("expected atom %s, got %s", ID(noopatom{}), records[i].AtomID)
+		}
+
+		if i > 0 && records[i].Time.Before(records[i-1].Time) {
+			t.Fatalf("expected ordered timestamps, got %v", records)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+// MaxLineage is the maximum number of entries in the lineage of an
+// electron. The oldest entries are dropped once the lineage is full.
+const MaxLineage = 64
+
+// LineageEntry is an electron in the lineage of a child electron
+// along with the atom which processed it
+type LineageEntry struct {
+	ElectronID string `json:"electronId"`
+	AtomID     string `json:"atomId"`
+}
+
+// descend returns the lineage of a child of the electron
+func descend(parent *Electron) []LineageEntry {
+	return bound(append(
+		append([]LineageEntry(nil), parent.Lineage...),
+		LineageEntry{parent.ID, parent.AtomID},
+	))
+}
+
+// lineage returns the lineage of the electron including the electron
+// itself or nil when the electron has no ancestors
+func lineage(e *Electron) []LineageEntry {
+	if len(e.Lineage) == 0 {
+		return nil
+	}
+
+	return bound(append(
+		append([]LineageEntry(nil), e.Lineage...),
+		LineageEntry{e.ID, e.AtomID},
+	))
+}
+
+// bound drops the oldest entries of the lineage beyond MaxLineage
+func bound(l []LineageEntry) []LineageEntry {
+	if len(l) <= MaxLineage {
+		return l
+	}
+
+	return l[len(l)-MaxLineage:]
+}
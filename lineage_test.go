@@ -0,0 +1,103 @@
+package engine
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// chained receives the properties of the last electron in the chain
+var chained chan Properties
+
+// chainatom submits a child electron until the count in the
+// payload reaches zero
+type chainatom struct{}
+
+func (*chainatom) Process(
+	ctx context.Context,
+	conductor Conductor,
+	electron *Electron,
+) ([]byte, error) {
+	n, err := strconv.Atoi(string(electron.Payload))
+	if err != nil || n == 0 {
+		return nil, err
+	}
+
+	child := &Electron{
+		SenderID: "chainatom",
+		ID:       uuid.New().String(),
+		AtomID:   ID(chainatom{}),
+		Payload:  []byte(strconv.Itoa(n - 1)),
+	}
+
+	return nil, Submit(ctx, child, func(p Properties, err error) {
+		if n == 1 {
+			chained <- p
+		}
+	})
+}
+
+func TestAtomizer_Lineage(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	chained = make(chan Properties, 1)
+
+	rec, a := recHarness(ctx, t, &chainatom{})
+
+	atomID := ID(chainatom{})
+	eventually(t, time.Second, func() bool {
+		return a.route(newElectron(atomID, nil)) != nil
+	})
+
+	root := newElectron(atomID, []byte("2"))
+	if _, err := rec.Send(ctx, root); err != nil {
+		t.Fatal(err)
+	}
+
+	if p := rec.next(ctx, t); len(p.Lineage) != 0 {
+		t.Fatalf("expected no lineage for the root, got %v", p.Lineage)
+	}
+
+	var p Properties
+	select {
+	case <-ctx.Done():
+		t.Fatal("expected chain to complete")
+	case p = <-chained:
+	}
+
+	if len(p.Lineage) != 3 {
+		t.Fatalf("expected 3 lineage entries, got %v", p.Lineage)
+	}
+
+	if p.Lineage[0].ElectronID != root.ID ||
+		p.Lineage[2].ElectronID != p.ElectronID {
+		t.Fatalf("unexpected lineage order %v", p.Lineage)
+	}
+
+	for _, entry := range p.Lineage {
+		if entry.AtomID != atomID {
+			t.Fatalf("unexpected atom %s", entry.AtomID)
+		}
+	}
+}
+
+func TestDescend_Bounded(t *testing.T) {
+	parent := &Electron{ID: "parent", AtomID: "atom"}
+	for i := 0; i < MaxLineage+10; i++ {
+		parent.Lineage = descend(parent)
+	}
+
+	if len(parent.Lineage) != MaxLineage {
+		t.Fatalf("expected %v entries, got %v", MaxLineage, len(parent.Lineage))
+	}
+}
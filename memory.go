@@ -0,0 +1,33 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import "runtime"
+
+// WithMemoryProfiling enables best-effort attribution of memory allocations
+// to atoms. The runtime memory statistics are sampled before and after each
+// atom execution and the difference is reported in the Allocated field of
+// the Properties as well as the atom statistics.
+//
+// NOTE: The runtime statistics are process wide so concurrently executing
+// atoms will be attributed each other's allocations. The values should be
+// treated as directional rather than exact. Sampling the runtime statistics
+// stops the world so this is disabled by default.
+func WithMemoryProfiling() Option {
+	return func(a *atomizer) error {
+		a.memProfiling = true
+		return nil
+	}
+}
+
+// allocated returns the total number of bytes allocated by the
+// process since it started
+func allocated() uint64 {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+
+	return m.TotalAlloc
+}
@@ -0,0 +1,109 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+var heavysink [][]byte
+
+type heavyatom struct{}
+
+func (*heavyatom) Process(
+	ctx context.Context,
+	conductor Conductor,
+	electron *Electron,
+) ([]byte, error) {
+	heavysink = make([][]byte, 0, 32)
+	for i := 0; i < 32; i++ {
+		heavysink = append(heavysink, make([]byte, 1<<20))
+	}
+
+	return nil, nil
+}
+
+func TestAtomizer_MemoryProfiling(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	conductor, _, a, err := optHarness(
+		ctx,
+		-1,
+		[]Option{WithMemoryProfiling()},
+		&heavyatom{},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	heavy := sendAndWait(
+		ctx,
+		t,
+		conductor,
+		newElectron(ID(heavyatom{}), nil),
+	)
+
+	light := sendAndWait(
+		ctx,
+		t,
+		conductor,
+		newElectron(ID(noopatom{}), nil),
+	)
+
+	if heavy.Allocated <= light.Allocated {
+		t.Fatalf(
+			"expected heavy [%v] > light [%v]",
+			heavy.Allocated,
+			light.Allocated,
+		)
+	}
+
+	if heavy.Allocated < 32<<20 {
+		t.Fatalf("expected at least 32MiB, got %v", heavy.Allocated)
+	}
+
+	eventually(t, time.Second, func() bool {
+		stats := a.Stats()
+		return stats[ID(heavyatom{})].Executions == 1 &&
+			stats[ID(noopatom{})].Executions == 1
+	})
+
+	stats := a.Stats()
+	if stats[ID(heavyatom{})].Allocated <= stats[ID(noopatom{})].Allocated {
+		t.Fatalf("expected heavy stats > light stats | %v", stats)
+	}
+}
+
+func TestAtomizer_MemoryProfiling_Disabled(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	conductor, _, err := harness(ctx, -1, &heavyatom{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := sendAndWait(
+		ctx,
+		t,
+		conductor,
+		newElectron(ID(heavyatom{}), nil),
+	)
+
+	if p.Allocated != 0 {
+		t.Fatalf("expected no allocation sampling, got %v", p.Allocated)
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import "time"
+
+// Monitor receives typed metrics from the atomizer so they can be bridged
+// to a metrics system without parsing the event stream. The callbacks are
+// called synchronously from the processing pipeline so they should return
+// quickly.
+type Monitor interface {
+	// ElectronReceived is called when an electron for the atom
+	// is admitted from a conductor
+	ElectronReceived(atomID string)
+
+	// ElectronCompleted is called when the atom finishes processing an
+	// electron with the processing duration and error, if any
+	ElectronCompleted(atomID string, d time.Duration, err error)
+
+	// QueueDepth is called when an electron is pushed to the atom with
+	// the number of electrons for the atom in flight
+	QueueDepth(atomID string, depth int)
+}
+
+// WithMonitor registers the monitor for the metrics of the atomizer.
+// By default no metrics are reported.
+func WithMonitor(monitor Monitor) Option {
+	return func(a *atomizer) error {
+		if monitor == nil {
+			return simple("invalid nil monitor", nil)
+		}
+
+		a.metrics = monitor
+		return nil
+	}
+}
+
+// monitorReceived reports the received electron to the monitor
+func (a *atomizer) monitorReceived(e *Electron) {
+	if a.metrics == nil {
+		return
+	}
+
+	a.metrics.ElectronReceived(e.AtomID)
+}
+
+// monitorCompleted reports the processed electron to the monitor
+func (a *atomizer) monitorCompleted(atomID string, p *Properties, err error) {
+	if a.metrics == nil {
+		return
+	}
+
+	var d time.Duration
+	if p != nil {
+		if p.End.After(p.Start) {
+			d = p.End.Sub(p.Start)
+		}
+
+		if err == nil {
+			err = p.Error
+		}
+	}
+
+	a.metrics.ElectronCompleted(atomID, d, err)
+}
+
+// monitorDepth reports the electrons in flight for the atom to the monitor
+func (a *atomizer) monitorDepth(atomID string) {
+	if a.metrics == nil {
+		return
+	}
+
+	a.metrics.QueueDepth(atomID, a.inflight.queued(atomID))
+}
@@ -0,0 +1,128 @@
+package engine
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recmonitor is a monitor which records the calls it receives
+type recmonitor struct {
+	mu        sync.Mutex
+	received  map[string]int
+	completed map[string]int
+	errors    map[string]int
+	depths    map[string]int
+}
+
+func newRecMonitor() *recmonitor {
+	return &recmonitor{
+		received:  make(map[string]int),
+		completed: make(map[string]int),
+		errors:    make(map[string]int),
+		depths:    make(map[string]int),
+	}
+}
+
+func (m *recmonitor) ElectronReceived(atomID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.received[atomID]++
+}
+
+func (m *recmonitor) ElectronCompleted(
+	atomID string,
+	d time.Duration,
+	err error,
+) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.completed[atomID]++
+	if err != nil {
+		m.errors[atomID]++
+	}
+}
+
+func (m *recmonitor) QueueDepth(atomID string, depth int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.depths[atomID]++
+}
+
+func (m *recmonitor) counts(atomID string) (int, int, int, int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.received[atomID], m.completed[atomID],
+		m.errors[atomID], m.depths[atomID]
+}
+
+func TestAtomizer_Monitor(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	monitor := newRecMonitor()
+	rec, a := recHarness(
+		ctx,
+		t,
+		WithMonitor(monitor),
+		&noopatom{},
+		&failatom{},
+	)
+
+	eventually(t, time.Second, func() bool {
+		return a.route(newElectron(ID(noopatom{}), nil)) != nil &&
+			a.route(newElectron(ID(failatom{}), nil)) != nil
+	})
+
+	electrons := []*Electron{
+		newElectron(ID(noopatom{}), nil),
+		newElectron(ID(noopatom{}), nil),
+		newElectron(ID(noopatom{}), nil),
+		newElectron(ID(failatom{}), nil),
+	}
+
+	for _, e := range electrons {
+		if _, err := rec.Send(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+
+		rec.next(ctx, t)
+	}
+
+	tests := map[string]struct {
+		atomID                             string
+		received, completed, errors, depth int
+	}{
+		"noop": {ID(noopatom{}), 3, 3, 0, 3},
+		"fail": {ID(failatom{}), 1, 1, 1, 1},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			eventually(t, time.Second, func() bool {
+				received, completed, errors, depth := monitor.counts(test.atomID)
+				return received == test.received &&
+					completed == test.completed &&
+					errors == test.errors &&
+					depth == test.depth
+			})
+		})
+	}
+}
+
+func TestWithMonitor_Invalid(t *testing.T) {
+	if err := WithMonitor(nil)(&atomizer{}); err == nil {
+		t.Fatal("expected error")
+	}
+}
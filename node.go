@@ -0,0 +1,50 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import "os"
+
+// WithNodeID sets the ID of the node the atomizer runs on which is stamped
+// on the events, errors and properties of the atomizer so that the node
+// which processed an electron can be identified. The hostname is used when
+// no node ID is set.
+func WithNodeID(id string) Option {
+	return func(a *atomizer) error {
+		if id == "" {
+			return simple("empty node id", nil)
+		}
+
+		a.nodeID = id
+		return nil
+	}
+}
+
+// hostname returns the default node ID
+func hostname() string {
+	name, err := os.Hostname()
+	if err != nil {
+		return ""
+	}
+
+	return name
+}
+
+// identify sets the node ID on the event or error when not already set
+func (a *atomizer) identify(e interface{}) {
+	var event *Event
+	switch v := e.(type) {
+	case *Event:
+		event = v
+	case *Error:
+		if v != nil {
+			event = v.Event
+		}
+	}
+
+	if event != nil && event.NodeID == "" {
+		event.NodeID = a.nodeID
+	}
+}
@@ -0,0 +1,78 @@
+package engine
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestAtomizer_NodeID(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	rec, a := recHarness(ctx, t, WithNodeID("node-1"), &noopatom{})
+	events := a.Events(100)
+
+	eventually(t, time.Second, func() bool {
+		return a.route(newElectron(ID(noopatom{}), nil)) != nil
+	})
+
+	e := newElectron(ID(noopatom{}), nil)
+	if _, err := rec.Send(ctx, e); err != nil {
+		t.Fatal(err)
+	}
+
+	if p := rec.next(ctx, t); p.NodeID != "node-1" {
+		t.Fatalf("expected completion from node-1, got %q", p.NodeID)
+	}
+
+	for {
+		var ev interface{}
+		select {
+		case <-ctx.Done():
+			t.Fatal("expected electron events")
+		case ev = <-events:
+		}
+
+		event, ok := ev.(*Event)
+		if !ok || event.ElectronID != e.ID {
+			continue
+		}
+
+		if event.NodeID != "node-1" {
+			t.Fatalf("expected event from node-1, got %q", event.NodeID)
+		}
+
+		return
+	}
+}
+
+func TestAtomizer_NodeID_Default(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	a, err := Atomize(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	name, err := os.Hostname()
+	if err != nil {
+		t.Skip("hostname unavailable")
+	}
+
+	if got := a.(*atomizer).nodeID; got != name {
+		t.Fatalf("expected hostname %s, got %s", name, got)
+	}
+
+	if err = WithNodeID("")(&atomizer{}); err == nil {
+		t.Fatal("expected error")
+	}
+}
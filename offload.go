@@ -0,0 +1,96 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import (
+	"strconv"
+	"sync/atomic"
+)
+
+// offloading re-publishes the electrons received while the node is
+// overloaded to a conductor shared with other nodes
+type offloading struct {
+	threshold int
+	out       Conductor
+
+	// relaying is the number of offloaded electrons
+	// still tracked in flight
+	relaying int64
+}
+
+// WithOffload offloads the electrons received while threshold electrons
+// are already in flight on the node to the outbound conductor so another
+// node sharing the conductor can pick them up rather than queueing them
+// locally. The completion of an offloaded electron is relayed to the
+// conductor it was received from. Electrons which were already forwarded
+// or offloaded by a node are always processed locally so they cannot
+// bounce between overloaded nodes.
+func WithOffload(threshold int, out Conductor) Option {
+	return func(a *atomizer) error {
+		if threshold < 1 {
+			return simple(
+				"invalid offload threshold "+strconv.Itoa(threshold),
+				nil,
+			)
+		}
+
+		if out == nil {
+			return simple("nil offload conductor", nil)
+		}
+
+		a.offloading = &offloading{
+			threshold: threshold,
+			out:       out,
+		}
+		return nil
+	}
+}
+
+// offload sends the instance electron to the offload conductor when the
+// node is overloaded and returns false when it should be processed locally
+func (a *atomizer) offload(inst instance) bool {
+	if a.offloading == nil || inst.electron.Hops > 0 ||
+		a.backlog() < a.offloading.threshold {
+		return false
+	}
+
+	fwd := *inst.electron
+	fwd.Hops++
+
+	a.event(func() interface{} {
+		return &Event{
+			Message:     "offloading electron from overloaded node",
+			ElectronID:  inst.electron.ID,
+			AtomID:      inst.electron.AtomID,
+			ConductorID: ID(inst.conductor),
+		}
+	})
+
+	atomic.AddInt64(&a.offloading.relaying, 1)
+	a.inflight.add(inst.electron, inst.conductor)
+	a.routine(func() {
+		defer atomic.AddInt64(&a.offloading.relaying, -1)
+
+		a.relay(inst, a.offloading.out, &fwd)
+	})
+
+	return true
+}
+
+// backlog returns the number of electrons in flight on the node
+// excluding the electrons which were offloaded
+func (a *atomizer) backlog() int {
+	return a.inflight.depth() -
+		int(atomic.LoadInt64(&a.offloading.relaying))
+}
+
+// depth returns the number of electrons in flight
+func (f *inflight) depth() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return len(f.pending)
+}
@@ -0,0 +1,111 @@
+package engine
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAtomizer_Offload(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	gaterelease = make(chan struct{})
+	release := gaterelease
+
+	var once sync.Once
+	open := func() {
+		once.Do(func() {
+			close(release)
+		})
+	}
+	t.Cleanup(open)
+
+	atomID := ID(gateatom{})
+	out := newRecorder()
+	rec, a := recHarness(ctx, t, WithOffload(2, out), &gateatom{})
+
+	eventually(t, time.Second, func() bool {
+		return a.route(newElectron(atomID, nil)) != nil
+	})
+
+	for i := 0; i < 2; i++ {
+		if _, err := rec.Send(ctx, newElectron(atomID, nil)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	eventually(t, time.Second, func() bool {
+		return a.inflight.queued(atomID) == 2
+	})
+
+	for i := 0; i < 3; i++ {
+		e := newElectron(atomID, nil)
+		if _, err := rec.Send(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+
+		var offloaded *Electron
+		select {
+		case <-ctx.Done():
+			t.Fatal("electron not offloaded")
+		case offloaded = <-out.input:
+		}
+
+		if offloaded.ID != e.ID || offloaded.Hops != 1 {
+			t.Fatalf("expected offloaded electron with 1 hop, got %+v", offloaded)
+		}
+
+		if n := a.backlog(); n != 2 {
+			t.Fatalf("expected 2 electrons queued locally, got %v", n)
+		}
+	}
+
+	// Electrons offloaded by another node are processed locally
+	e := newElectron(atomID, nil)
+	e.Hops = 1
+	if _, err := rec.Send(ctx, e); err != nil {
+		t.Fatal(err)
+	}
+
+	eventually(t, time.Second, func() bool {
+		return a.backlog() == 3
+	})
+
+	select {
+	case offloaded := <-out.input:
+		t.Fatalf("expected offloaded electron to stay local, got %+v", offloaded)
+	default:
+	}
+
+	// Finish the local electrons before the gate is reused
+	open()
+	for i := 0; i < 3; i++ {
+		rec.next(ctx, t)
+	}
+}
+
+func TestWithOffload_Invalid(t *testing.T) {
+	tests := map[string]struct {
+		threshold int
+		out       Conductor
+	}{
+		"zero threshold": {0, newRecorder()},
+		"nil conductor":  {1, nil},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := WithOffload(test.threshold, test.out)(&atomizer{}); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
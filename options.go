@@ -0,0 +1,35 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+// Option configures optional behavior of the atomizer. Options are passed
+// to Atomize alongside any registrations and are applied before the
+// registrations are stored.
+type Option func(a *atomizer) error
+
+// options applies any options in the values passed to Atomize and returns
+// the remaining values for registration
+func (a *atomizer) options(values ...interface{}) ([]interface{}, error) {
+	registrations := make([]interface{}, 0, len(values))
+
+	for _, value := range values {
+		opt, ok := value.(Option)
+		if !ok {
+			registrations = append(registrations, value)
+			continue
+		}
+
+		if opt == nil {
+			continue
+		}
+
+		if err := opt(a); err != nil {
+			return nil, err
+		}
+	}
+
+	return registrations, nil
+}
@@ -0,0 +1,67 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import "context"
+
+// OutboundMiddleware transforms the properties of a processed electron
+// before they are completed on the conductor
+type OutboundMiddleware func(
+	ctx context.Context,
+	electron Electron,
+	p Properties,
+) (Properties, error)
+
+// WithOutboundMiddleware applies the middleware to the properties of
+// every processed electron just before they are completed so results can
+// be redacted, enriched or reformatted uniformly. Middlewares are applied
+// in the order they are configured with each receiving the properties
+// returned by the previous middleware. A middleware error stops the chain
+// and is completed as the error of the electron.
+func WithOutboundMiddleware(middleware ...OutboundMiddleware) Option {
+	return func(a *atomizer) error {
+		for _, mw := range middleware {
+			if mw == nil {
+				return simple("nil outbound middleware", nil)
+			}
+		}
+
+		a.outbound = append(a.outbound, middleware...)
+		return nil
+	}
+}
+
+// transform applies the outbound middleware to the properties
+func (a *atomizer) transform(
+	ctx context.Context,
+	inst *instance,
+	p *Properties,
+) {
+	if len(a.outbound) == 0 || p == nil || inst.electron == nil {
+		return
+	}
+
+	out := *p
+	for _, mw := range a.outbound {
+		var err error
+		out, err = mw(ctx, *inst.electron, out)
+		if err != nil {
+			p.Result = nil
+			p.Error = &Error{
+				Event: &Event{
+					Message:    "outbound middleware failed",
+					ElectronID: inst.electron.ID,
+					AtomID:     inst.electron.AtomID,
+				},
+				Internal: err,
+			}
+
+			return
+		}
+	}
+
+	*p = out
+}
@@ -0,0 +1,101 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+var errOutbound = errors.New("outbound failure")
+
+func TestAtomizer_OutboundMiddleware(t *testing.T) {
+	redact := func(
+		ctx context.Context,
+		e Electron,
+		p Properties,
+	) (Properties, error) {
+		result := map[string]string{}
+		if err := json.Unmarshal(p.Result, &result); err != nil {
+			return p, err
+		}
+
+		delete(result, "secret")
+
+		var err error
+		p.Result, err = json.Marshal(result)
+		return p, err
+	}
+
+	fail := func(
+		ctx context.Context,
+		e Electron,
+		p Properties,
+	) (Properties, error) {
+		return p, errOutbound
+	}
+
+	tests := map[string]struct {
+		middleware []OutboundMiddleware
+		result     string
+		err        error
+	}{
+		"redact": {
+			[]OutboundMiddleware{redact},
+			`{"user":"test"}`,
+			nil,
+		},
+		"fail": {
+			[]OutboundMiddleware{redact, fail},
+			"",
+			errOutbound,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			d := time.Second * 30
+			ctx, cancel := _ctxT(context.TODO(), &d)
+			defer cancel()
+
+			reset(ctx, t)
+			t.Cleanup(func() {
+				reset(context.TODO(), t)
+			})
+
+			rec, a := recHarness(
+				ctx,
+				t,
+				WithOutboundMiddleware(test.middleware...),
+				&returner{},
+			)
+
+			eventually(t, time.Second, func() bool {
+				return a.route(newElectron(ID(returner{}), nil)) != nil
+			})
+
+			payload, err := json.Marshal(&printerdata{
+				Message: `{"user":"test","secret":"hunter2"}`,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = rec.Send(ctx, newElectron(ID(returner{}), payload))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			p := rec.next(ctx, t)
+			if !errors.Is(p.Error, test.err) ||
+				(test.err == nil && p.Error != nil) {
+				t.Fatalf("expected error %v, got %v", test.err, p.Error)
+			}
+
+			if string(p.Result) != test.result {
+				t.Fatalf("expected %s, got %s", test.result, p.Result)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// moodyatom panics when asked to and echoes the payload otherwise
+type moodyatom struct{}
+
+func (*moodyatom) Process(
+	ctx context.Context,
+	conductor Conductor,
+	electron *Electron,
+) ([]byte, error) {
+	if string(electron.Payload) == `"panic"` {
+		panic("moody panic")
+	}
+
+	return electron.Payload, nil
+}
+
+func TestAtomizer_exec_Panic(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	rec, a := recHarness(ctx, t, &moodyatom{})
+
+	eventually(t, time.Second, func() bool {
+		return a.route(newElectron(ID(moodyatom{}), nil)) != nil
+	})
+
+	_, err := rec.Send(ctx, newElectron(ID(moodyatom{}), []byte(`"panic"`)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := rec.next(ctx, t)
+
+	var perr *PanicError
+	if !errors.As(p.Error, &perr) {
+		t.Fatalf("expected panic error, got %v", p.Error)
+	}
+
+	if perr.Value != "moody panic" {
+		t.Fatalf("expected panic value, got %q", perr.Value)
+	}
+
+	if !strings.Contains(perr.Stack, "moodyatom") {
+		t.Fatalf("expected stack trace of the atom, got %s", perr.Stack)
+	}
+
+	if p.Status != StatusFailed || p.End.IsZero() {
+		t.Fatalf("expected failed status and end time, got %s", p.Status)
+	}
+
+	_, err = rec.Send(ctx, newElectron(ID(moodyatom{}), []byte(`"calm"`)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p = rec.next(ctx, t)
+	if p.Error != nil || string(p.Result) != `"calm"` {
+		t.Fatalf("expected atom to keep processing, got %v", p.Error)
+	}
+}
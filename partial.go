@@ -0,0 +1,111 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import (
+	"context"
+	"sync"
+)
+
+// PartialCompleter is an optional interface for conductors which forward
+// the partial results of an electron to its sender while the electron is
+// still processing, such as the progress of a long running batch job.
+// CompletePartial is called on the routine of the atom for each call to
+// EmitPartial, in order, before the properties of the electron are
+// completed. Errors are emitted by the atomizer and do not fail the
+// electron. Conductors which do not implement PartialCompleter only
+// receive the partial results in the Partials of the completed properties.
+type PartialCompleter interface {
+	CompletePartial(ctx context.Context, electronID string, partial []byte) error
+}
+
+type partialsKey struct{}
+
+// partials collects the partial results emitted by an atom instance
+type partials struct {
+	mu      sync.Mutex
+	results [][]byte
+
+	// forward streams each partial result to the
+	// conductor when it is a PartialCompleter
+	forward func(partial []byte)
+}
+
+// withPartials adds the partial result collector of the atom
+// instance to its context
+func withPartials(ctx context.Context, p *partials) context.Context {
+	return context.WithValue(ctx, partialsKey{}, p)
+}
+
+// EmitPartial records a partial result of the electron being processed
+// by an atom using the context passed to the Process method of the atom.
+// Partial results are included in the Properties of the electron so that
+// the conductor receives the progress of an electron which fails or times
+// out before the atom returns its result. When the conductor implements
+// PartialCompleter the partial result is also forwarded to the conductor
+// before EmitPartial returns. EmitPartial returns false when the context
+// is not the context of an atom instance.
+func EmitPartial(ctx context.Context, result []byte) bool {
+	if ctx == nil {
+		return false
+	}
+
+	p, ok := ctx.Value(partialsKey{}).(*partials)
+	if !ok {
+		return false
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	partial := append([]byte(nil), result...)
+	p.results = append(p.results, partial)
+
+	// Forwarding under the lock keeps the partials in order
+	if p.forward != nil {
+		p.forward(partial)
+	}
+
+	return true
+}
+
+// get returns the partial results emitted so far
+func (p *partials) get() [][]byte {
+	if p == nil {
+		return nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([][]byte(nil), p.results...)
+}
+
+// streamPartials forwards the partial results of the instance to its
+// conductor when the conductor is a PartialCompleter
+func (a *atomizer) streamPartials(ctx context.Context, inst *instance) {
+	var pc PartialCompleter
+	if !conductorAs(inst.conductor, &pc) || !inst.electron.ExpectsReply() {
+		return
+	}
+
+	inst.partials.forward = func(partial []byte) {
+		err := pc.CompletePartial(ctx, inst.electron.ID, partial)
+		if err != nil {
+			a.err(func() error {
+				return &Error{
+					Event: &Event{
+						Message:     "error completing partial result",
+						ElectronID:  inst.electron.ID,
+						AtomID:      inst.electron.AtomID,
+						ConductorID: ID(inst.conductor),
+					},
+					Internal: err,
+				}
+			})
+		}
+	}
+}
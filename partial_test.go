@@ -0,0 +1,204 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// partialatom emits a partial result and then blocks until its
+// context is cancelled
+type partialatom struct{}
+
+func (*partialatom) Process(
+	ctx context.Context,
+	conductor Conductor,
+	electron *Electron,
+) ([]byte, error) {
+	if !EmitPartial(ctx, []byte(`{"step":1}`)) {
+		return nil, errors.New("unable to emit partial")
+	}
+
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestAtomizer_TimeoutDetails(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	rec, a := recHarness(ctx, t, &partialatom{}, &noopatom{})
+
+	eventually(t, time.Second, func() bool {
+		return a.route(newElectron(ID(partialatom{}), nil)) != nil &&
+			a.route(newElectron(ID(noopatom{}), nil)) != nil
+	})
+
+	timeout := time.Millisecond * 50
+	e := newElectron(ID(partialatom{}), nil)
+	e.Timeout = &timeout
+
+	if _, err := rec.Send(ctx, e); err != nil {
+		t.Fatal(err)
+	}
+
+	p := rec.next(ctx, t)
+	if p.Status != StatusTimedOut {
+		t.Fatalf("expected status %s, got %s", StatusTimedOut, p.Status)
+	}
+
+	if !errors.Is(p.Error, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", p.Error)
+	}
+
+	if p.Timeout != timeout || p.Elapsed() < timeout {
+		t.Fatalf("unexpected timing %s of %s", p.Elapsed(), p.Timeout)
+	}
+
+	if len(p.Partials) != 1 || string(p.Partials[0]) != `{"step":1}` {
+		t.Fatalf("unexpected partials %s", p.Partials)
+	}
+
+	e = newElectron(ID(noopatom{}), nil)
+	if _, err := rec.Send(ctx, e); err != nil {
+		t.Fatal(err)
+	}
+
+	if p = rec.next(ctx, t); p.Status != StatusSucceeded {
+		t.Fatalf("expected status %s, got %s", StatusSucceeded, p.Status)
+	}
+}
+
+// progressatom emits three partial results before returning its result
+type progressatom struct{}
+
+func (*progressatom) Process(
+	ctx context.Context,
+	conductor Conductor,
+	electron *Electron,
+) ([]byte, error) {
+	for i := 1; i <= 3; i++ {
+		if !EmitPartial(ctx, []byte(fmt.Sprintf(`{"step":%v}`, i))) {
+			return nil, errors.New("unable to emit partial")
+		}
+	}
+
+	return []byte(`{"step":"done"}`), nil
+}
+
+// partialrecorder is a recorder which records the partial results
+// completed to it along with the final properties in order
+type partialrecorder struct {
+	*recorder
+
+	mu    sync.Mutex
+	order []string
+}
+
+func (r *partialrecorder) CompletePartial(
+	ctx context.Context,
+	electronID string,
+	partial []byte,
+) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.order = append(r.order, electronID+" "+string(partial))
+	return nil
+}
+
+func (r *partialrecorder) Complete(ctx context.Context, p *Properties) error {
+	r.mu.Lock()
+	r.order = append(r.order, p.ElectronID+" "+string(p.Result))
+	r.mu.Unlock()
+
+	return r.recorder.Complete(ctx, p)
+}
+
+func (r *partialrecorder) recorded() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]string(nil), r.order...)
+}
+
+func TestAtomizer_StreamPartials(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	streaming := &partialrecorder{recorder: newRecorder()}
+	plain := newRecorder()
+	mizer, err := Atomize(ctx, &progressatom{}, streaming, plain)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, _ := mizer.(*atomizer)
+	if err = a.Exec(); err != nil {
+		t.Fatal(err)
+	}
+
+	eventually(t, time.Second, func() bool {
+		return a.route(newElectron(ID(progressatom{}), nil)) != nil
+	})
+
+	e := newElectron(ID(progressatom{}), nil)
+	streaming.input <- e
+
+	p := streaming.next(ctx, t)
+	if p.Error != nil {
+		t.Fatal(p.Error)
+	}
+
+	expected := []string{
+		e.ID + ` {"step":1}`,
+		e.ID + ` {"step":2}`,
+		e.ID + ` {"step":3}`,
+		e.ID + ` {"step":"done"}`,
+	}
+
+	if diff := cmp.Diff(expected, streaming.recorded()); diff != "" {
+		t.Fatalf("unexpected completions (-want +got):\n%s", diff)
+	}
+
+	// The partials are still included in the final properties
+	if len(p.Partials) != 3 {
+		t.Fatalf("expected 3 partials, got %s", p.Partials)
+	}
+
+	// Conductors without partial support only
+	// receive the partials in the final properties
+	plain.input <- newElectron(ID(progressatom{}), nil)
+
+	p = plain.next(ctx, t)
+	if p.Error != nil || len(p.Partials) != 3 {
+		t.Fatalf("expected 3 partials without error, got %+v", p)
+	}
+
+	if got := len(streaming.recorded()); got != len(expected) {
+		t.Fatalf("expected %v streamed completions, got %v", len(expected), got)
+	}
+}
+
+func TestEmitPartial_NoInstance(t *testing.T) {
+	if EmitPartial(context.TODO(), []byte(`{}`)) {
+		t.Fatal("expected partial to be rejected")
+	}
+}
@@ -0,0 +1,331 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrInstanceInterrupted is set as the error of an electron which was
+// executing when the atomizer stopped and was completed on recovery
+var ErrInstanceInterrupted = errors.New("instance interrupted")
+
+// InstanceState is the processing state of a persisted instance
+type InstanceState string
+
+const (
+	// InstanceQueued indicates the electron was received
+	// but was not yet bonded to an atom
+	InstanceQueued InstanceState = "queued"
+
+	// InstanceBonded indicates the electron was bonded
+	// to an atom and was executing
+	InstanceBonded InstanceState = "bonded"
+)
+
+// PersistedInstance is the serializable state of an instance. The
+// conductor is referenced by ID and resolved from the registered
+// conductors on recovery.
+type PersistedInstance struct {
+	// Electron is the electron as received by the atomizer
+	Electron *Electron
+
+	// ConductorID is the ID of the conductor the electron
+	// was received from
+	ConductorID string
+
+	// State is the processing state of the instance
+	State InstanceState
+
+	// Received is when the electron was received
+	Received time.Time
+
+	// Bonded is when the electron was bonded to an atom, if it was
+	Bonded time.Time
+}
+
+// InstanceStore persists the instances of the atomizer so that they can
+// be recovered after a crash (see Recover). Instances are saved when they
+// are received and when they are bonded, and deleted once they are done.
+type InstanceStore interface {
+	// Save stores the instance, replacing any instance
+	// with the same electron ID
+	Save(ctx context.Context, inst *PersistedInstance) error
+
+	// Delete removes the instance of the electron
+	Delete(ctx context.Context, electronID string) error
+
+	// Load returns every stored instance
+	Load(ctx context.Context) ([]*PersistedInstance, error)
+}
+
+// WithInstanceStore persists the instances received from the conductors
+// in the instance store so that they can be recovered after a crash.
+// Instances which are still in-flight when the atomizer is closed remain
+// in the store to be recovered on restart.
+func WithInstanceStore(store InstanceStore) Option {
+	return func(a *atomizer) error {
+		if store == nil {
+			return simple("nil instance store", nil)
+		}
+
+		a.instances = store
+		a.inflight.finished = a.forget
+		return nil
+	}
+}
+
+// persist saves the state of the instance to the instance store
+func (a *atomizer) persist(inst *instance, state InstanceState) {
+	if a.instances == nil || inst.electron == nil {
+		return
+	}
+
+	p := &PersistedInstance{
+		Electron:    inst.electron,
+		ConductorID: ID(inst.conductor),
+		State:       state,
+		Received:    inst.received,
+	}
+
+	if state == InstanceBonded {
+		p.Bonded = time.Now()
+	}
+
+	if err := a.instances.Save(a.ctx, p); err != nil {
+		a.err(func() error {
+			return &Error{
+				Event: &Event{
+					Message:     "error persisting instance",
+					ElectronID:  inst.electron.ID,
+					AtomID:      inst.electron.AtomID,
+					ConductorID: ID(inst.conductor),
+				},
+				Internal: err,
+			}
+		})
+	}
+}
+
+// forget deletes the instance of a done electron from the instance
+// store. Instances are kept while the atomizer is closing so that
+// the electrons interrupted by the close are recovered on restart.
+func (a *atomizer) forget(e *Electron) {
+	if a.ctx.Err() != nil {
+		return
+	}
+
+	if err := a.instances.Delete(a.ctx, e.ID); err != nil {
+		a.err(func() error {
+			return &Error{
+				Event: &Event{
+					Message:    "error deleting persisted instance",
+					ElectronID: e.ID,
+					AtomID:     e.AtomID,
+				},
+				Internal: err,
+			}
+		})
+	}
+}
+
+// Recover resumes the instances persisted in the instance store by a
+// previous run of the atomizer (see WithInstanceStore) and returns the
+// number of recovered instances. Recover must be called once the
+// conductors the instances were received from are registered.
+//
+// Queued instances are processed again. Bonded instances may have
+// partially executed so they are not re-run, instead their electron is
+// completed with ErrInstanceInterrupted and stored in the dead letter
+// store when configured so that it can be reprocessed (see Reprocess)
+// once it is known to be safe. Instances whose conductor is not
+// registered remain in the store.
+func (a *atomizer) Recover(ctx context.Context) (int, error) {
+	if a.instances == nil {
+		return 0, simple("instance store not configured", nil)
+	}
+
+	if ctx == nil {
+		ctx = a.ctx
+	}
+
+	persisted, err := a.instances.Load(ctx)
+	if err != nil {
+		return 0, simple("error loading instances", err)
+	}
+
+	var count int
+	for _, p := range persisted {
+		a.conductorsMu.RLock()
+		conductor, ok := a.conductors[p.ConductorID]
+		a.conductorsMu.RUnlock()
+
+		if !ok {
+			a.err(func() error {
+				return &Error{
+					Event: &Event{
+						Message:     "persisted instance conductor not registered",
+						ElectronID:  p.Electron.ID,
+						AtomID:      p.Electron.AtomID,
+						ConductorID: p.ConductorID,
+					},
+				}
+			})
+
+			continue
+		}
+
+		inst := instance{
+			electron:  p.Electron,
+			conductor: conductor,
+			timing:    &timing{},
+			received:  p.Received,
+		}
+
+		if p.State == InstanceBonded {
+			a.interrupted(ctx, inst, p.Bonded)
+			count++
+			continue
+		}
+
+		a.inflight.add(p.Electron, conductor)
+		if !a.enqueue(inst) {
+			a.inflight.done(p.Electron)
+			return count, simple("context closed", nil)
+		}
+
+		count++
+		a.event(func() interface{} {
+			return &Event{
+				Message:     "electron recovered from instance store",
+				ElectronID:  p.Electron.ID,
+				AtomID:      p.Electron.AtomID,
+				ConductorID: p.ConductorID,
+			}
+		})
+	}
+
+	return count, nil
+}
+
+// interrupted completes the electron of an instance which was executing
+// when the atomizer stopped
+func (a *atomizer) interrupted(
+	ctx context.Context,
+	inst instance,
+	bonded time.Time,
+) {
+	err := &Error{
+		Event: &Event{
+			Message:     "instance interrupted",
+			ElectronID:  inst.electron.ID,
+			AtomID:      inst.electron.AtomID,
+			ConductorID: ID(inst.conductor),
+		},
+		Internal: ErrInstanceInterrupted,
+	}
+
+	a.deadletter(&inst, err)
+
+	if inst.electron.ExpectsReply() {
+		completion := inst.conductor.Complete(ctx, &Properties{
+			ElectronID:    inst.electron.ID,
+			AtomID:        inst.electron.AtomID,
+			CorrelationID: inst.electron.CorrelationID,
+			NodeID:        a.nodeID,
+			Start:         bonded,
+			End:           time.Now(),
+			Error:         err,
+			Status:        StatusFailed,
+		})
+
+		if completion != nil {
+			a.err(func() error {
+				return completion
+			})
+		}
+	}
+
+	a.forget(inst.electron)
+	a.err(func() error {
+		return err
+	})
+}
+
+// MemoryInstanceStore is an in-memory InstanceStore. It only survives a
+// restart of the atomizer within the same process and is intended for
+// testing.
+type MemoryInstanceStore struct {
+	mu        sync.Mutex
+	instances map[string]PersistedInstance
+}
+
+// Save stores a copy of the instance
+func (s *MemoryInstanceStore) Save(
+	_ context.Context,
+	inst *PersistedInstance,
+) error {
+	if inst == nil || inst.Electron == nil {
+		return simple("invalid persisted instance", nil)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.instances == nil {
+		s.instances = make(map[string]PersistedInstance)
+	}
+
+	p := *inst
+	e := *inst.Electron
+	p.Electron = &e
+
+	s.instances[e.ID] = p
+	return nil
+}
+
+// Delete removes the instance of the electron
+func (s *MemoryInstanceStore) Delete(_ context.Context, electronID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.instances, electronID)
+	return nil
+}
+
+// Load returns copies of the stored instances ordered by the
+// time they were received
+func (s *MemoryInstanceStore) Load(context.Context) ([]*PersistedInstance, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make([]*PersistedInstance, 0, len(s.instances))
+	for _, inst := range s.instances {
+		p := inst
+		e := *inst.Electron
+		p.Electron = &e
+
+		out = append(out, &p)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Received.Before(out[j].Received)
+	})
+
+	return out, nil
+}
+
+// Len returns the number of stored instances
+func (s *MemoryInstanceStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.instances)
+}
@@ -0,0 +1,113 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAtomizer_Recover(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	release := stick()
+	t.Cleanup(func() { close(release) })
+
+	store := &MemoryInstanceStore{}
+
+	crashctx, crash := context.WithCancel(ctx)
+	rec, a := recHarness(
+		crashctx,
+		t,
+		WithInstanceStore(store),
+		&stuckatom{},
+	)
+
+	atomID := ID(stuckatom{})
+	eventually(t, time.Second, func() bool {
+		return a.route(newElectron(atomID, nil)) != nil
+	})
+
+	// The first electron blocks the atom so the second remains queued
+	bonded := newElectron(atomID, nil)
+	queued := newElectron(atomID, nil)
+	for _, e := range []*Electron{bonded, queued} {
+		if _, err := rec.Send(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	eventually(t, time.Second, func() bool {
+		persisted, _ := store.Load(ctx)
+		return len(persisted) == 2 &&
+			persisted[0].State == InstanceBonded &&
+			persisted[1].State == InstanceQueued
+	})
+
+	crash()
+	a.Wait()
+
+	// Restart with the same store
+	unstuck := stick()
+	close(unstuck)
+
+	deadletters := &MemoryDeadLetterStore{}
+	rec, a = recHarness(
+		ctx,
+		t,
+		WithInstanceStore(store),
+		WithDeadLetter(deadletters),
+		&stuckatom{},
+	)
+
+	eventually(t, time.Second, func() bool {
+		return a.route(newElectron(atomID, nil)) != nil
+	})
+
+	eventually(t, time.Second, func() bool {
+		a.conductorsMu.RLock()
+		defer a.conductorsMu.RUnlock()
+
+		_, ok := a.conductors[ID(rec)]
+		return ok
+	})
+
+	count, err := a.Recover(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if count != 2 {
+		t.Fatalf("expected 2 recovered instances, got %v", count)
+	}
+
+	completions := make(map[string]*Properties)
+	for i := 0; i < 2; i++ {
+		p := rec.next(ctx, t)
+		completions[p.ElectronID] = p
+	}
+
+	if p := completions[bonded.ID]; p == nil ||
+		!errors.Is(p.Error, ErrInstanceInterrupted) {
+		t.Fatalf("expected interrupted bonded instance, got %v", p)
+	}
+
+	if p := completions[queued.ID]; p == nil || p.Error != nil {
+		t.Fatalf("expected queued instance to be processed, got %v", p)
+	}
+
+	if deadletters.Len() != 1 {
+		t.Fatalf("expected interrupted dead letter, got %v", deadletters.Len())
+	}
+
+	eventually(t, time.Second, func() bool {
+		return store.Len() == 0
+	})
+}
@@ -0,0 +1,106 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+//go:build (linux || darwin || freebsd) && cgo
+// +build linux darwin freebsd
+// +build cgo
+
+package engine
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"plugin"
+	"strings"
+)
+
+// AtomsSymbol is the symbol which MUST be exported by atom plugins. The
+// symbol must be a function with the signature `func() []engine.Atom`
+const AtomsSymbol = "Atoms"
+
+// LoadPlugins opens each of the Go plugins (.so files) in the directory,
+// looks up the exported Atoms function of the plugin and registers the
+// returned atoms with the atomizer. Plugins which fail to load (i.e. built
+// with a different version of the atomizer or missing the Atoms symbol)
+// are skipped and reported in the returned error while the atoms from the
+// remaining plugins are still registered and returned.
+//
+// NOTE: Go plugins are only supported on Linux, FreeBSD and macOS with cgo
+// enabled. Plugins must be built with the same Go version and the same
+// versions of all shared packages as the host.
+func LoadPlugins(dir string) ([]Atom, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, simple("error reading plugin directory "+dir, err)
+	}
+
+	var atoms []Atom
+	var failures []string
+
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".so" {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+
+		loaded, err := loadPlugin(path)
+		if err != nil {
+			failures = append(failures, err.Error())
+			continue
+		}
+
+		values := make([]interface{}, 0, len(loaded))
+		for _, atom := range loaded {
+			values = append(values, atom)
+		}
+
+		if err = Register(values...); err != nil {
+			failures = append(
+				failures,
+				simple("error registering atoms from "+path, err).Error(),
+			)
+			continue
+		}
+
+		atoms = append(atoms, loaded...)
+	}
+
+	if len(failures) > 0 {
+		return atoms, simple(
+			"error loading plugins: "+strings.Join(failures, "; "),
+			nil,
+		)
+	}
+
+	return atoms, nil
+}
+
+// loadPlugin opens the plugin and returns the atoms it exports
+func loadPlugin(path string) ([]Atom, error) {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return nil, simple("error opening plugin "+path, err)
+	}
+
+	sym, err := p.Lookup(AtomsSymbol)
+	if err != nil {
+		return nil, simple(
+			"plugin "+path+" missing symbol "+AtomsSymbol,
+			err,
+		)
+	}
+
+	atomsFn, ok := sym.(func() []Atom)
+	if !ok {
+		return nil, simple(
+			"plugin "+path+" symbol "+AtomsSymbol+
+				" is not a func() []Atom",
+			nil,
+		)
+	}
+
+	return atomsFn(), nil
+}
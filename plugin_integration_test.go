@@ -0,0 +1,46 @@
+//go:build plugin && (linux || darwin || freebsd) && cgo
+// +build plugin
+// +build linux darwin freebsd
+// +build cgo
+
+package engine
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestLoadPlugins builds the test plugin and a host binary which loads it.
+// The host is built separately from the test binary since a plugin can only
+// be loaded by a binary built with the exact same version of this package.
+//
+// Run with: go test -tags plugin -run TestLoadPlugins
+func TestLoadPlugins(t *testing.T) {
+	dir := t.TempDir()
+
+	build := exec.Command(
+		"go", "build", "-buildmode=plugin",
+		"-o", filepath.Join(dir, "atoms.so"),
+		"./testdata/plugin",
+	)
+	if out, err := build.CombinedOutput(); err != nil {
+		t.Fatalf("error building plugin | %s | %s", err, out)
+	}
+
+	host := filepath.Join(t.TempDir(), "host")
+	build = exec.Command("go", "build", "-o", host, "./testdata/pluginhost")
+	if out, err := build.CombinedOutput(); err != nil {
+		t.Fatalf("error building host | %s | %s", err, out)
+	}
+
+	out, err := exec.Command(host, dir).CombinedOutput()
+	if err != nil {
+		t.Fatalf("error loading plugins | %s | %s", err, out)
+	}
+
+	if strings.TrimSpace(string(out)) != "main.echo" {
+		t.Fatalf("expected main.echo atom, got %s", out)
+	}
+}
@@ -0,0 +1,43 @@
+//go:build (linux || darwin || freebsd) && cgo
+// +build linux darwin freebsd
+// +build cgo
+
+package engine
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadPlugins_Errs(t *testing.T) {
+	dir := t.TempDir()
+
+	// Files without the .so extension are skipped
+	err := ioutil.WriteFile(filepath.Join(dir, "readme.txt"), []byte("x"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	atoms, err := LoadPlugins(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(atoms) != 0 {
+		t.Fatalf("expected no atoms, got %v", len(atoms))
+	}
+
+	err = ioutil.WriteFile(filepath.Join(dir, "bad.so"), []byte("not a plugin"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = LoadPlugins(dir); err == nil {
+		t.Fatal("expected error for invalid plugin")
+	}
+
+	if _, err = LoadPlugins(filepath.Join(dir, "missing")); err == nil {
+		t.Fatal("expected error for missing directory")
+	}
+}
@@ -0,0 +1,19 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+//go:build !(linux || darwin || freebsd) || !cgo
+// +build !linux,!darwin,!freebsd !cgo
+
+package engine
+
+// AtomsSymbol is the symbol which MUST be exported by atom plugins. The
+// symbol must be a function with the signature `func() []engine.Atom`
+const AtomsSymbol = "Atoms"
+
+// LoadPlugins is not supported on this platform, Go plugins are only
+// supported on Linux, FreeBSD and macOS with cgo enabled
+func LoadPlugins(dir string) ([]Atom, error) {
+	return nil, simple("plugins are not supported on this platform", nil)
+}
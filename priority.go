@@ -0,0 +1,258 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultQueueSize is the number of electrons the priority queue holds
+// before receiving from the conductors blocks
+const DefaultQueueSize = 1000
+
+// WithConductorPriority sets the priority of the electrons received from
+// the conductor with the ID. Electrons waiting to be distributed are
+// dispatched in order of the Priority of the electron and then the
+// priority of their conductor, with higher values dispatched first.
+// Conductors without a priority have a priority of 0.
+func WithConductorPriority(conductorID string, level int) Option {
+	return func(a *atomizer) error {
+		if conductorID == "" {
+			return simple("empty priority conductor id", nil)
+		}
+
+		if a.priorities == nil {
+			a.priorities = make(map[string]int)
+		}
+
+		a.priorities[conductorID] = level
+
+		if a.queue == nil {
+			a.queue = newPQueue(DefaultQueueSize)
+		}
+
+		return nil
+	}
+}
+
+// enqueue pushes the instance for distribution, through the priority
+// queue when configured, and returns false if the atomizer closed
+func (a *atomizer) enqueue(inst instance) bool {
+	if a.queue != nil {
+		return a.queue.push(
+			a.ctx,
+			inst,
+			a.priorities[ID(inst.conductor)],
+			a.weight(inst.electron.TenantID),
+		) == nil
+	}
+
+	select {
+	case <-a.ctx.Done():
+		return false
+	case a.electrons <- inst:
+		return true
+	}
+}
+
+// dequeue pushes the electrons from the priority queue to distribute
+// in priority order
+func (a *atomizer) dequeue() {
+	for {
+		inst, ok := a.queue.pop(a.ctx)
+		if !ok {
+			return
+		}
+
+		select {
+		case <-a.ctx.Done():
+			return
+		case a.electrons <- inst:
+		}
+	}
+}
+
+// queued is an instance waiting in the priority queue
+type queued struct {
+	inst      instance
+	priority  int
+	conductor int
+	finish    float64
+	seq       uint64
+	enqueued  time.Time
+}
+
+// queuedHeap orders the queued instances by electron priority, then
+// conductor priority, then the virtual finish time of their tenant and
+// then the order they were queued
+type queuedHeap []*queued
+
+func (h queuedHeap) Len() int { return len(h) }
+
+func (h queuedHeap) Less(i, j int) bool {
+	return ahead(h[i], h[j], h[i].priority, h[j].priority)
+}
+
+// ahead indicates if a is dispatched before b when they have
+// the priorities pa and pb
+func ahead(a, b *queued, pa, pb int) bool {
+	if pa != pb {
+		return pa > pb
+	}
+
+	if a.conductor != b.conductor {
+		return a.conductor > b.conductor
+	}
+
+	if a.finish != b.finish {
+		return a.finish < b.finish
+	}
+
+	return a.seq < b.seq
+}
+
+func (h queuedHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *queuedHeap) Push(x interface{}) {
+	q, _ := x.(*queued)
+	*h = append(*h, q)
+}
+
+func (h *queuedHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	q := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+
+	return q
+}
+
+// pqueue is a bounded priority queue of instances waiting to be
+// distributed
+type pqueue struct {
+	mu    sync.Mutex
+	items queuedHeap
+	seq   uint64
+	slots chan struct{}
+	ready chan struct{}
+
+	// vtime is the virtual time of the queue and finish the virtual
+	// finish time of the last instance queued for each tenant with
+	// queued instances, counted by tenants. A tenant without queued
+	// instances starts again from the virtual time.
+	vtime   float64
+	finish  map[string]float64
+	tenants map[string]int
+
+	// aging raises the priority of a queued instance by one
+	// for every interval it waits (see WithPriorityAging)
+	aging time.Duration
+}
+
+func newPQueue(size int) *pqueue {
+	return &pqueue{
+		slots:   make(chan struct{}, size),
+		ready:   make(chan struct{}, 1),
+		finish:  make(map[string]float64),
+		tenants: make(map[string]int),
+	}
+}
+
+// push adds the instance to the queue, blocking while the queue is full.
+// The instance is stamped with a virtual finish time advancing by the
+// inverse of the tenant weight so that tenants are dispatched in
+// proportion to their weights regardless of the volume they submit.
+func (q *pqueue) push(
+	ctx context.Context,
+	inst instance,
+	conductor int,
+	weight int,
+) error {
+	select {
+	case <-ctx.Done():
+		return simple("context closed", nil)
+	case q.slots <- struct{}{}:
+	}
+
+	q.mu.Lock()
+	q.seq++
+
+	tenant := inst.electron.TenantID
+	start := q.finish[tenant]
+	if start < q.vtime {
+		start = q.vtime
+	}
+	q.finish[tenant] = start + 1/float64(weight)
+	q.tenants[tenant]++
+
+	heap.Push(&q.items, &queued{
+		inst:      inst,
+		priority:  inst.electron.Priority,
+		conductor: conductor,
+		finish:    q.finish[tenant],
+		seq:       q.seq,
+		enqueued:  time.Now(),
+	})
+	q.mu.Unlock()
+
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
+
+	return nil
+}
+
+// pop removes the highest priority instance, blocking while the
+// queue is empty
+func (q *pqueue) pop(ctx context.Context) (instance, bool) {
+	for {
+		q.mu.Lock()
+		if q.items.Len() > 0 {
+			next := q.take()
+			if next.finish > q.vtime {
+				q.vtime = next.finish
+			}
+			q.release(next.inst.electron.TenantID)
+			q.mu.Unlock()
+
+			<-q.slots
+			return next.inst, true
+		}
+		q.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return instance{}, false
+		case <-q.ready:
+		}
+	}
+}
+
+// release forgets the virtual finish time of the tenant once it has no
+// queued instances so the tenants tracked are bounded by the queue size.
+// The queue lock MUST be held.
+func (q *pqueue) release(tenant string) {
+	q.tenants[tenant]--
+	if q.tenants[tenant] > 0 {
+		return
+	}
+
+	delete(q.tenants, tenant)
+	delete(q.finish, tenant)
+}
+
+// len returns the number of queued instances
+func (q *pqueue) len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.items.Len()
+}
@@ -0,0 +1,98 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// orderrelease blocks orderatom until it is closed
+var orderrelease chan struct{}
+
+// ordered receives the payloads in the order orderatom processes them
+var ordered chan string
+
+type orderatom struct{}
+
+func (*orderatom) Process(
+	ctx context.Context,
+	conductor Conductor,
+	electron *Electron,
+) ([]byte, error) {
+	<-orderrelease
+	ordered <- string(electron.Payload)
+	return nil, nil
+}
+
+func TestAtomizer_ConductorPriority(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	orderrelease = make(chan struct{})
+	ordered = make(chan string, 10)
+	alt := &altrecorder{newRecorder()}
+
+	rec, a := recHarness(
+		ctx,
+		t,
+		WithConductorPriority(ID(alt), 10),
+		alt,
+		&orderatom{},
+	)
+
+	atomID := ID(orderatom{})
+	eventually(t, time.Second, func() bool {
+		return a.route(newElectron(atomID, nil)) != nil
+	})
+
+	send := func(c Conductor, name string) {
+		e := newElectron(atomID, []byte(name))
+		if _, err := c.Send(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Occupy the atom, distribute and the dispatch loop so the
+	// following electrons contend in the queue
+	for _, name := range []string{"blocker", "low1", "low2"} {
+		send(rec, name)
+		eventually(t, time.Second, func() bool {
+			return a.queue.len() == 0
+		})
+
+		// Allow the electron to move past the queue
+		time.Sleep(time.Millisecond * 20)
+	}
+
+	send(rec, "low3")
+	send(rec, "low4")
+	send(alt, "high1")
+	send(alt, "high2")
+
+	eventually(t, time.Second, func() bool {
+		return a.queue.len() == 4
+	})
+
+	close(orderrelease)
+
+	expected := []string{
+		"blocker", "low1", "low2", "high1", "high2", "low3", "low4",
+	}
+
+	for _, name := range expected {
+		select {
+		case <-ctx.Done():
+			t.Fatalf("expected %s to be processed", name)
+		case got := <-ordered:
+			if got != name {
+				t.Fatalf("expected %s, got %s", name, got)
+			}
+		}
+	}
+}
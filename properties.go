@@ -24,18 +24,82 @@ type Properties struct {
 	End        time.Time
 	Error      error
 	Result     []byte
+
+	// CorrelationID is the correlation ID of the electron
+	// (see Electron.CorrelationID)
+	CorrelationID string
+
+	// Lineage is the chain of electrons which led to the electron,
+	// oldest first and ending with the electron itself, when the
+	// electron was submitted as a child of another (see Submit)
+	Lineage []LineageEntry
+
+	// NodeID is the node of the atomizer which processed
+	// the electron (see WithNodeID)
+	NodeID string
+
+	// Status is the outcome of processing the electron
+	Status Status
+
+	// Empty indicates the atom succeeded without producing a result,
+	// which is legitimate for side-effect only atoms but may also
+	// indicate an atom silently producing nothing
+	Empty bool
+
+	// Timeout is the timeout the electron was processed with, if any
+	Timeout time.Duration
+
+	// Attempts is the number of times the atom processed the electron,
+	// more than once when failures were retried (see RetryPolicy)
+	Attempts int
+
+	// Partials are the partial results emitted by the atom while
+	// processing the electron (see EmitPartial)
+	Partials [][]byte
+
+	// Allocated is the approximate number of bytes allocated during
+	// the execution of the atom when memory profiling is enabled
+	Allocated uint64
+
+	// Signer is the identity of the node which signed the result
+	// and Signature is the signature of the result when signed
+	Signer    string
+	Signature []byte
+
+	// ResultHash is the stable hash of the result in the form
+	// `algorithm:hex` when result hashing is enabled
+	// (see WithResultHashing and ResultsMatch)
+	ResultHash string
+
+	// codec is the name of the codec the result
+	// is compressed with when marshalled
+	codec string
 }
 
 // UnmarshalJSON reads in a []byte of JSON data and maps it to the Properties
 // struct properly for use throughout Atomizer
 func (p *Properties) UnmarshalJSON(data []byte) error {
 	jsonP := struct {
-		ElectronID string          `json:"electronId"`
-		AtomID     string          `json:"atomId"`
-		Start      time.Time       `json:"starttime"`
-		End        time.Time       `json:"endtime"`
-		Error      []byte          `json:"error,omitempty"`
-		Result     json.RawMessage `json:"result"`
+		ElectronID    string          `json:"electronId"`
+		CorrelationID string          `json:"correlationId,omitempty"`
+		Lineage       []LineageEntry  `json:"lineage,omitempty"`
+		AtomID        string          `json:"atomId"`
+		Start         time.Time       `json:"starttime"`
+		End           time.Time       `json:"endtime"`
+		Error         []byte          `json:"error,omitempty"`
+		Result        json.RawMessage `json:"result"`
+		NodeID        string          `json:"nodeId,omitempty"`
+		Status        Status          `json:"status,omitempty"`
+		Empty         bool            `json:"empty,omitempty"`
+		Timeout       time.Duration   `json:"timeout,omitempty"`
+		Attempts      int             `json:"attempts,omitempty"`
+		Partials      [][]byte        `json:"partials,omitempty"`
+		Allocated     uint64          `json:"allocated,omitempty"`
+		Signer        string          `json:"signer,omitempty"`
+		Signature     []byte          `json:"signature,omitempty"`
+		ResultHash    string          `json:"resultHash,omitempty"`
+		Codec         string          `json:"codec,omitempty"`
+		Compressed    []byte          `json:"compressed,omitempty"`
 	}{}
 
 	err := json.Unmarshal(data, &jsonP)
@@ -55,9 +119,33 @@ func (p *Properties) UnmarshalJSON(data []byte) error {
 
 	p.ElectronID = jsonP.ElectronID
 	p.AtomID = jsonP.AtomID
+	p.CorrelationID = jsonP.CorrelationID
+	p.Lineage = jsonP.Lineage
 	p.Start = jsonP.Start
 	p.End = jsonP.End
 	p.Result = []byte(jsonP.Result)
+	p.NodeID = jsonP.NodeID
+	p.Status = jsonP.Status
+	p.Empty = jsonP.Empty
+	p.Timeout = jsonP.Timeout
+	p.Attempts = jsonP.Attempts
+	p.Partials = jsonP.Partials
+	p.Allocated = jsonP.Allocated
+	p.Signer = jsonP.Signer
+	p.Signature = jsonP.Signature
+	p.ResultHash = jsonP.ResultHash
+
+	if jsonP.Codec != "" {
+		c, err := codec(jsonP.Codec)
+		if err != nil {
+			return err
+		}
+
+		p.Result, err = c.Decompress(jsonP.Compressed)
+		if err != nil {
+			return simple("error decompressing result", err)
+		}
+	}
 
 	return nil
 }
@@ -78,23 +166,89 @@ func (p *Properties) MarshalJSON() ([]byte, error) {
 		}
 	}
 
+	result := json.RawMessage(p.Result)
+
+	var compressed []byte
+	if p.codec != "" {
+		c, err := codec(p.codec)
+		if err != nil {
+			return nil, err
+		}
+
+		compressed, err = c.Compress(p.Result)
+		if err != nil {
+			return nil, simple("error compressing result", err)
+		}
+
+		result = nil
+	}
+
 	return json.Marshal(&struct {
-		ElectronID string          `json:"electronId"`
-		AtomID     string          `json:"atomId"`
-		Start      time.Time       `json:"starttime"`
-		End        time.Time       `json:"endtime"`
-		Error      []byte          `json:"error,omitempty"`
-		Result     json.RawMessage `json:"result"`
+		ElectronID    string          `json:"electronId"`
+		CorrelationID string          `json:"correlationId,omitempty"`
+		Lineage       []LineageEntry  `json:"lineage,omitempty"`
+		AtomID        string          `json:"atomId"`
+		Start         time.Time       `json:"starttime"`
+		End           time.Time       `json:"endtime"`
+		Error         []byte          `json:"error,omitempty"`
+		Result        json.RawMessage `json:"result"`
+		NodeID        string          `json:"nodeId,omitempty"`
+		Status        Status          `json:"status,omitempty"`
+		Empty         bool            `json:"empty,omitempty"`
+		Timeout       time.Duration   `json:"timeout,omitempty"`
+		Attempts      int             `json:"attempts,omitempty"`
+		Partials      [][]byte        `json:"partials,omitempty"`
+		Allocated     uint64          `json:"allocated,omitempty"`
+		Signer        string          `json:"signer,omitempty"`
+		Signature     []byte          `json:"signature,omitempty"`
+		ResultHash    string          `json:"resultHash,omitempty"`
+		Codec         string          `json:"codec,omitempty"`
+		Compressed    []byte          `json:"compressed,omitempty"`
 	}{
-		ElectronID: p.ElectronID,
-		AtomID:     p.AtomID,
-		Start:      p.Start,
-		End:        p.End,
-		Error:      eString,
-		Result:     json.RawMessage(p.Result),
+		ElectronID:    p.ElectronID,
+		AtomID:        p.AtomID,
+		CorrelationID: p.CorrelationID,
+		Lineage:       p.Lineage,
+		Start:         p.Start,
+		End:           p.End,
+		Error:         eString,
+		Result:        result,
+		NodeID:        p.NodeID,
+		Status:        p.Status,
+		Empty:         p.Empty,
+		Timeout:       p.Timeout,
+		Attempts:      p.Attempts,
+		Partials:      p.Partials,
+		Allocated:     p.Allocated,
+		Signer:        p.Signer,
+		Signature:     p.Signature,
+		ResultHash:    p.ResultHash,
+		Codec:         p.codec,
+		Compressed:    compressed,
 	})
 }
 
+// Elapsed returns the processing time of the electron
+func (p *Properties) Elapsed() time.Duration {
+	return p.End.Sub(p.Start)
+}
+
+// CorrelateResult returns the ID of the request the properties complete
+// so that senders of electrons through asynchronous conductors can route
+// the properties back to the waiting request. The CorrelationID is used
+// when set, otherwise the ElectronID.
+func CorrelateResult(p *Properties) (requestID string) {
+	if p == nil {
+		return ""
+	}
+
+	if p.CorrelationID != "" {
+		return p.CorrelationID
+	}
+
+	return p.ElectronID
+}
+
 // Equal determines if two properties structs are equal to eachother
 // TODO: Should this use reflect.DeepEqual?
 func (p *Properties) Equal(p2 *Properties) bool {
@@ -186,3 +186,22 @@ func TestProperties_UnmarshalJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestCorrelateResult(t *testing.T) {
+	tests := map[string]struct {
+		p        *Properties
+		expected string
+	}{
+		"nil":         {nil, ""},
+		"electron":    {&Properties{ElectronID: "e"}, "e"},
+		"correlation": {&Properties{ElectronID: "e", CorrelationID: "c"}, "c"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := CorrelateResult(test.p); got != test.expected {
+				t.Fatalf("expected %s, got %s", test.expected, got)
+			}
+		})
+	}
+}
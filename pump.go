@@ -0,0 +1,135 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import (
+	"context"
+	"sort"
+	"strings"
+)
+
+// WithManualPump disables the receive loops of the conductors so that
+// electrons are only pulled from the conductors by calling Pump. This is
+// intended for deterministic testing of atoms and routing.
+func WithManualPump() Option {
+	return func(a *atomizer) error {
+		a.pumps = make(map[string]<-chan *Electron)
+		return nil
+	}
+}
+
+// Pump synchronously pulls the electrons currently available from the
+// conductors through validation, routing, execution and completion in the
+// calling goroutine and returns the number of electrons pulled once no
+// more electrons are available. Pump requires WithManualPump.
+func (a *atomizer) Pump(ctx context.Context) (processed int, err error) {
+	if a.pumps == nil {
+		return 0, simple("pump requires manual pump mode", nil)
+	}
+
+	for {
+		pulled := 0
+		for _, c := range a.pumping() {
+			select {
+			case <-ctx.Done():
+				return processed, simple("context closed", ctx.Err())
+			case <-a.ctx.Done():
+				return processed, simple("atomizer closed", nil)
+			case e, ok := <-c.receiver:
+				if !ok {
+					a.conductorsMu.Lock()
+					delete(a.pumps, ID(c.conductor))
+					a.conductorsMu.Unlock()
+					continue
+				}
+
+				pulled++
+				a.pump(c.conductor, e)
+			default:
+			}
+		}
+
+		if pulled == 0 {
+			return processed, nil
+		}
+
+		processed += pulled
+	}
+}
+
+// pumped is a conductor and its receiver for a manual pump
+type pumped struct {
+	conductor Conductor
+	receiver  <-chan *Electron
+}
+
+// pumping returns the conductors for a manual pump ordered
+// by conductor ID
+func (a *atomizer) pumping() []pumped {
+	a.conductorsMu.RLock()
+	defer a.conductorsMu.RUnlock()
+
+	ids := make([]string, 0, len(a.pumps))
+	for id := range a.pumps {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	conductors := make([]pumped, 0, len(ids))
+	for _, id := range ids {
+		c, ok := a.conductors[id]
+		if !ok {
+			continue
+		}
+
+		conductors = append(conductors, pumped{c, a.pumps[id]})
+	}
+
+	return conductors
+}
+
+// pump processes the electron in the calling goroutine
+func (a *atomizer) pump(conductor Conductor, e *Electron) {
+	inst, ok := a.admit(a.ctx, conductor, e)
+	if !ok {
+		return
+	}
+
+	a.inflight.add(e, conductor)
+	a.persist(&inst, InstanceQueued)
+	if a.dispatch(inst) == nil {
+		return
+	}
+
+	atom := a.registered(e)
+	if atom == nil {
+		a.inflight.done(e)
+		return
+	}
+
+	a.process(atom, inst)
+}
+
+// registered returns the registered atom which processes the electron
+func (a *atomizer) registered(e *Electron) Atom {
+	atomID := e.AtomID
+	if e.ForceAtomKey != "" {
+		atomID = e.ForceAtomKey
+		if i := strings.LastIndex(atomID, "#"); i >= 0 {
+			atomID = atomID[:i]
+		}
+	}
+
+	a.atomsMu.RLock()
+	defer a.atomsMu.RUnlock()
+
+	reps, ok := a.atoms[atomID]
+	if !ok {
+		return nil
+	}
+
+	return reps.atom
+}
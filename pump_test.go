@@ -0,0 +1,90 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestAtomizer_Pump(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	rec := newRecorder()
+	rec.input = make(chan *Electron, 10)
+
+	mizer, err := Atomize(ctx, WithManualPump(), rec, &noopatom{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = mizer.Exec(); err != nil {
+		t.Fatal(err)
+	}
+
+	processed, err := mizer.Pump(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if processed != 0 {
+		t.Fatalf("expected 0 electrons pumped, got %v", processed)
+	}
+
+	sent := make(map[string]bool)
+	for i := 0; i < 3; i++ {
+		e := newElectron(ID(noopatom{}), nil)
+		sent[e.ID] = true
+		rec.input <- e
+	}
+
+	processed, err = mizer.Pump(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if processed != len(sent) {
+		t.Fatalf("expected %v electrons pumped, got %v", len(sent), processed)
+	}
+
+	// Every pumped electron is completed before Pump returns
+	if len(rec.completions) != len(sent) {
+		t.Fatalf(
+			"expected %v completions, got %v",
+			len(sent),
+			len(rec.completions),
+		)
+	}
+
+	for len(rec.completions) > 0 {
+		p := <-rec.completions
+		if !sent[p.ElectronID] || p.Error != nil {
+			t.Fatalf("unexpected completion %+v", p)
+		}
+	}
+
+	processed, err = mizer.Pump(ctx)
+	if err != nil || processed != 0 {
+		t.Fatalf("expected idle pump, got %v, %v", processed, err)
+	}
+}
+
+func TestAtomizer_Pump_NotManual(t *testing.T) {
+	ctx, cancel := _ctx(context.TODO())
+	defer cancel()
+
+	mizer, err := Atomize(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = mizer.Pump(ctx); err == nil {
+		t.Fatal("expected error pumping without manual pump mode")
+	}
+}
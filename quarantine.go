@@ -0,0 +1,118 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrAtomQuarantined is set as the error of electrons for an atom which
+// was quarantined after exceeding its panic budget
+var ErrAtomQuarantined = errors.New("atom quarantined")
+
+// panicBudget tracks the recent panics of each atom
+type panicBudget struct {
+	mu     sync.Mutex
+	max    int
+	window time.Duration
+	panics map[string][]time.Time
+}
+
+// WithPanicBudget quarantines an atom which panics the number of times
+// within the window. Electrons for a quarantined atom are rejected with
+// ErrAtomQuarantined and dead lettered, when a dead letter store is
+// configured, until the atom is re-enabled with an EnableAtom control
+// command.
+func WithPanicBudget(panics int, window time.Duration) Option {
+	return func(a *atomizer) error {
+		if panics < 1 {
+			return simple("panic budget must be at least 1", nil)
+		}
+
+		if window <= 0 {
+			return simple("panic budget window must be positive", nil)
+		}
+
+		a.budget = &panicBudget{
+			max:    panics,
+			window: window,
+			panics: make(map[string][]time.Time),
+		}
+
+		return nil
+	}
+}
+
+// spend records a panic of the atom and indicates if the
+// atom exceeded its budget
+func (b *panicBudget) spend(atomID string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+
+	// Drop the panics which are outside of the window
+	recent := b.panics[atomID][:0]
+	for _, t := range b.panics[atomID] {
+		if now.Sub(t) < b.window {
+			recent = append(recent, t)
+		}
+	}
+
+	recent = append(recent, now)
+	if len(recent) < b.max {
+		b.panics[atomID] = recent
+		return false
+	}
+
+	// The budget starts over once the atom is re-enabled
+	delete(b.panics, atomID)
+
+	return true
+}
+
+// panicked records a panic of the atom against its panic budget and
+// quarantines the atom once the budget is exceeded
+func (a *atomizer) panicked(atomID string) {
+	if a.budget == nil || !a.budget.spend(atomID) {
+		return
+	}
+
+	err := a.command(&Command{Action: DisableAtom, AtomID: atomID})
+	if err != nil {
+		a.err(func() error {
+			return err
+		})
+		return
+	}
+
+	a.atomsMu.Lock()
+	if a.quarantined == nil {
+		a.quarantined = make(map[string]struct{})
+	}
+	a.quarantined[atomID] = struct{}{}
+	a.atomsMu.Unlock()
+
+	a.event(func() interface{} {
+		return &Event{
+			Message: "atom quarantined after " +
+				strconv.Itoa(a.budget.max) + " panics",
+			AtomID: atomID,
+		}
+	})
+}
+
+// isQuarantined indicates if the atom was quarantined
+func (a *atomizer) isQuarantined(atomID string) bool {
+	a.atomsMu.RLock()
+	defer a.atomsMu.RUnlock()
+
+	_, ok := a.quarantined[atomID]
+	return ok
+}
@@ -0,0 +1,78 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAtomizer_PanicBudget(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	store := &MemoryDeadLetterStore{}
+	rec, a := recHarness(
+		ctx,
+		t,
+		WithPanicBudget(3, time.Minute),
+		WithDeadLetter(store),
+		&panicatom{},
+	)
+
+	for i := 0; i < 3; i++ {
+		if a.isQuarantined(ID(panicatom{})) {
+			t.Fatalf("atom quarantined after %v panics", i)
+		}
+
+		_, err := rec.Send(ctx, newElectron(ID(panicatom{}), nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if p := rec.next(ctx, t); p.Error == nil {
+			t.Fatal("expected panic error")
+		}
+	}
+
+	if !a.isQuarantined(ID(panicatom{})) {
+		t.Fatal("expected atom to be quarantined")
+	}
+
+	e := newElectron(ID(panicatom{}), nil)
+	if _, err := rec.Send(ctx, e); err != nil {
+		t.Fatal(err)
+	}
+
+	if p := rec.next(ctx, t); !errors.Is(p.Error, ErrAtomQuarantined) {
+		t.Fatalf("expected quarantine error, got %v", p.Error)
+	}
+
+	dls, err := store.Take(ctx, DeadLetterFilter{
+		Error: func(err error) bool {
+			return errors.Is(err, ErrAtomQuarantined)
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(dls) != 1 || dls[0].Electron.ID != e.ID {
+		t.Fatalf("expected quarantined electron dead lettered, got %v", dls)
+	}
+
+	err = a.command(&Command{Action: EnableAtom, AtomID: ID(panicatom{})})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if a.isQuarantined(ID(panicatom{})) {
+		t.Fatal("expected atom to be released from quarantine")
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+// Redactor rewrites an event before it is emitted so that sensitive
+// identifiers can be hashed or masked. The redactor receives a copy of
+// the event, the electron the event refers to is never modified.
+type Redactor func(Event) Event
+
+// WithRedactor applies the redactor to every event emitted by the
+// atomizer, including the events published to sinks and the events of
+// the errors emitted on the errors channel
+func WithRedactor(redactor Redactor) Option {
+	return func(a *atomizer) error {
+		if redactor == nil {
+			return simple("invalid nil redactor", nil)
+		}
+
+		a.redactor = redactor
+
+		return nil
+	}
+}
+
+// redact returns a redacted copy of the event, events of other types
+// are returned unchanged
+func (a *atomizer) redact(e interface{}) interface{} {
+	if a.redactor == nil {
+		return e
+	}
+
+	event, ok := e.(*Event)
+	if !ok || event == nil {
+		return e
+	}
+
+	redacted := a.redactor(*event)
+
+	return &redacted
+}
+
+// redactErr returns a copy of the error with its event redacted, errors
+// of other types are returned unchanged
+func (a *atomizer) redactErr(err error) error {
+	if a.redactor == nil {
+		return err
+	}
+
+	e, ok := err.(*Error)
+	if !ok || e == nil || e.Event == nil {
+		return err
+	}
+
+	redacted := a.redactor(*e.Event)
+
+	return &Error{
+		Event:    &redacted,
+		Internal: e.Internal,
+	}
+}
@@ -0,0 +1,155 @@
+package engine
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+// senderatom returns the sender of the electron it received
+type senderatom struct{}
+
+func (*senderatom) Process(
+	ctx context.Context,
+	conductor Conductor,
+	electron *Electron,
+) ([]byte, error) {
+	return []byte(electron.SenderID), nil
+}
+
+func TestAtomizer_Redactor(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	rec, a := recHarness(
+		ctx,
+		t,
+		WithRedactor(func(e Event) Event {
+			if e.SenderID != "" {
+				e.SenderID = strings.Repeat("*", len(e.SenderID))
+			}
+
+			return e
+		}),
+		&senderatom{},
+	)
+	events := a.Events(1000)
+
+	eventually(t, time.Second, func() bool {
+		return a.route(newElectron(ID(senderatom{}), nil)) != nil
+	})
+
+	e := newElectron(ID(senderatom{}), nil)
+	if _, err := rec.Send(ctx, e); err != nil {
+		t.Fatal(err)
+	}
+
+	if p := rec.next(ctx, t); string(p.Result) != e.SenderID {
+		t.Fatalf("expected atom to receive sender %s, got %s", e.SenderID, p.Result)
+	}
+
+	masked := strings.Repeat("*", len(e.SenderID))
+	for {
+		var ev interface{}
+		select {
+		case <-ctx.Done():
+			t.Fatal("expected electron received event")
+		case ev = <-events:
+		}
+
+		event, ok := ev.(*Event)
+		if !ok || event.Message != "electron received" ||
+			event.ElectronID != e.ID {
+			continue
+		}
+
+		if event.SenderID != masked {
+			t.Fatalf("expected masked sender, got %s", event.SenderID)
+		}
+
+		break
+	}
+
+	if e.SenderID == masked {
+		t.Fatal("expected electron to retain the original sender")
+	}
+}
+
+func TestAtomizer_Redactor_Errors(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	rec, a := recHarness(
+		ctx,
+		t,
+		WithRedactor(func(e Event) Event {
+			if e.ElectronID != "" {
+				e.ElectronID = strings.Repeat("*", len(e.ElectronID))
+			}
+
+			return e
+		}),
+		&noopatom{},
+	)
+	errs := a.Errors(1000)
+
+	// The electron is rejected since the atom is not registered
+	e := newElectron("unregistered", nil)
+	if _, err := rec.Send(ctx, e); err != nil {
+		t.Fatal(err)
+	}
+
+	p := rec.next(ctx, t)
+	if p.ElectronID != e.ID || p.Error == nil {
+		t.Fatalf("expected %s to be rejected, got %+v", e.ID, p)
+	}
+
+	completed, ok := p.Error.(*Error)
+	if !ok || completed.Event == nil || completed.Event.ElectronID != e.ID {
+		t.Fatalf("expected completion to retain the electron id, got %v", p.Error)
+	}
+
+	masked := strings.Repeat("*", len(e.ID))
+	for {
+		var err error
+		select {
+		case <-ctx.Done():
+			t.Fatal("expected rejection error")
+		case err = <-errs:
+		}
+
+		rejection, isErr := err.(*Error)
+		if !isErr || rejection.Event == nil ||
+			rejection.Event.AtomID != "unregistered" {
+			continue
+		}
+
+		if rejection.Event.ElectronID != masked {
+			t.Fatalf(
+				"expected masked electron id, got %s",
+				rejection.Event.ElectronID,
+			)
+		}
+
+		break
+	}
+}
+
+func TestWithRedactor_Invalid(t *testing.T) {
+	if err := WithRedactor(nil)(&atomizer{}); err == nil {
+		t.Fatal("expected error")
+	}
+}
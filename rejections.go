@@ -0,0 +1,70 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+// RejectionStage is the stage of the pipeline where an electron
+// was rejected
+type RejectionStage string
+
+const (
+	// StageAdmission rejections happen as the electron is received
+	// from its conductor, such as failed validation or duplicates
+	StageAdmission RejectionStage = "admission"
+
+	// StageDistribution rejections happen as the electron is routed
+	// to its atom, such as unregistered or disabled atoms
+	StageDistribution RejectionStage = "distribution"
+
+	// StageExecution rejections happen before the electron is executed
+	// by its atom, such as oversized payloads or rate limits
+	StageExecution RejectionStage = "execution"
+)
+
+// RejectedElectron is an electron which was dropped or rejected
+// without being processed by its atom
+type RejectedElectron struct {
+	Electron Electron
+	Reason   error
+	Stage    RejectionStage
+}
+
+// Rejections creates a channel to receive the electrons rejected by the
+// atomizer and returns the channel for handling. Rejections are delivered
+// without blocking the atomizer so rejections which arrive while the
+// channel buffer is full are dropped.
+func (a *atomizer) Rejections(buffer int) <-chan RejectedElectron {
+	if buffer < 0 {
+		buffer = 0
+	}
+
+	a.rejectionsMu.Lock()
+	defer a.rejectionsMu.Unlock()
+
+	if a.rejections == nil {
+		a.rejections = make(chan RejectedElectron, buffer)
+	}
+
+	return a.rejections
+}
+
+// rejected delivers the rejected instance to the rejections channel
+func (a *atomizer) rejected(inst instance, stage RejectionStage, err error) {
+	a.rejectionsMu.RLock()
+	defer a.rejectionsMu.RUnlock()
+
+	if a.rejections == nil || inst.electron == nil {
+		return
+	}
+
+	select {
+	case a.rejections <- RejectedElectron{
+		Electron: *inst.electron,
+		Reason:   err,
+		Stage:    stage,
+	}:
+	default:
+	}
+}
@@ -0,0 +1,88 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAtomizer_Rejections(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	rec, a := recHarness(
+		ctx,
+		t,
+		WithElectronValidator(func(e Electron) error {
+			if len(e.Payload) == 0 {
+				return errEmptyPayload
+			}
+
+			return nil
+		}),
+		&noopatom{},
+	)
+	rejections := a.Rejections(10)
+
+	eventually(t, time.Second, func() bool {
+		return a.route(newElectron(ID(noopatom{}), nil)) != nil
+	})
+
+	tests := []struct {
+		name     string
+		electron *Electron
+		stage    RejectionStage
+		reason   error
+	}{
+		{
+			"invalid",
+			newElectron(ID(noopatom{}), nil),
+			StageAdmission,
+			errEmptyPayload,
+		},
+		{
+			"unregistered",
+			newElectron("unregistered", []byte(`{}`)),
+			StageDistribution,
+			nil,
+		},
+	}
+
+	for _, test := range tests {
+		if _, err := rec.Send(ctx, test.electron); err != nil {
+			t.Fatal(err)
+		}
+
+		var r RejectedElectron
+		select {
+		case <-ctx.Done():
+			t.Fatalf("%s: expected rejection", test.name)
+		case r = <-rejections:
+		}
+
+		if r.Electron.ID != test.electron.ID {
+			t.Fatalf(
+				"%s: expected %s, got %s",
+				test.name,
+				test.electron.ID,
+				r.Electron.ID,
+			)
+		}
+
+		if r.Stage != test.stage {
+			t.Fatalf("%s: expected %s, got %s", test.name, test.stage, r.Stage)
+		}
+
+		if r.Reason == nil ||
+			(test.reason != nil && !errors.Is(r.Reason, test.reason)) {
+			t.Fatalf("%s: unexpected reason %v", test.name, r.Reason)
+		}
+	}
+}
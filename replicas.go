@@ -0,0 +1,322 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// WithReplicas registers the atom with the supplied ID as n replicas, each
+// with its own processing loop. Electrons for the atom are routed to a
+// replica by consistent hashing of the electron's PartitionKey (or ID when
+// no partition key is set) so that a key is always processed by the same
+// replica, enabling per-key state locality within a replica. Replicas can
+// be added or removed at runtime (see Scale) which only remaps the keys
+// owned by the added or removed replicas.
+func WithReplicas(atomID string, n int) Option {
+	return func(a *atomizer) error {
+		if atomID == "" {
+			return simple("empty replica atom id", nil)
+		}
+
+		if n < 1 {
+			return simple("replicas must be at least 1", nil)
+		}
+
+		if a.replicaCounts == nil {
+			a.replicaCounts = make(map[string]int)
+		}
+
+		a.replicaCounts[atomID] = n
+		return nil
+	}
+}
+
+// replicas contains the electron channels for each of the processing
+// loops of a registered atom
+type replicas struct {
+	id   string
+	atom Atom
+
+	// mu guards the ring and the replica channels
+	// which change as replicas are added or removed
+	mu       sync.RWMutex
+	ring     *ring
+	channels map[string]chan<- instance
+	next     int
+
+	// names are the replica names in the order they were added
+	names []string
+
+	// sharder selects the replica for each electron
+	// rather than the ring when set (see WithSharder)
+	sharder Sharder
+
+	// tenants are the processing loops dedicated to each tenant
+	// when tenants are isolated (see WithTenantIsolation)
+	tenantsMu sync.Mutex
+	tenants   map[string]chan<- instance
+
+	// stopping is set once the replicas are stopped so the
+	// tenant loops are no longer started or reaped
+	stopping bool
+}
+
+func newReplicas(atom Atom) *replicas {
+	return &replicas{
+		id:       ID(atom),
+		atom:     atom,
+		ring:     newRing(),
+		channels: make(map[string]chan<- instance),
+	}
+}
+
+// add adds a replica channel to the ring and returns the replica name
+func (r *replicas) add(electrons chan<- instance) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	name := r.id + "#" + strconv.Itoa(r.next)
+	r.next++
+
+	r.channels[name] = electrons
+	r.names = append(r.names, name)
+	r.ring.add(name)
+
+	return name
+}
+
+// remove removes the most recently added replica from the ring, remapping
+// only the keys owned by the replica to the remaining replicas, and returns
+// its channel
+func (r *replicas) remove() (chan<- instance, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.names) == 0 {
+		return nil, false
+	}
+
+	name := r.names[len(r.names)-1]
+	electrons := r.channels[name]
+
+	delete(r.channels, name)
+	r.names = r.names[:len(r.names)-1]
+	r.ring.remove(name)
+
+	return electrons, true
+}
+
+// size returns the number of replicas
+func (r *replicas) size() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return len(r.channels)
+}
+
+// channel returns the channel of the named replica
+func (r *replicas) channel(name string) chan<- instance {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.channels[name]
+}
+
+// stop stops the processing loops of the replicas once they finish the
+// electrons already pushed to them. The replicas MUST no longer be routable
+// and distribute MUST have passed a barrier so that no other electrons are
+// pushed to the loops.
+func (r *replicas) stop(ctx context.Context) error {
+	r.tenantsMu.Lock()
+	r.stopping = true
+	r.tenantsMu.Unlock()
+
+	for _, electrons := range r.loops() {
+		if err := drain(ctx, electrons); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// drain stops the processing loop once it finishes the electrons already
+// pushed to it, blocking until the loop stops or the context closes
+func drain(ctx context.Context, electrons chan<- instance) error {
+	b := make(chan struct{})
+
+	select {
+	case <-ctx.Done():
+		return simple("context closed", nil)
+	case electrons <- instance{barrier: b}:
+	}
+
+	select {
+	case <-ctx.Done():
+		return simple("context closed", nil)
+	case <-b:
+		return nil
+	}
+}
+
+// loops returns the channels of every processing loop of the replicas
+func (r *replicas) loops() []chan<- instance {
+	r.tenantsMu.Lock()
+	defer r.tenantsMu.Unlock()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	loops := make([]chan<- instance, 0, len(r.channels)+len(r.tenants))
+	for _, electrons := range r.channels {
+		loops = append(loops, electrons)
+	}
+
+	for _, electrons := range r.tenants {
+		loops = append(loops, electrons)
+	}
+
+	return loops
+}
+
+// route returns the replica channel which owns the electron
+func (r *replicas) route(e *Electron) chan<- instance {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.sharder != nil {
+		return r.shard(e)
+	}
+
+	key := e.PartitionKey
+	if key == "" {
+		key = e.ID
+	}
+
+	name, ok := r.ring.get(key)
+	if !ok {
+		return nil
+	}
+
+	return r.channels[name]
+}
+
+// route returns the channel of the atom replica which should process
+// the electron or nil if there is no registered atom for the electron
+func (a *atomizer) route(e *Electron) chan<- instance {
+	if routes, ok := a.routing(); ok && e.ForceAtomKey == "" {
+		reps, found := routes[e.AtomID]
+		if !found {
+			return nil
+		}
+
+		return a.replica(reps, e)
+	}
+
+	a.atomsMu.RLock()
+	if e.ForceAtomKey != "" {
+		defer a.atomsMu.RUnlock()
+		return a.forced(e.ForceAtomKey)
+	}
+
+	reps, ok := a.atoms[e.AtomID]
+	a.atomsMu.RUnlock()
+
+	if !ok {
+		return nil
+	}
+
+	return a.replica(reps, e)
+}
+
+// forced returns the channel for a forced atom key which is either an
+// atom ID or the name of a specific replica of an atom (i.e. atomID#1).
+// The atoms lock MUST be held.
+func (a *atomizer) forced(key string) chan<- instance {
+	atomID := key
+	if i := strings.LastIndex(key, "#"); i >= 0 {
+		atomID = key[:i]
+	}
+
+	reps, ok := a.atoms[atomID]
+	if !ok {
+		return nil
+	}
+
+	if atomID == key {
+		// Route to the first replica since a specific
+		// atom was requested rather than a replica
+		return reps.channel(key + "#0")
+	}
+
+	return reps.channel(key)
+}
+
+// Scale changes the number of replicas of the registered atom to n at
+// runtime. Added replicas only take over the keys they own on the ring and
+// the keys owned by removed replicas are remapped to the remaining replicas,
+// every other key stays with its replica. The processing loops of removed
+// replicas finish the electrons already pushed to them before Scale returns.
+func (a *atomizer) Scale(atomID string, n int) error {
+	if n < 1 {
+		return simple("replicas must be at least 1", nil)
+	}
+
+	var removed []chan<- instance
+
+	a.atomsMu.Lock()
+	reps, ok := a.atoms[atomID]
+	if ok {
+		for i := reps.size(); i < n; i++ {
+			reps.add(a.split(reps.atom))
+		}
+
+		for i := reps.size(); i > n; i-- {
+			electrons, found := reps.remove()
+			if !found {
+				break
+			}
+
+			removed = append(removed, electrons)
+		}
+
+		a.rebuild()
+	}
+	a.atomsMu.Unlock()
+
+	if !ok {
+		return simple("scale of unregistered atom "+atomID, nil)
+	}
+
+	if len(removed) > 0 {
+		// Ensure distribute is not pushing to the removed
+		// replicas before their processing loops are stopped
+		if err := a.barrier(a.ctx); err != nil {
+			return err
+		}
+
+		for _, electrons := range removed {
+			if err := drain(a.ctx, electrons); err != nil {
+				return err
+			}
+
+			close(electrons)
+		}
+	}
+
+	a.event(func() interface{} {
+		return &Event{
+			Message: "atom scaled to " + strconv.Itoa(n) + " replicas",
+			AtomID:  atomID,
+		}
+	})
+
+	return nil
+}
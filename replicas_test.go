@@ -0,0 +1,259 @@
+package engine
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestReplicas_route(t *testing.T) {
+	reps := newReplicas(&noopatom{})
+
+	channels := make(map[chan<- instance]int)
+	for i := 0; i < 3; i++ {
+		c := make(chan instance)
+		reps.add(c)
+		channels[c] = i
+	}
+
+	seen := make(map[int]bool)
+	for i := 0; i < 100; i++ {
+		key := strconv.Itoa(i)
+		e := &Electron{ID: "id", PartitionKey: key}
+
+		first := reps.route(e)
+		for j := 0; j < 3; j++ {
+			if reps.route(&Electron{ID: "other", PartitionKey: key}) != first {
+				t.Fatalf("partition key %s routed to multiple replicas", key)
+			}
+		}
+
+		seen[channels[first]] = true
+	}
+
+	if len(seen) != 3 {
+		t.Fatalf("expected keys routed to all 3 replicas, got %v", len(seen))
+	}
+}
+
+func TestAtomizer_Replicas(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	conductor, _, a, err := optHarness(
+		ctx,
+		-1,
+		[]Option{WithReplicas(ID(noopatom{}), 3)},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 10; i++ {
+		e := newElectron(ID(noopatom{}), nil)
+		e.PartitionKey = strconv.Itoa(i % 2)
+
+		p := sendAndWait(ctx, t, conductor, e)
+		if p.Error != nil {
+			t.Fatal(p.Error)
+		}
+	}
+
+	a.atomsMu.RLock()
+	defer a.atomsMu.RUnlock()
+
+	if len(a.atoms[ID(noopatom{})].channels) != 3 {
+		t.Fatal("expected 3 replicas")
+	}
+}
+
+func TestWithReplicas_Invalid(t *testing.T) {
+	tests := map[string]Option{
+		"empty id":      WithReplicas("", 1),
+		"zero replicas": WithReplicas("test", 0),
+	}
+
+	for name, opt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Atomize(context.Background(), opt); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
+
+func TestAtomizer_ForceAtomKey(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	rec, _ := recHarness(
+		ctx,
+		t,
+		WithReplicas(ID(noopatom{}), 2),
+		&noopatom{},
+		&failatom{},
+	)
+
+	tests := []struct {
+		name string
+		key  string
+		atom string
+		err  bool
+	}{
+		{"atom key", ID(noopatom{}), ID(noopatom{}), false},
+		{"replica key", ID(noopatom{}) + "#1", ID(noopatom{}), false},
+		{"unregistered key", "nopey.nope", "", true},
+		{"unregistered replica", ID(noopatom{}) + "#5", "", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// The electron is addressed to the failing atom so it
+			// only succeeds if the forced key overrides the routing
+			e := newElectron(ID(failatom{}), nil)
+			e.ForceAtomKey = test.key
+
+			if _, err := rec.Send(ctx, e); err != nil {
+				t.Fatal(err)
+			}
+
+			p := rec.next(ctx, t)
+			if p.ElectronID != e.ID {
+				t.Fatalf("unexpected electron %s", p.ElectronID)
+			}
+
+			if test.err {
+				if p.Error == nil {
+					t.Fatal("expected error")
+				}
+
+				return
+			}
+
+			if p.Error != nil {
+				t.Fatal(p.Error)
+			}
+
+			if p.AtomID != test.atom {
+				t.Fatalf("expected atom %s, got %s", test.atom, p.AtomID)
+			}
+		})
+	}
+}
+
+func TestAtomizer_Scale(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	rec, a := recHarness(
+		ctx,
+		t,
+		WithReplicas(ID(noopatom{}), 3),
+		&noopatom{},
+	)
+
+	owners := func() map[string]chan<- instance {
+		owners := make(map[string]chan<- instance)
+		for i := 0; i < 500; i++ {
+			key := strconv.Itoa(i)
+			owners[key] = a.route(&Electron{
+				ID:           "id",
+				AtomID:       ID(noopatom{}),
+				PartitionKey: key,
+			})
+		}
+
+		return owners
+	}
+
+	process := func() {
+		for i := 0; i < 10; i++ {
+			e := newElectron(ID(noopatom{}), nil)
+			e.PartitionKey = strconv.Itoa(i)
+
+			if _, err := rec.Send(ctx, e); err != nil {
+				t.Fatal(err)
+			}
+
+			if p := rec.next(ctx, t); p.Error != nil {
+				t.Fatal(p.Error)
+			}
+		}
+	}
+
+	before := owners()
+
+	if err := a.Scale(ID(noopatom{}), 4); err != nil {
+		t.Fatal(err)
+	}
+
+	a.atomsMu.RLock()
+	added := a.atoms[ID(noopatom{})].channel(ID(noopatom{}) + "#3")
+	a.atomsMu.RUnlock()
+
+	if added == nil {
+		t.Fatal("expected an added replica")
+	}
+
+	after := owners()
+
+	var moved int
+	for key, owner := range after {
+		if owner == before[key] {
+			continue
+		}
+
+		// Only the keys owned by the added replica are remapped
+		if owner != added {
+			t.Fatalf("key %s remapped between existing replicas", key)
+		}
+
+		moved++
+	}
+
+	if moved == 0 || moved == len(after) {
+		t.Fatalf("expected a share of the keys remapped, got %v", moved)
+	}
+
+	process()
+
+	if err := a.Scale(ID(noopatom{}), 3); err != nil {
+		t.Fatal(err)
+	}
+
+	// Removing the added replica restores the original mapping
+	for key, owner := range owners() {
+		if owner != before[key] {
+			t.Fatalf("key %s not restored to its replica", key)
+		}
+	}
+
+	process()
+
+	if err := a.Scale("nopey.nope", 2); err == nil {
+		t.Fatal("expected error scaling an unregistered atom")
+	}
+
+	if err := a.Scale(ID(noopatom{}), 0); err == nil {
+		t.Fatal("expected error scaling to zero replicas")
+	}
+}
@@ -0,0 +1,109 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestAtomizer_NoReply(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	executed := make(chan string, 1)
+	store := NewMemoryResultStore(ctx, time.Minute, time.Minute, 10)
+
+	rec, a := recHarness(
+		ctx,
+		t,
+		WithResultStore(store),
+		WithExecHooks(nil, func(e Electron, _ Properties) {
+			executed <- e.ID
+		}),
+		&noopatom{},
+	)
+
+	eventually(t, time.Second, func() bool {
+		return a.route(newElectron(ID(noopatom{}), nil)) != nil
+	})
+
+	reply := false
+	e := newElectron(ID(noopatom{}), nil)
+	e.ReplyExpected = &reply
+
+	if _, err := rec.Send(ctx, e); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-ctx.Done():
+		t.Fatal("expected atom to execute")
+	case id := <-executed:
+		if id != e.ID {
+			t.Fatalf("expected %s, got %s", e.ID, id)
+		}
+	}
+
+	select {
+	case p := <-rec.completions:
+		t.Fatalf("unexpected completion %+v", p)
+	case <-time.After(time.Millisecond * 50):
+	}
+
+	if store.Len() != 0 {
+		t.Fatal("expected no stored result")
+	}
+
+	// Electrons expect a reply by default
+	e = newElectron(ID(noopatom{}), nil)
+	if _, err := rec.Send(ctx, e); err != nil {
+		t.Fatal(err)
+	}
+
+	if p := rec.next(ctx, t); p.ElectronID != e.ID {
+		t.Fatalf("expected %s, got %s", e.ID, p.ElectronID)
+	}
+}
+
+func TestElectron_ReplyExpected_JSON(t *testing.T) {
+	reply := false
+	tests := map[string]struct {
+		reply    *bool
+		expected bool
+	}{
+		"default":  {nil, true},
+		"no reply": {&reply, false},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			e := newElectron("test", nil)
+			e.ReplyExpected = test.reply
+
+			data, err := json.Marshal(e)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			out := &Electron{}
+			if err = json.Unmarshal(data, out); err != nil {
+				t.Fatal(err)
+			}
+
+			if out.ExpectsReply() != test.expected {
+				t.Fatalf(
+					"expected reply %v, got %v",
+					test.expected,
+					out.ExpectsReply(),
+				)
+			}
+		})
+	}
+}
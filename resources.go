@@ -0,0 +1,135 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import (
+	"runtime"
+	"strconv"
+	"time"
+)
+
+// ResourceGate reports if the node has the resources to admit more
+// electrons. Check returns an error describing the resource pressure when
+// intake should be paused and nil when resources are available.
+type ResourceGate interface {
+	Check() error
+}
+
+// MemoryGate is a ResourceGate which pauses intake while the allocated
+// heap of the process exceeds MaxHeap bytes
+type MemoryGate struct {
+	MaxHeap uint64
+}
+
+// Check reads the runtime memory statistics of the process
+func (g MemoryGate) Check() error {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+
+	if stats.HeapAlloc > g.MaxHeap {
+		return simple(
+			"heap of "+strconv.FormatUint(stats.HeapAlloc, 10)+
+				" bytes exceeds "+strconv.FormatUint(g.MaxHeap, 10),
+			nil,
+		)
+	}
+
+	return nil
+}
+
+// resources pauses distribution while the resource gate reports
+// resource pressure
+type resources struct {
+	gate     ResourceGate
+	interval time.Duration
+	intake   *gate
+}
+
+// WithResourceGate checks the resource gate on the interval and pauses
+// the distribution of electrons while the gate reports resource pressure,
+// resuming once it recovers. While paused, conductors are not read, so the
+// pause pushes back on the conductors. See MemoryGate and DiskGate.
+func WithResourceGate(g ResourceGate, interval time.Duration) Option {
+	return func(a *atomizer) error {
+		if g == nil {
+			return simple("nil resource gate", nil)
+		}
+
+		if interval <= 0 {
+			return simple("resource check interval must be positive", nil)
+		}
+
+		a.resources = &resources{
+			gate:     g,
+			interval: interval,
+			intake:   newGate(true),
+		}
+
+		return nil
+	}
+}
+
+// monitor checks the resource gate on the interval pausing and
+// resuming the intake of electrons
+func (a *atomizer) monitor() {
+	ticker := time.NewTicker(a.resources.interval)
+	defer ticker.Stop()
+
+	for {
+		a.gauge()
+
+		select {
+		case <-a.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// gauge checks the resource gate once, recovering any panic as
+// resource pressure
+func (a *atomizer) gauge() {
+	err := func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = ptoe(r)
+			}
+		}()
+
+		return a.resources.gate.Check()
+	}()
+
+	if !a.resources.intake.set(err == nil) {
+		return
+	}
+
+	if err != nil {
+		a.event(func() interface{} {
+			return &Event{Message: "intake paused, " + err.Error()}
+		})
+
+		return
+	}
+
+	a.event(func() interface{} {
+		return makeEvent("intake resumed")
+	})
+}
+
+// admitting blocks while intake is paused for resource pressure and
+// returns false if the atomizer closed
+func (a *atomizer) admitting() bool {
+	if a.resources == nil {
+		return true
+	}
+
+	select {
+	case <-a.ctx.Done():
+		return false
+	case <-a.resources.intake.open():
+		return true
+	}
+}
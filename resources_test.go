@@ -0,0 +1,93 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// togglegate reports resource pressure while low is set
+type togglegate struct {
+	low int32
+}
+
+func (g *togglegate) Check() error {
+	if atomic.LoadInt32(&g.low) == 1 {
+		return errors.New("low resources")
+	}
+
+	return nil
+}
+
+func TestAtomizer_ResourceGate(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	g := &togglegate{low: 1}
+	rec, a := recHarness(
+		ctx,
+		t,
+		WithResourceGate(g, time.Millisecond*5),
+		&noopatom{},
+	)
+
+	eventually(t, time.Second, func() bool {
+		return a.route(newElectron(ID(noopatom{}), nil)) != nil
+	})
+
+	eventually(t, time.Second, func() bool {
+		select {
+		case <-a.resources.intake.closed():
+			return true
+		default:
+			return false
+		}
+	})
+
+	e := newElectron(ID(noopatom{}), nil)
+	if _, err := rec.Send(ctx, e); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case p := <-rec.completions:
+		t.Fatalf("unexpected completion while paused %s", p.ElectronID)
+	case <-time.After(time.Millisecond * 50):
+	}
+
+	atomic.StoreInt32(&g.low, 0)
+
+	if p := rec.next(ctx, t); p.ElectronID != e.ID {
+		t.Fatalf("expected %s, got %s", e.ID, p.ElectronID)
+	}
+}
+
+func TestResourceGates(t *testing.T) {
+	tests := map[string]struct {
+		gate ResourceGate
+		low  bool
+	}{
+		"memory low":  {MemoryGate{MaxHeap: 0}, true},
+		"memory ok":   {MemoryGate{MaxHeap: math.MaxUint64}, false},
+		"disk low":    {DiskGate{Path: t.TempDir(), MinFree: math.MaxUint64}, true},
+		"disk ok":     {DiskGate{Path: t.TempDir(), MinFree: 0}, false},
+		"disk absent": {DiskGate{Path: "/does/not/exist"}, true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := test.gate.Check(); (err != nil) != test.low {
+				t.Fatalf("expected low %v, got %v", test.low, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+//go:build linux || darwin || freebsd
+// +build linux darwin freebsd
+
+package engine
+
+import (
+	"strconv"
+	"syscall"
+)
+
+// DiskGate is a ResourceGate which pauses intake while the free disk
+// space available at Path falls below MinFree bytes
+type DiskGate struct {
+	Path    string
+	MinFree uint64
+}
+
+// Check reads the free disk space of the file system of the path
+func (g DiskGate) Check() error {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(g.Path, &stat); err != nil {
+		return simple("unable to read disk space of "+g.Path, err)
+	}
+
+	free := stat.Bavail * uint64(stat.Bsize)
+	if free < g.MinFree {
+		return simple(
+			"free disk of "+strconv.FormatUint(free, 10)+
+				" bytes is below "+strconv.FormatUint(g.MinFree, 10),
+			nil,
+		)
+	}
+
+	return nil
+}
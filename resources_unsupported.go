@@ -0,0 +1,22 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+//go:build !linux && !darwin && !freebsd
+// +build !linux,!darwin,!freebsd
+
+package engine
+
+// DiskGate is a ResourceGate which pauses intake while the free disk
+// space available at Path falls below MinFree bytes
+type DiskGate struct {
+	Path    string
+	MinFree uint64
+}
+
+// Check is not supported on this platform, reading the free disk space
+// is only supported on Linux, FreeBSD and macOS
+func (g DiskGate) Check() error {
+	return simple("disk gate is not supported on this platform", nil)
+}
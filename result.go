@@ -0,0 +1,119 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+)
+
+// ResultStreamer is an optional interface for conductors which stream the
+// result of an electron to its sender as it is written rather than once
+// the atom returns. Stream is called when the atom first writes to its
+// ResultWriter and the writer is closed once the atom returns, before the
+// properties of the electron are completed.
+type ResultStreamer interface {
+	Stream(ctx context.Context, electron *Electron) (io.WriteCloser, error)
+}
+
+type resultKey struct{}
+
+// ResultWriter returns the writer for streaming the result of the electron
+// being processed by an atom using the context passed to the Process method
+// of the atom. The writes are streamed to the sender when the conductor of
+// the electron implements ResultStreamer, otherwise they are buffered and
+// become the Result of the electron when the atom returns no result.
+func ResultWriter(ctx context.Context) (io.Writer, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+
+	w, ok := ctx.Value(resultKey{}).(*resultWriter)
+	return w, ok
+}
+
+// withResult adds the result writer of the atom instance to its context
+func withResult(ctx context.Context, w *resultWriter) context.Context {
+	return context.WithValue(ctx, resultKey{}, w)
+}
+
+// resultWriter streams or buffers the result written by an atom
+type resultWriter struct {
+	ctx       context.Context
+	conductor Conductor
+	electron  *Electron
+
+	mu      sync.Mutex
+	stream  io.WriteCloser
+	buffer  bytes.Buffer
+	written bool
+	err     error
+}
+
+func (w *resultWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.err != nil {
+		return 0, w.err
+	}
+
+	if !w.written {
+		w.written = true
+		var s ResultStreamer
+		if conductorAs(w.conductor, &s) {
+			w.stream, w.err = s.Stream(w.ctx, w.electron)
+			if w.err != nil {
+				return 0, w.err
+			}
+		}
+	}
+
+	if w.stream != nil {
+		return w.stream.Write(p)
+	}
+
+	return w.buffer.Write(p)
+}
+
+// wrote indicates if the atom wrote a result to the writer
+func (w *resultWriter) wrote() bool {
+	if w == nil {
+		return false
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.written
+}
+
+// finish finalizes the stream or sets the buffered result
+// on the properties
+func (w *resultWriter) finish(p *Properties) {
+	if w == nil {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.stream != nil {
+		err := w.stream.Close()
+		if err != nil && p.Error == nil {
+			p.Error = simple("error closing result stream", err)
+		}
+
+		w.stream = nil
+		return
+	}
+
+	if w.buffer.Len() > 0 && len(p.Result) == 0 {
+		p.Result = append([]byte(nil), w.buffer.Bytes()...)
+	}
+}
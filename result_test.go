@@ -0,0 +1,132 @@
+package engine
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+)
+
+// chunkatom writes its result in chunks
+type chunkatom struct{}
+
+const chunks = 5
+
+var chunk = bytes.Repeat([]byte("a"), 1024)
+
+func (*chunkatom) Process(
+	ctx context.Context,
+	conductor Conductor,
+	electron *Electron,
+) ([]byte, error) {
+	w, ok := ResultWriter(ctx)
+	if !ok {
+		return nil, errors.New("expected result writer")
+	}
+
+	for i := 0; i < chunks; i++ {
+		if _, err := w.Write(chunk); err != nil {
+			return nil, err
+		}
+	}
+
+	return nil, nil
+}
+
+// streamrecorder records the frames streamed for each electron
+type streamrecorder struct {
+	*recorder
+
+	mu     sync.Mutex
+	frames map[string][][]byte
+	closed map[string]bool
+}
+
+type framewriter struct {
+	s  *streamrecorder
+	id string
+}
+
+func (f *framewriter) Write(p []byte) (int, error) {
+	f.s.mu.Lock()
+	defer f.s.mu.Unlock()
+
+	f.s.frames[f.id] = append(f.s.frames[f.id], append([]byte(nil), p...))
+	return len(p), nil
+}
+
+func (f *framewriter) Close() error {
+	f.s.mu.Lock()
+	defer f.s.mu.Unlock()
+
+	f.s.closed[f.id] = true
+	return nil
+}
+
+func (s *streamrecorder) Stream(
+	ctx context.Context,
+	electron *Electron,
+) (io.WriteCloser, error) {
+	return &framewriter{s, electron.ID}, nil
+}
+
+func TestAtomizer_ResultStreaming(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	streamer := &streamrecorder{
+		recorder: newRecorder(),
+		frames:   make(map[string][][]byte),
+		closed:   make(map[string]bool),
+	}
+
+	rec, a := recHarness(ctx, t, streamer, &chunkatom{})
+
+	eventually(t, time.Second, func() bool {
+		return a.route(newElectron(ID(chunkatom{}), nil)) != nil
+	})
+
+	t.Run("streamed", func(t *testing.T) {
+		e := newElectron(ID(chunkatom{}), nil)
+		if _, err := streamer.Send(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+
+		p := streamer.next(ctx, t)
+		if p.Error != nil || len(p.Result) != 0 {
+			t.Fatalf("unexpected completion %+v", p)
+		}
+
+		streamer.mu.Lock()
+		defer streamer.mu.Unlock()
+
+		if len(streamer.frames[e.ID]) != chunks || !streamer.closed[e.ID] {
+			t.Fatalf(
+				"expected %v closed frames, got %v",
+				chunks,
+				len(streamer.frames[e.ID]),
+			)
+		}
+	})
+
+	t.Run("buffered", func(t *testing.T) {
+		e := newElectron(ID(chunkatom{}), nil)
+		if _, err := rec.Send(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+
+		p := rec.next(ctx, t)
+		if len(p.Result) != chunks*len(chunk) {
+			t.Fatalf("expected buffered result, got %v bytes", len(p.Result))
+		}
+	})
+}
@@ -0,0 +1,54 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+// WithResultHashing includes a stable SHA-256 hash of the result in the
+// properties of every electron processed by this node so that the results
+// of the same deterministic electron processed on multiple nodes can be
+// compared with ResultsMatch.
+func WithResultHashing() Option {
+	return func(a *atomizer) error {
+		a.hashResults = true
+		return nil
+	}
+}
+
+// ResultsMatch indicates if the properties hold the same result by
+// comparing their result hashes. The hash of properties without a result
+// hash is calculated from their result.
+func ResultsMatch(a, b Properties) bool {
+	ahash, err := a.resultHash()
+	if err != nil {
+		return false
+	}
+
+	bhash, err := b.resultHash()
+	if err != nil {
+		return false
+	}
+
+	return ahash == bhash
+}
+
+// resultHash returns the result hash of the properties, calculating
+// it from the result when it was not included
+func (p *Properties) resultHash() (string, error) {
+	if p.ResultHash != "" {
+		return p.ResultHash, nil
+	}
+
+	return SHA256.sum(p.Result)
+}
+
+// hashResult sets the result hash of the properties when enabled
+func (a *atomizer) hashResult(p *Properties) {
+	if !a.hashResults || p == nil {
+		return
+	}
+
+	// The sum only fails for an unknown algorithm
+	p.ResultHash, _ = SHA256.sum(p.Result)
+}
@@ -0,0 +1,78 @@
+package engine
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAtomizer_ResultHashing(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	rec, a := recHarness(ctx, t, WithResultHashing(), &echoatom{})
+
+	eventually(t, time.Second, func() bool {
+		return a.route(newElectron(ID(echoatom{}), nil)) != nil
+	})
+
+	payloads := []string{`"same"`, `"same"`, `"different"`}
+
+	results := make([]*Properties, 0, len(payloads))
+	for _, payload := range payloads {
+		e := newElectron(ID(echoatom{}), []byte(payload))
+		if _, err := rec.Send(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+
+		p := rec.next(ctx, t)
+		if !strings.HasPrefix(p.ResultHash, string(SHA256)+":") {
+			t.Fatalf("expected sha256 result hash, got %q", p.ResultHash)
+		}
+
+		results = append(results, p)
+	}
+
+	if !ResultsMatch(*results[0], *results[1]) {
+		t.Fatal("expected identical results to match")
+	}
+
+	if ResultsMatch(*results[0], *results[2]) {
+		t.Fatal("expected differing results not to match")
+	}
+}
+
+func TestResultsMatch(t *testing.T) {
+	hashed := func(result string) Properties {
+		p := Properties{Result: []byte(result)}
+		p.ResultHash, _ = SHA256.sum(p.Result)
+
+		return p
+	}
+
+	tests := map[string]struct {
+		a, b  Properties
+		match bool
+	}{
+		"identical hashed": {hashed(`"a"`), hashed(`"a"`), true},
+		"differing hashed": {hashed(`"a"`), hashed(`"b"`), false},
+		"hashed unhashed":  {hashed(`"a"`), Properties{Result: []byte(`"a"`)}, true},
+		"unhashed":         {Properties{Result: []byte(`"a"`)}, Properties{Result: []byte(`"b"`)}, false},
+		"empty":            {Properties{}, Properties{}, true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if ResultsMatch(test.a, test.b) != test.match {
+				t.Fatalf("expected match %v", test.match)
+			}
+		})
+	}
+}
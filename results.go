@@ -0,0 +1,281 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// ResultStore stores the properties of completed electrons so that they
+// can be retrieved by the sender asynchronously using the electron ID
+type ResultStore interface {
+	// Store saves the properties of a completed electron
+	Store(ctx context.Context, p *Properties) error
+
+	// Load returns the properties of a completed electron
+	Load(ctx context.Context, electronID string) (*Properties, bool, error)
+}
+
+// WithResultStore configures the atomizer to save the properties of each
+// completed electron to the result store. Electrons which are received
+// again after they succeeded are completed with the stored properties
+// without executing the atom, making resubmission idempotent. Electrons
+// which failed are executed again so that they can be retried.
+func WithResultStore(store ResultStore) Option {
+	return func(a *atomizer) error {
+		if store == nil {
+			return simple("nil result store", nil)
+		}
+
+		a.results = store
+		return nil
+	}
+}
+
+// storeResult saves the properties to the result store if configured
+func (a *atomizer) storeResult(p *Properties) {
+	if a.results == nil || p == nil {
+		return
+	}
+
+	if err := a.results.Store(a.ctx, p); err != nil {
+		a.err(func() error {
+			return &Error{
+				Event: &Event{
+					Message:    "error storing result",
+					ElectronID: p.ElectronID,
+					AtomID:     p.AtomID,
+				},
+				Internal: err,
+			}
+		})
+	}
+}
+
+// stored completes the instance with the properties in the result store
+// when the electron already succeeded, returning true so that the atom is
+// not executed again. Errors loading the result are emitted and the
+// electron is processed.
+func (a *atomizer) stored(inst instance) bool {
+	if a.results == nil {
+		return false
+	}
+
+	p, ok, err := a.results.Load(a.ctx, inst.electron.ID)
+	if err != nil {
+		a.err(func() error {
+			return &Error{
+				Event: &Event{
+					Message:     "error loading result",
+					ElectronID:  inst.electron.ID,
+					AtomID:      inst.electron.AtomID,
+					ConductorID: ID(inst.conductor),
+				},
+				Internal: err,
+			}
+		})
+
+		return false
+	}
+
+	if !ok || !succeeded(p) {
+		return false
+	}
+
+	a.event(func() interface{} {
+		return &Event{
+			Message:     "electron completed from result store",
+			ElectronID:  inst.electron.ID,
+			AtomID:      inst.electron.AtomID,
+			ConductorID: ID(inst.conductor),
+		}
+	})
+
+	if !inst.electron.ExpectsReply() {
+		return true
+	}
+
+	// The stored properties were already prepared for completion
+	// so they are delivered as is rather than through complete
+	redelivered := *p
+	err = inst.conductor.Complete(a.ctx, &redelivered)
+	if err != nil {
+		a.err(func() error {
+			return &Error{
+				Event: &Event{
+					Message:     "error completing stored result",
+					ElectronID:  inst.electron.ID,
+					AtomID:      inst.electron.AtomID,
+					ConductorID: ID(inst.conductor),
+				},
+				Internal: err,
+			}
+		})
+	}
+
+	return true
+}
+
+// succeeded indicates if the properties are of an electron which was
+// processed successfully
+func succeeded(p *Properties) bool {
+	return p != nil && p.Error == nil &&
+		(p.Status == "" || p.Status == StatusSucceeded)
+}
+
+type result struct {
+	properties *Properties
+	expires    time.Time
+}
+
+// MemoryResultStore is an in-memory ResultStore. Results expire after the
+// configured TTL and are evicted by a janitor running on an interval so
+// that results which are never loaded do not accumulate. When the number of
+// results exceeds the maximum number of entries the least recently used
+// results are evicted.
+type MemoryResultStore struct {
+	ttl        time.Duration
+	maxEntries int
+
+	mu      sync.Mutex
+	lru     *list.List
+	entries map[string]*list.Element
+}
+
+// NewMemoryResultStore creates an in-memory result store. A zero ttl
+// disables expiration, a zero interval disables the janitor and a zero
+// maxEntries disables the LRU cap. The janitor stops when the context
+// is canceled.
+func NewMemoryResultStore(
+	ctx context.Context,
+	ttl time.Duration,
+	interval time.Duration,
+	maxEntries int,
+) *MemoryResultStore {
+	s := &MemoryResultStore{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		lru:        list.New(),
+		entries:    make(map[string]*list.Element),
+	}
+
+	if ttl > 0 && interval > 0 {
+		go s.janitor(ctx, interval)
+	}
+
+	return s
+}
+
+// Store saves the properties of a completed electron, replacing any
+// existing properties for the same electron
+func (s *MemoryResultStore) Store(_ context.Context, p *Properties) error {
+	if p == nil {
+		return simple("nil properties", nil)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r := &result{properties: p}
+	if s.ttl > 0 {
+		r.expires = time.Now().Add(s.ttl)
+	}
+
+	if el, ok := s.entries[p.ElectronID]; ok {
+		el.Value = r
+		s.lru.MoveToFront(el)
+		return nil
+	}
+
+	s.entries[p.ElectronID] = s.lru.PushFront(r)
+
+	for s.maxEntries > 0 && s.lru.Len() > s.maxEntries {
+		s.evict(s.lru.Back())
+	}
+
+	return nil
+}
+
+// Load returns the properties of a completed electron if they exist
+// and have not expired
+func (s *MemoryResultStore) Load(
+	_ context.Context,
+	electronID string,
+) (*Properties, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	el, ok := s.entries[electronID]
+	if !ok {
+		return nil, false, nil
+	}
+
+	r, _ := el.Value.(*result)
+	if r.expired(time.Now()) {
+		s.evict(el)
+		return nil, false, nil
+	}
+
+	s.lru.MoveToFront(el)
+
+	return r.properties, true, nil
+}
+
+// Len returns the number of results currently held by the store
+func (s *MemoryResultStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.lru.Len()
+}
+
+func (r *result) expired(now time.Time) bool {
+	return !r.expires.IsZero() && now.After(r.expires)
+}
+
+// evict removes the element from the store, the lock MUST be held
+func (s *MemoryResultStore) evict(el *list.Element) {
+	r, _ := s.lru.Remove(el).(*result)
+	delete(s.entries, r.properties.ElectronID)
+}
+
+// janitor evicts the expired results on each interval
+func (s *MemoryResultStore) janitor(
+	ctx context.Context,
+	interval time.Duration,
+) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.sweep(now)
+		}
+	}
+}
+
+// sweep removes all results which have expired
+func (s *MemoryResultStore) sweep(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for el := s.lru.Back(); el != nil; {
+		prev := el.Prev()
+
+		r, _ := el.Value.(*result)
+		if r.expired(now) {
+			s.evict(el)
+		}
+
+		el = prev
+	}
+}
@@ -0,0 +1,224 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryResultStore_Janitor(t *testing.T) {
+	ctx, cancel := _ctx(context.TODO())
+	defer cancel()
+
+	s := NewMemoryResultStore(
+		ctx,
+		time.Millisecond*10,
+		time.Millisecond*5,
+		0,
+	)
+
+	for _, id := range []string{"1", "2", "3"} {
+		if err := s.Store(ctx, &Properties{ElectronID: id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if s.Len() != 3 {
+		t.Fatalf("expected 3 results, got %v", s.Len())
+	}
+
+	// The results are never loaded so only the janitor can evict them
+	eventually(t, time.Second, func() bool {
+		return s.Len() == 0
+	})
+}
+
+func TestMemoryResultStore_LRU(t *testing.T) {
+	ctx, cancel := _ctx(context.TODO())
+	defer cancel()
+
+	s := NewMemoryResultStore(ctx, 0, 0, 2)
+
+	for _, id := range []string{"1", "2"} {
+		if err := s.Store(ctx, &Properties{ElectronID: id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Touch the oldest entry so that 2 becomes the least recently used
+	if _, ok, _ := s.Load(ctx, "1"); !ok {
+		t.Fatal("expected result 1")
+	}
+
+	if err := s.Store(ctx, &Properties{ElectronID: "3"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if s.Len() != 2 {
+		t.Fatalf("expected 2 results, got %v", s.Len())
+	}
+
+	if _, ok, _ := s.Load(ctx, "2"); ok {
+		t.Fatal("expected result 2 to be evicted")
+	}
+
+	for _, id := range []string{"1", "3"} {
+		if _, ok, _ := s.Load(ctx, id); !ok {
+			t.Fatalf("expected result %s", id)
+		}
+	}
+}
+
+func TestMemoryResultStore_LoadExpired(t *testing.T) {
+	ctx, cancel := _ctx(context.TODO())
+	defer cancel()
+
+	s := NewMemoryResultStore(ctx, time.Millisecond, 0, 0)
+	if err := s.Store(ctx, &Properties{ElectronID: "1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(time.Millisecond * 5)
+
+	if _, ok, _ := s.Load(ctx, "1"); ok {
+		t.Fatal("expected expired result")
+	}
+
+	if s.Len() != 0 {
+		t.Fatal("expected expired result to be evicted on load")
+	}
+}
+
+func TestAtomizer_ResultStore(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	store := NewMemoryResultStore(ctx, time.Minute, time.Minute, 10)
+
+	conductor, _, _, err := optHarness(
+		ctx,
+		-1,
+		[]Option{WithResultStore(store)},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := newElectron(ID(noopatom{}), nil)
+	sendAndWait(ctx, t, conductor, e)
+
+	eventually(t, time.Second, func() bool {
+		_, ok, _ := store.Load(ctx, e.ID)
+		return ok
+	})
+}
+
+func TestAtomizer_ResultStore_Idempotent(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	store := NewMemoryResultStore(ctx, 0, 0, 0)
+	rec, a := recHarness(ctx, t, WithResultStore(store), &echoatom{})
+	atomID := ID(&echoatom{})
+
+	e := newElectron(atomID, []byte(`"echo"`))
+	rec.input <- e
+
+	first := rec.next(ctx, t)
+	if string(first.Result) != `"echo"` {
+		t.Fatalf("unexpected result %s", first.Result)
+	}
+
+	eventually(t, time.Second, func() bool {
+		return a.Stats()[atomID].Executions == 1
+	})
+
+	// Resubmitting through the conductor and directly
+	// completes from the store without executing the atom
+	resent := *e
+	rec.input <- &resent
+
+	p := rec.next(ctx, t)
+	if p.ElectronID != e.ID || string(p.Result) != `"echo"` {
+		t.Fatalf("unexpected redelivered properties %+v", p)
+	}
+
+	submitted := make(chan Properties, 1)
+	err := a.SubmitWithCallback(ctx, &resent, func(p Properties, _ error) {
+		submitted <- p
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-ctx.Done():
+		t.Fatal("expected submission callback")
+	case p := <-submitted:
+		if string(p.Result) != `"echo"` {
+			t.Fatalf("unexpected submitted result %s", p.Result)
+		}
+	}
+
+	if n := a.Stats()[atomID].Executions; n != 1 {
+		t.Fatalf("expected 1 execution, got %v", n)
+	}
+
+	// Electrons without a stored result are executed
+	rec.input <- newElectron(atomID, []byte(`"other"`))
+	if p = rec.next(ctx, t); string(p.Result) != `"other"` {
+		t.Fatalf("unexpected result %s", p.Result)
+	}
+}
+
+func TestAtomizer_ResultStore_RetryFailed(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	store := NewMemoryResultStore(ctx, 0, 0, 0)
+	rec, a := recHarness(ctx, t, WithResultStore(store), &failatom{})
+	atomID := ID(&failatom{})
+
+	e := newElectron(atomID, nil)
+	rec.input <- e
+
+	if p := rec.next(ctx, t); p.Error == nil {
+		t.Fatal("expected failure")
+	}
+
+	eventually(t, time.Second, func() bool {
+		_, ok, _ := store.Load(ctx, e.ID)
+		return ok
+	})
+
+	// Retrying the failed electron executes the atom
+	// rather than completing the stored failure
+	retried := *e
+	rec.input <- &retried
+
+	if p := rec.next(ctx, t); p.ElectronID != e.ID || p.Error == nil {
+		t.Fatalf("expected failure of %s, got %+v", e.ID, p)
+	}
+
+	eventually(t, time.Second, func() bool {
+		return a.Stats()[atomID].Executions == 2
+	})
+}
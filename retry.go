@@ -0,0 +1,98 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrCompletionFailed is set as the error of the dead letter stored for
+// an electron whose properties could not be completed on its conductor
+var ErrCompletionFailed = errors.New("completion failed")
+
+// completionRetry is the retry policy for conductor completions
+type completionRetry struct {
+	attempts int
+	backoff  time.Duration
+}
+
+// WithCompletionRetry retries completions which fail on the conductor
+// (i.e. a transient network error) so that the result of an electron is
+// not lost after its atom executed. A completion is attempted at most
+// attempts times, waiting backoff before the first retry and doubling
+// the wait for each retry after it.
+//
+// When every attempt fails the electron is stored in the dead letter
+// store (see WithDeadLetter) with an error satisfying
+// errors.Is(err, ErrCompletionFailed) and its properties are stored in
+// the result store (see WithResultStore) for later delivery.
+func WithCompletionRetry(attempts int, backoff time.Duration) Option {
+	return func(a *atomizer) error {
+		if attempts < 1 {
+			return simple("completion attempts must be at least 1", nil)
+		}
+
+		if backoff < 0 {
+			return simple("negative completion backoff", nil)
+		}
+
+		a.retry = completionRetry{
+			attempts: attempts,
+			backoff:  backoff,
+		}
+
+		return nil
+	}
+}
+
+// deliver completes the properties on the conductor of the instance,
+// retrying failed completions according to the retry policy
+func (a *atomizer) deliver(
+	ctx context.Context,
+	inst *instance,
+	p *Properties,
+) error {
+	wait := a.retry.backoff
+
+	err := inst.conductor.Complete(ctx, p)
+	for attempt := 1; err != nil && attempt < a.retry.attempts; attempt++ {
+		a.event(func() interface{} {
+			return &Event{
+				Message:     "retrying completion",
+				ElectronID:  inst.electron.ID,
+				AtomID:      inst.electron.AtomID,
+				ConductorID: ID(inst.conductor),
+			}
+		})
+
+		select {
+		case <-ctx.Done():
+			return simple("context closed", err)
+		case <-a.ctx.Done():
+			return simple("context closed", err)
+		case <-time.After(wait):
+		}
+
+		wait *= 2
+		err = inst.conductor.Complete(ctx, p)
+	}
+
+	if err == nil {
+		return nil
+	}
+
+	return &Error{
+		Event: &Event{
+			Message:     "error completing electron, " + err.Error(),
+			ElectronID:  inst.electron.ID,
+			AtomID:      inst.electron.AtomID,
+			ConductorID: ID(inst.conductor),
+		},
+		Internal: ErrCompletionFailed,
+	}
+}
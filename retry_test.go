@@ -0,0 +1,242 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+var errFlakyConductor = errors.New("flaky conductor")
+
+// flakyconductor fails the first failures completions of each electron
+type flakyconductor struct {
+	*recorder
+	failures int
+
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+func (c *flakyconductor) Complete(ctx context.Context, p *Properties) error {
+	c.mu.Lock()
+	c.calls[p.ElectronID]++
+	calls := c.calls[p.ElectronID]
+	c.mu.Unlock()
+
+	if c.failures < 0 || calls <= c.failures {
+		return errFlakyConductor
+	}
+
+	return c.recorder.Complete(ctx, p)
+}
+
+func (c *flakyconductor) attempts(electronID string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.calls[electronID]
+}
+
+func flakyHarness(
+	ctx context.Context,
+	t *testing.T,
+	failures int,
+	values ...interface{},
+) (*flakyconductor, *atomizer) {
+	t.Helper()
+
+	c := &flakyconductor{
+		recorder: newRecorder(),
+		failures: failures,
+		calls:    make(map[string]int),
+	}
+
+	mizer, err := Atomize(ctx, append(values, c)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, _ := mizer.(*atomizer)
+	if err = a.Exec(); err != nil {
+		t.Fatal(err)
+	}
+
+	return c, a
+}
+
+func TestAtomizer_CompletionRetry(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	store := &MemoryDeadLetterStore{}
+	c, _ := flakyHarness(
+		ctx,
+		t,
+		2,
+		WithCompletionRetry(3, time.Millisecond),
+		WithDeadLetter(store),
+		&noopatom{},
+	)
+
+	e := newElectron(ID(noopatom{}), nil)
+	if _, err := c.Send(ctx, e); err != nil {
+		t.Fatal(err)
+	}
+
+	p := c.next(ctx, t)
+	if p.ElectronID != e.ID || p.Error != nil {
+		t.Fatalf("expected successful completion of %s, got %v", e.ID, p)
+	}
+
+	if n := c.attempts(e.ID); n != 3 {
+		t.Fatalf("expected 3 completion attempts, got %v", n)
+	}
+
+	if store.Len() != 0 {
+		t.Fatalf("unexpected dead letters %v", store.Len())
+	}
+}
+
+func TestAtomizer_CompletionRetry_Exhausted(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	store := &MemoryDeadLetterStore{}
+	results := NewMemoryResultStore(ctx, 0, 0, 0)
+	c, _ := flakyHarness(
+		ctx,
+		t,
+		-1,
+		WithCompletionRetry(3, time.Millisecond),
+		WithDeadLetter(store),
+		WithResultStore(results),
+		&noopatom{},
+	)
+
+	e := newElectron(ID(noopatom{}), nil)
+	if _, err := c.Send(ctx, e); err != nil {
+		t.Fatal(err)
+	}
+
+	eventually(t, time.Second*5, func() bool {
+		return store.Len() == 1
+	})
+
+	if n := c.attempts(e.ID); n != 3 {
+		t.Fatalf("expected 3 completion attempts, got %v", n)
+	}
+
+	dls, err := store.Take(ctx, DeadLetterFilter{
+		Error: func(err error) bool {
+			return errors.Is(err, ErrCompletionFailed)
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(dls) != 1 || dls[0].Electron.ID != e.ID {
+		t.Fatalf("expected dead letter for %s, got %v", e.ID, dls)
+	}
+
+	eventually(t, time.Second, func() bool {
+		_, ok, _ := results.Load(ctx, e.ID)
+		return ok
+	})
+}
+
+func TestWithCompletionRetry_Invalid(t *testing.T) {
+	tests := map[string]Option{
+		"no attempts":      WithCompletionRetry(0, time.Millisecond),
+		"negative backoff": WithCompletionRetry(1, -time.Millisecond),
+	}
+
+	for name, opt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := opt(&atomizer{}); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
+
+func TestAtomizer_CompletionRetry_Exhausted_Properties(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	store := &MemoryDeadLetterStore{}
+	results := NewMemoryResultStore(ctx, 0, 0, 0)
+	c, a := flakyHarness(
+		ctx,
+		t,
+		-1,
+		WithCompletionRetry(2, time.Millisecond),
+		WithDeadLetter(store),
+		WithResultStore(results),
+		&echoatom{},
+	)
+	errs := a.Errors(10)
+
+	e := newElectron(ID(echoatom{}), []byte(`"echo"`))
+	if _, err := c.Send(ctx, e); err != nil {
+		t.Fatal(err)
+	}
+
+	eventually(t, time.Second*5, func() bool {
+		return store.Len() == 1
+	})
+
+	// The stored result is the result of the atom
+	// without the failure to complete it
+	var p *Properties
+	eventually(t, time.Second, func() bool {
+		p, _, _ = results.Load(ctx, e.ID)
+		return p != nil
+	})
+
+	if p.Error != nil || p.Status != StatusSucceeded ||
+		string(p.Result) != `"echo"` {
+		t.Fatalf("unexpected stored properties %+v", p)
+	}
+
+	eventually(t, time.Second, func() bool {
+		return a.Stats()[ID(echoatom{})].Executions == 1
+	})
+
+	stats := a.Stats()[ID(echoatom{})]
+	if stats.Errors != 0 || stats.ErrorRate != 0 {
+		t.Fatalf("expected no atom errors, got %+v", stats)
+	}
+
+	// The completion failure is reported through the errors
+	for {
+		select {
+		case <-ctx.Done():
+			t.Fatal("expected completion failure error")
+		case err := <-errs:
+			if errors.Is(err, ErrCompletionFailed) {
+				return
+			}
+		}
+	}
+}
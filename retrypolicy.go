@@ -0,0 +1,97 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrNoRetry is wrapped by atoms in the errors of failures which must not
+// be retried (i.e. invalid input) regardless of the retry policy
+var ErrNoRetry = errors.New("no retry")
+
+// RetryPolicy re-runs the atom of an electron whose processing failed,
+// such as for transient failures of an external service the atom calls.
+// The atom is run at most MaxAttempts times waiting InitialBackoff before
+// the first retry and multiplying the wait by Multiplier for each retry
+// after it, up to MaxBackoff. Failures wrapping ErrNoRetry and failures
+// caused by the context of the electron closing are not retried.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of times the atom is run,
+	// including the first attempt
+	MaxAttempts int `json:"maxattempts"`
+
+	// InitialBackoff is the wait before the first retry
+	InitialBackoff time.Duration `json:"initialbackoff,omitempty"`
+
+	// Multiplier is applied to the wait for each retry after the
+	// first, a Multiplier less than 1 keeps the wait constant
+	Multiplier float64 `json:"multiplier,omitempty"`
+
+	// MaxBackoff caps the wait between retries, zero does not cap
+	// the wait
+	MaxBackoff time.Duration `json:"maxbackoff,omitempty"`
+}
+
+// WithRetryPolicy sets the default retry policy for electrons which
+// fail processing. Electrons with their own retry policy use it instead.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(a *atomizer) error {
+		if policy.MaxAttempts < 1 {
+			return simple("retry attempts must be at least 1", nil)
+		}
+
+		if policy.InitialBackoff < 0 || policy.MaxBackoff < 0 {
+			return simple("negative retry backoff", nil)
+		}
+
+		a.retries = &policy
+		return nil
+	}
+}
+
+// retryPolicy returns the retry policy of the electron, if any
+func (a *atomizer) retryPolicy(e *Electron) *RetryPolicy {
+	if e != nil && e.Retry != nil {
+		return e.Retry
+	}
+
+	return a.retries
+}
+
+// backoff returns the wait before the retry following the attempt
+// and false when the failure of the attempt must not be retried
+func (p *RetryPolicy) backoff(
+	ctx context.Context,
+	attempt int,
+	err error,
+) (time.Duration, bool) {
+	if p == nil || err == nil || attempt >= p.MaxAttempts ||
+		ctx.Err() != nil || errors.Is(err, ErrNoRetry) {
+		return 0, false
+	}
+
+	wait := p.InitialBackoff
+	for i := 1; i < attempt && p.Multiplier > 1; i++ {
+		wait = time.Duration(float64(wait) * p.Multiplier)
+
+		if p.MaxBackoff > 0 && wait >= p.MaxBackoff {
+			break
+		}
+	}
+
+	if p.MaxBackoff > 0 && wait > p.MaxBackoff {
+		wait = p.MaxBackoff
+	}
+
+	if wait < 0 {
+		wait = 0
+	}
+
+	return wait, true
+}
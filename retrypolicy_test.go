@@ -0,0 +1,161 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// runs counts the number of times failingatom processed an electron
+var runs sync.Map
+
+// failingatom always fails, without retries for fatal payloads
+type failingatom struct{}
+
+func (*failingatom) Process(
+	ctx context.Context,
+	conductor Conductor,
+	electron *Electron,
+) ([]byte, error) {
+	n, _ := runs.LoadOrStore(electron.ID, new(int))
+	*n.(*int)++
+
+	if string(electron.Payload) == `"fatal"` {
+		return nil, fmt.Errorf("fatal failure: %w", ErrNoRetry)
+	}
+
+	return nil, errors.New("transient failure")
+}
+
+func TestAtomizer_RetryPolicy(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	rec, a := recHarness(
+		ctx,
+		t,
+		WithRetryPolicy(RetryPolicy{
+			MaxAttempts:    3,
+			InitialBackoff: time.Millisecond * 20,
+			Multiplier:     2,
+		}),
+		&failingatom{},
+	)
+
+	eventually(t, time.Second, func() bool {
+		return a.route(newElectron(ID(failingatom{}), nil)) != nil
+	})
+
+	tests := map[string]struct {
+		payload  []byte
+		retry    *RetryPolicy
+		attempts int
+		backoff  time.Duration
+	}{
+		"default policy": {nil, nil, 3, time.Millisecond * 60},
+		"electron policy": {
+			nil,
+			&RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond * 30},
+			2,
+			time.Millisecond * 30,
+		},
+		"no retry": {[]byte(`"fatal"`), nil, 1, 0},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			e := newElectron(ID(failingatom{}), test.payload)
+			e.Retry = test.retry
+
+			if _, err := rec.Send(ctx, e); err != nil {
+				t.Fatal(err)
+			}
+
+			p := rec.next(ctx, t)
+			if p.Error == nil {
+				t.Fatal("expected failure")
+			}
+
+			if p.Attempts != test.attempts {
+				t.Fatalf("expected %v attempts, got %v", test.attempts, p.Attempts)
+			}
+
+			n, _ := runs.Load(e.ID)
+			if n == nil || *n.(*int) != test.attempts {
+				t.Fatalf("expected atom to run %v times", test.attempts)
+			}
+
+			if p.Elapsed() < test.backoff {
+				t.Fatalf("expected %s of backoff, got %s", test.backoff, p.Elapsed())
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_backoff(t *testing.T) {
+	policy := &RetryPolicy{
+		MaxAttempts:    5,
+		InitialBackoff: time.Millisecond * 10,
+		Multiplier:     2,
+		MaxBackoff:     time.Millisecond * 30,
+	}
+
+	failure := errors.New("failure")
+
+	tests := map[string]struct {
+		policy  *RetryPolicy
+		attempt int
+		err     error
+		wait    time.Duration
+		retry   bool
+	}{
+		"first":     {policy, 1, failure, time.Millisecond * 10, true},
+		"second":    {policy, 2, failure, time.Millisecond * 20, true},
+		"capped":    {policy, 3, failure, time.Millisecond * 30, true},
+		"exhausted": {policy, 5, failure, 0, false},
+		"succeeded": {policy, 1, nil, 0, false},
+		"no retry":  {policy, 1, fmt.Errorf("%w", ErrNoRetry), 0, false},
+		"no policy": {nil, 1, failure, 0, false},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			wait, retry := test.policy.backoff(
+				context.TODO(),
+				test.attempt,
+				test.err,
+			)
+			if wait != test.wait || retry != test.retry {
+				t.Fatalf(
+					"expected %s %v, got %s %v",
+					test.wait, test.retry, wait, retry,
+				)
+			}
+		})
+	}
+}
+
+func TestWithRetryPolicy_Invalid(t *testing.T) {
+	tests := map[string]RetryPolicy{
+		"zero attempts":    {},
+		"negative backoff": {MaxAttempts: 2, InitialBackoff: -1},
+		"negative max":     {MaxAttempts: 2, MaxBackoff: -1},
+	}
+
+	for name, policy := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := WithRetryPolicy(policy)(&atomizer{}); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
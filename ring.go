@@ -0,0 +1,91 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import (
+	"hash/fnv"
+	"sort"
+	"strconv"
+)
+
+// vnodes is the number of virtual nodes placed on the ring for each member
+// to smooth out the distribution of keys across the members
+const vnodes = 64
+
+// ring is a consistent hash ring which maps keys to members such that
+// adding or removing a member only remaps the keys owned by that member
+type ring struct {
+	hashes  []uint32
+	members map[uint32]string
+}
+
+func newRing(members ...string) *ring {
+	r := &ring{members: make(map[uint32]string)}
+
+	for _, m := range members {
+		r.add(m)
+	}
+
+	return r
+}
+
+func hash(key string) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+
+	return h.Sum32()
+}
+
+// add places the virtual nodes of the member on the ring
+func (r *ring) add(member string) {
+	for i := 0; i < vnodes; i++ {
+		h := hash(member + "#" + strconv.Itoa(i))
+		if _, ok := r.members[h]; ok {
+			continue
+		}
+
+		r.members[h] = member
+		r.hashes = append(r.hashes, h)
+	}
+
+	sort.Slice(r.hashes, func(i, j int) bool {
+		return r.hashes[i] < r.hashes[j]
+	})
+}
+
+// remove removes the virtual nodes of the member from the ring
+func (r *ring) remove(member string) {
+	hashes := r.hashes[:0]
+	for _, h := range r.hashes {
+		if r.members[h] == member {
+			delete(r.members, h)
+			continue
+		}
+
+		hashes = append(hashes, h)
+	}
+
+	r.hashes = hashes
+}
+
+// get returns the member which owns the key
+func (r *ring) get(key string) (string, bool) {
+	if len(r.hashes) == 0 {
+		return "", false
+	}
+
+	h := hash(key)
+	i := sort.Search(len(r.hashes), func(i int) bool {
+		return r.hashes[i] >= h
+	})
+
+	// Wrap around to the start of the ring
+	if i == len(r.hashes) {
+		i = 0
+	}
+
+	return r.members[r.hashes[i]], true
+}
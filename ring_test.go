@@ -0,0 +1,72 @@
+package engine
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestRing_Stable(t *testing.T) {
+	r := newRing("a", "b", "c")
+
+	for i := 0; i < 1000; i++ {
+		key := strconv.Itoa(i)
+
+		first, ok := r.get(key)
+		if !ok {
+			t.Fatal("expected member")
+		}
+
+		for j := 0; j < 3; j++ {
+			m, _ := r.get(key)
+			if m != first {
+				t.Fatalf("key %s moved from %s to %s", key, first, m)
+			}
+		}
+	}
+}
+
+func TestRing_MinimalRemap(t *testing.T) {
+	keys := 10000
+	r := newRing("a", "b", "c")
+
+	before := make(map[string]string, keys)
+	for i := 0; i < keys; i++ {
+		key := strconv.Itoa(i)
+		before[key], _ = r.get(key)
+	}
+
+	r.add("d")
+
+	var moved int
+	for key, old := range before {
+		m, _ := r.get(key)
+		if m == old {
+			continue
+		}
+
+		if m != "d" {
+			t.Fatalf("key %s moved from %s to existing member %s", key, old, m)
+		}
+
+		moved++
+	}
+
+	// Ideally a quarter of the keys move to the new member
+	if moved == 0 || moved > keys/2 {
+		t.Fatalf("expected roughly a quarter of keys to move, moved %v", moved)
+	}
+
+	r.remove("d")
+
+	for key, old := range before {
+		if m, _ := r.get(key); m != old {
+			t.Fatalf("key %s did not return to %s after removal", key, old)
+		}
+	}
+}
+
+func TestRing_Empty(t *testing.T) {
+	if _, ok := newRing().get("key"); ok {
+		t.Fatal("expected no member")
+	}
+}
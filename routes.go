@@ -0,0 +1,45 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+// WithRouteSnapshot routes electrons using an immutable snapshot of the
+// registered atoms which is atomically swapped whenever the registrations
+// change, so that steady-state routing in distribute takes no locks. This
+// favors very high electron rates with rare registration changes since
+// every registration change copies the registered atoms.
+func WithRouteSnapshot() Option {
+	return func(a *atomizer) error {
+		a.snapshotRoutes = true
+		return nil
+	}
+}
+
+// rebuild swaps the routing snapshot for a copy of the registered atoms.
+// The replicas are immutable once registered so only the map is copied.
+// The atoms lock MUST be held for writing.
+func (a *atomizer) rebuild() {
+	if !a.snapshotRoutes {
+		return
+	}
+
+	routes := make(map[string]*replicas, len(a.atoms))
+	for id, reps := range a.atoms {
+		routes[id] = reps
+	}
+
+	a.routes.Store(routes)
+}
+
+// routing returns the routing snapshot if routing
+// snapshots are enabled and one was built
+func (a *atomizer) routing() (map[string]*replicas, bool) {
+	if !a.snapshotRoutes {
+		return nil, false
+	}
+
+	routes, ok := a.routes.Load().(map[string]*replicas)
+	return routes, ok
+}
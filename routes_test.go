@@ -0,0 +1,117 @@
+package engine
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func routeHarness(tb testing.TB, opts ...interface{}) (*atomizer, func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	mizer, err := Atomize(ctx, opts...)
+	if err != nil {
+		cancel()
+		tb.Fatal(err)
+	}
+
+	a, _ := mizer.(*atomizer)
+	if err = a.receiveAtom(&noopatom{}); err != nil {
+		cancel()
+		tb.Fatal(err)
+	}
+
+	return a, cancel
+}
+
+func TestAtomizer_RouteSnapshot(t *testing.T) {
+	a, cancel := routeHarness(t, WithRouteSnapshot())
+	defer cancel()
+
+	e := newElectron(ID(noopatom{}), nil)
+	if a.route(e) == nil {
+		t.Fatal("expected route for registered atom")
+	}
+
+	if err := a.deregister(ID(noopatom{})); err != nil {
+		t.Fatal(err)
+	}
+
+	if a.route(e) != nil {
+		t.Fatal("expected no route for deregistered atom")
+	}
+}
+
+func TestAtomizer_RouteSnapshot_Race(t *testing.T) {
+	a, cancel := routeHarness(t, WithRouteSnapshot())
+	defer cancel()
+
+	e := newElectron(ID(noopatom{}), nil)
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for {
+				select {
+				case <-done:
+					return
+				default:
+					a.route(e)
+				}
+			}
+		}()
+	}
+
+	// Churn the registrations while routing
+	deadline := time.Now().Add(time.Millisecond * 100)
+	for time.Now().Before(deadline) {
+		_ = a.command(&Command{Action: DisableAtom, AtomID: ID(noopatom{})})
+		_ = a.command(&Command{Action: EnableAtom, AtomID: ID(noopatom{})})
+	}
+
+	close(done)
+	wg.Wait()
+
+	if a.route(e) == nil {
+		t.Fatal("expected route once the churn settled")
+	}
+}
+
+func benchmarkRoute(b *testing.B, opts ...interface{}) {
+	a, cancel := routeHarness(b, opts...)
+	defer cancel()
+
+	// Routing to one atom amongst many
+	for i := 0; i < 100; i++ {
+		a.atomsMu.Lock()
+		a.atoms["atom"+strconv.Itoa(i)] = newReplicas(&noopatom{})
+		a.rebuild()
+		a.atomsMu.Unlock()
+	}
+
+	e := newElectron(ID(noopatom{}), nil)
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if a.route(e) == nil {
+				b.Error("expected route")
+				return
+			}
+		}
+	})
+}
+
+func BenchmarkAtomizer_Route_Locked(b *testing.B) {
+	benchmarkRoute(b)
+}
+
+func BenchmarkAtomizer_Route_Snapshot(b *testing.B) {
+	benchmarkRoute(b, WithRouteSnapshot())
+}
@@ -0,0 +1,77 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import (
+	"fmt"
+	"regexp"
+	"sync/atomic"
+)
+
+// sampling samples the events whose message matches the pattern
+// emitting one in every n events
+type sampling struct {
+	pattern *regexp.Regexp
+	n       uint64
+	seen    uint64
+}
+
+// WithEventSampling samples the events whose message matches the
+// regular expression so that only one in every n matching events is
+// emitted. Events which do not match a sampling pattern are always
+// emitted. When multiple patterns match an event the first configured
+// pattern is used.
+func WithEventSampling(messagePattern string, n int) Option {
+	return func(a *atomizer) error {
+		if n < 1 {
+			return simple(
+				fmt.Sprintf("invalid event sampling rate %v", n),
+				nil,
+			)
+		}
+
+		pattern, err := regexp.Compile(messagePattern)
+		if err != nil {
+			return simple("invalid event sampling pattern", err)
+		}
+
+		a.sampling = append(a.sampling, &sampling{
+			pattern: pattern,
+			n:       uint64(n),
+		})
+
+		return nil
+	}
+}
+
+// sampled indicates if the event should be emitted
+func (a *atomizer) sampled(e interface{}) bool {
+	if len(a.sampling) == 0 {
+		return true
+	}
+
+	var msg string
+	switch v := e.(type) {
+	case *Event:
+		msg = v.Message
+	case string:
+		msg = v
+	case fmt.Stringer:
+		msg = v.String()
+	default:
+		return true
+	}
+
+	for _, s := range a.sampling {
+		if !s.pattern.MatchString(msg) {
+			continue
+		}
+
+		return (atomic.AddUint64(&s.seen, 1)-1)%s.n == 0
+	}
+
+	return true
+}
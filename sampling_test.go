@@ -0,0 +1,72 @@
+package engine
+
+import (
+	"context"
+	"testing"
+)
+
+func TestAtomizer_EventSampling(t *testing.T) {
+	ctx, cancel := _ctx(context.TODO())
+	defer cancel()
+
+	mizer, err := Atomize(ctx, WithEventSampling("^pushed electron", 3))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, _ := mizer.(*atomizer)
+	events := a.Events(100)
+
+	for i := 0; i < 30; i++ {
+		a.event(func() interface{} {
+			return &Event{Message: "pushed electron to atom"}
+		})
+	}
+
+	for i := 0; i < 5; i++ {
+		a.event(func() interface{} {
+			return makeEvent("registered atom")
+		})
+	}
+
+	counts := map[string]int{}
+	for len(events) > 0 {
+		e := <-events
+		if ev, ok := e.(*Event); ok {
+			counts[ev.Message]++
+		}
+	}
+
+	if counts["pushed electron to atom"] != 10 {
+		t.Fatalf(
+			"expected 10 sampled events, got %v",
+			counts["pushed electron to atom"],
+		)
+	}
+
+	if counts["registered atom"] != 5 {
+		t.Fatalf(
+			"expected 5 unsampled events, got %v",
+			counts["registered atom"],
+		)
+	}
+}
+
+func TestWithEventSampling_Invalid(t *testing.T) {
+	tests := map[string]struct {
+		pattern string
+		n       int
+	}{
+		"zero rate":       {"event", 0},
+		"invalid pattern": {"(", 2},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := WithEventSampling(test.pattern, test.n)(&atomizer{})
+			if err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
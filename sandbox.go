@@ -0,0 +1,56 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import "context"
+
+// sandbox executes the instance in a dedicated goroutine so that an atom
+// which ignores the cancellation of its context cannot hold the atomizer
+// hostage. When expired is closed before the atom returns the atomizer
+// stops waiting, leaving the goroutine to finish on its own, and reports
+// the instance as abandoned. The electron itself has already been
+// completed by the hard timeout and any later completion is discarded.
+//
+// A nil expired channel executes the instance in place.
+func (a *atomizer) sandbox(
+	ctx context.Context,
+	inst *instance,
+	atomID string,
+	expired <-chan struct{},
+) (abandoned bool, err error) {
+	if expired == nil {
+		return false, inst.execute(ctx)
+	}
+
+	done := make(chan error, 1)
+	a.routine(func() {
+		done <- inst.execute(ctx)
+	})
+
+	select {
+	case err = <-done:
+		return false, err
+	case <-expired:
+	}
+
+	// The hard timeout and the atom may race, prefer the result
+	select {
+	case err = <-done:
+		return false, err
+	default:
+	}
+
+	a.event(func() interface{} {
+		return &Event{
+			Message:     "leaked goroutine, atom ignored cancellation",
+			ElectronID:  inst.electron.ID,
+			AtomID:      atomID,
+			ConductorID: ID(inst.conductor),
+		}
+	})
+
+	return true, ErrHardTimeout
+}
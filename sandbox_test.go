@@ -0,0 +1,72 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAtomizer_Sandbox_Abandon(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	release := stick()
+	t.Cleanup(func() { close(release) })
+
+	rec, a := recHarness(
+		ctx,
+		t,
+		WithHardTimeout(time.Millisecond*50),
+		&stuckatom{},
+	)
+	events := a.Events(100)
+
+	// The second electron is only executed when the
+	// first non-cooperative instance is abandoned
+	sent := make(map[string]bool)
+	for i := 0; i < 2; i++ {
+		e := newElectron(ID(stuckatom{}), nil)
+		if _, err := rec.Send(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+
+		sent[e.ID] = true
+	}
+
+	for i := 0; i < 2; i++ {
+		p := rec.next(ctx, t)
+		if !sent[p.ElectronID] {
+			t.Fatalf("unexpected completion for %s", p.ElectronID)
+		}
+
+		if !errors.Is(p.Error, ErrHardTimeout) {
+			t.Fatalf("expected hard timeout, got %v", p.Error)
+		}
+
+		if p.Status != StatusTimedOut {
+			t.Fatalf("expected timed out status, got %v", p.Status)
+		}
+	}
+
+	for len(sent) > 0 {
+		var ev interface{}
+		select {
+		case <-ctx.Done():
+			t.Fatalf("expected leaked goroutine warnings for %v", sent)
+		case ev = <-events:
+		}
+
+		event, ok := ev.(*Event)
+		if ok && strings.Contains(event.Message, "leaked goroutine") {
+			delete(sent, event.ElectronID)
+		}
+	}
+}
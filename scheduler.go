@@ -0,0 +1,68 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import (
+	"context"
+	"runtime"
+)
+
+// scheduler limits the concurrent executions of CPU-bound and IO-bound
+// atoms across every atom of the atomizer
+type scheduler struct {
+	cpu chan struct{}
+	io  chan struct{}
+}
+
+// WithScheduler limits the number of atoms executing at once across the
+// atomizer by their classification. CPU-bound atoms share cpuSlots
+// concurrent executions so they do not oversubscribe the cores while
+// IO-bound atoms (see IOBoundAtom) share the separate ioSlots executions.
+// A slot count of 0 defaults to GOMAXPROCS for CPU-bound atoms and
+// IOBoundFactor times GOMAXPROCS for IO-bound atoms.
+func WithScheduler(cpuSlots, ioSlots int) Option {
+	return func(a *atomizer) error {
+		if cpuSlots < 0 || ioSlots < 0 {
+			return simple("scheduler slots must not be negative", nil)
+		}
+
+		procs := runtime.GOMAXPROCS(0)
+		if cpuSlots == 0 {
+			cpuSlots = procs
+		}
+
+		if ioSlots == 0 {
+			ioSlots = procs * IOBoundFactor
+		}
+
+		a.scheduler = &scheduler{
+			cpu: make(chan struct{}, cpuSlots),
+			io:  make(chan struct{}, ioSlots),
+		}
+
+		return nil
+	}
+}
+
+// schedule waits for an execution slot for the atom and returns the
+// release of the slot or false if the context closed while waiting
+func (a *atomizer) schedule(ctx context.Context, atom Atom) (func(), bool) {
+	if a.scheduler == nil {
+		return func() {}, true
+	}
+
+	slots := a.scheduler.cpu
+	if io, ok := atom.(IOBoundAtom); ok && io.IOBound() {
+		slots = a.scheduler.io
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, false
+	case slots <- struct{}{}:
+		return func() { <-slots }, true
+	}
+}
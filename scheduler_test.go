@@ -0,0 +1,139 @@
+package engine
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// gauge tracks the peak of a concurrent count
+type gauge struct {
+	mu      sync.Mutex
+	current int
+	peak    int
+}
+
+func (g *gauge) hold(d time.Duration) {
+	g.mu.Lock()
+	g.current++
+	if g.current > g.peak {
+		g.peak = g.current
+	}
+	g.mu.Unlock()
+
+	time.Sleep(d)
+
+	g.mu.Lock()
+	g.current--
+	g.mu.Unlock()
+}
+
+func (g *gauge) highest() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.peak
+}
+
+var cpuGauge, ioGauge *gauge
+
+type cpuboundatom struct{}
+
+func (*cpuboundatom) Process(
+	ctx context.Context,
+	conductor Conductor,
+	electron *Electron,
+) ([]byte, error) {
+	cpuGauge.hold(time.Millisecond * 20)
+	return nil, nil
+}
+
+type ioboundatom struct{}
+
+func (*ioboundatom) IOBound() bool { return true }
+
+func (*ioboundatom) Process(
+	ctx context.Context,
+	conductor Conductor,
+	electron *Electron,
+) ([]byte, error) {
+	ioGauge.hold(time.Millisecond * 100)
+	return nil, nil
+}
+
+func TestAtomizer_Scheduler(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	cpuGauge, ioGauge = &gauge{}, &gauge{}
+
+	const (
+		replicas = 16
+		cpuSlots = 2
+	)
+
+	rec, a := recHarness(
+		ctx,
+		t,
+		WithScheduler(cpuSlots, replicas),
+		WithReplicas(ID(cpuboundatom{}), replicas),
+		WithReplicas(ID(ioboundatom{}), replicas),
+		&cpuboundatom{},
+		&ioboundatom{},
+	)
+
+	eventually(t, time.Second, func() bool {
+		return a.route(newElectron(ID(cpuboundatom{}), nil)) != nil &&
+			a.route(newElectron(ID(ioboundatom{}), nil)) != nil
+	})
+
+	var sent int
+	for _, atomID := range []string{ID(ioboundatom{}), ID(cpuboundatom{})} {
+		for i := 0; i < replicas; i++ {
+			e := newElectron(atomID, nil)
+			e.PartitionKey = strconv.Itoa(i)
+			if _, err := rec.Send(ctx, e); err != nil {
+				t.Fatal(err)
+			}
+
+			sent++
+		}
+	}
+
+	for i := 0; i < sent; i++ {
+		if p := rec.next(ctx, t); p.Error != nil {
+			t.Fatalf("unexpected error %v", p.Error)
+		}
+	}
+
+	if cpuGauge.highest() > cpuSlots {
+		t.Fatalf("expected at most %v cpu executions, got %v", cpuSlots, cpuGauge.highest())
+	}
+
+	if ioGauge.highest() <= cpuSlots {
+		t.Fatalf("expected io executions beyond the cpu slots, got %v", ioGauge.highest())
+	}
+}
+
+func TestWithScheduler_Defaults(t *testing.T) {
+	a := &atomizer{}
+	if err := WithScheduler(0, 0)(a); err != nil {
+		t.Fatal(err)
+	}
+
+	if cap(a.scheduler.io) != cap(a.scheduler.cpu)*IOBoundFactor {
+		t.Fatalf("unexpected slots %v/%v", cap(a.scheduler.cpu), cap(a.scheduler.io))
+	}
+
+	if err := WithScheduler(-1, 0)(&atomizer{}); err == nil {
+		t.Fatal("expected error")
+	}
+}
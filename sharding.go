@@ -0,0 +1,84 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import "time"
+
+// Sharder selects which of the replicas of an atom processes the electron,
+// returning an index in the range [0, replicas). Indexes out of range are
+// wrapped onto the replicas.
+type Sharder func(e *Electron, replicas int) int
+
+// WithSharder routes the electrons for the atom with the supplied ID to its
+// replicas (see WithReplicas) using the sharder rather than consistent
+// hashing of the PartitionKey of the electron
+func WithSharder(atomID string, sharder Sharder) Option {
+	return func(a *atomizer) error {
+		if atomID == "" {
+			return simple("empty sharder atom id", nil)
+		}
+
+		if sharder == nil {
+			return simple("nil sharder", nil)
+		}
+
+		if a.sharders == nil {
+			a.sharders = make(map[string]Sharder)
+		}
+
+		a.sharders[atomID] = sharder
+		return nil
+	}
+}
+
+// WithTimeSharding routes the electrons for the atom with the supplied ID
+// to its replicas by arrival time. Every electron arriving within the same
+// window is processed by the same replica and each window moves on to the
+// next replica, spreading steady input evenly across the replicas without
+// hashing so replicas can be added simply.
+func WithTimeSharding(atomID string, window time.Duration) Option {
+	return func(a *atomizer) error {
+		if window <= 0 {
+			return simple("time sharding window must be positive", nil)
+		}
+
+		return WithSharder(atomID, TimeSharder(window, time.Now))(a)
+	}
+}
+
+// TimeSharder creates a Sharder which assigns the electrons to replicas by
+// the window of the clock they arrive in, rotating through the replicas
+// with each window
+func TimeSharder(window time.Duration, clock func() time.Time) Sharder {
+	if clock == nil {
+		clock = time.Now
+	}
+
+	return func(_ *Electron, replicas int) int {
+		if window <= 0 || replicas < 1 {
+			return 0
+		}
+
+		bucket := clock().UnixNano() / int64(window)
+
+		return int(bucket % int64(replicas))
+	}
+}
+
+// shard returns the channel of the replica selected by the sharder.
+// The replicas lock MUST be held.
+func (r *replicas) shard(e *Electron) chan<- instance {
+	if len(r.names) == 0 {
+		return nil
+	}
+
+	i := r.sharder(e, len(r.names)) % len(r.names)
+	if i < 0 {
+		i += len(r.names)
+	}
+
+	return r.channels[r.names[i]]
+}
@@ -0,0 +1,112 @@
+package engine
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// stepclock is a clock which advances by the step each time it is read
+type stepclock struct {
+	mu   sync.Mutex
+	now  time.Time
+	step time.Duration
+}
+
+func (c *stepclock) read() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now
+	c.now = c.now.Add(c.step)
+	return now
+}
+
+func TestTimeSharder(t *testing.T) {
+	window := time.Millisecond * 10
+	clock := &stepclock{now: time.Unix(0, 0), step: window}
+	sharder := TimeSharder(window, clock.read)
+
+	// Each window moves on to the next replica
+	for i := 0; i < 9; i++ {
+		if got := sharder(&Electron{}, 3); got != i%3 {
+			t.Fatalf("expected replica %v for window %v, got %v", i%3, i, got)
+		}
+	}
+
+	if got := sharder(&Electron{}, 0); got != 0 {
+		t.Fatalf("expected replica 0 without replicas, got %v", got)
+	}
+}
+
+func TestAtomizer_TimeSharding(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	// Steady input of 5 electrons per window
+	window := time.Millisecond * 10
+	clock := &stepclock{now: time.Unix(0, 0), step: window / 5}
+
+	atomID := ID(noopatom{})
+	replicas := 4
+	rec, a := recHarness(
+		ctx,
+		t,
+		WithReplicas(atomID, replicas),
+		WithSharder(atomID, TimeSharder(window, clock.read)),
+		&noopatom{},
+	)
+
+	eventually(t, time.Second, func() bool {
+		return a.route(newElectron(atomID, nil)) != nil
+	})
+
+	counts := make(map[chan<- instance]int)
+	electrons := 1000
+	for i := 0; i < electrons; i++ {
+		counts[a.route(newElectron(atomID, nil))]++
+	}
+
+	if len(counts) != replicas {
+		t.Fatalf("expected %v replicas used, got %v", replicas, len(counts))
+	}
+
+	expected := electrons / replicas
+	for _, n := range counts {
+		if n < expected*9/10 || n > expected*11/10 {
+			t.Fatalf("expected roughly %v electrons per replica, got %v", expected, n)
+		}
+	}
+
+	// Electrons routed through the sharder are processed
+	for i := 0; i < replicas*2; i++ {
+		rec.input <- newElectron(atomID, nil)
+		if p := rec.next(ctx, t); p.Error != nil {
+			t.Fatal(p.Error)
+		}
+	}
+}
+
+func TestWithSharder_Invalid(t *testing.T) {
+	tests := map[string]Option{
+		"empty id":      WithSharder("", TimeSharder(time.Second, nil)),
+		"nil sharder":   WithSharder("test", nil),
+		"zero window":   WithTimeSharding("test", 0),
+		"time empty id": WithTimeSharding("", time.Second),
+	}
+
+	for name, opt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := opt(&atomizer{}); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,381 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrShutdown is the error completed to the conductors of the electrons
+// which were abandoned because they did not finish processing before the
+// shutdown context expired
+var ErrShutdown = errors.New("atomizer shut down")
+
+// ShutdownReport summarizes a shutdown of the atomizer so that deploy
+// tooling can log and alert on lossy shutdowns
+type ShutdownReport struct {
+	// DrainedElectrons is the number of in-flight electrons which
+	// finished processing while the atomizer was shutting down
+	DrainedElectrons int `json:"drained"`
+
+	// AbandonedElectrons are the IDs of the in-flight electrons which
+	// had not finished processing when the shutdown context expired
+	AbandonedElectrons []string `json:"abandoned,omitempty"`
+
+	// ConductorsStopped is the number of conductors the atomizer
+	// stopped receiving electrons from
+	ConductorsStopped int `json:"conductors"`
+
+	// Duration is how long the shutdown took
+	Duration time.Duration `json:"duration"`
+}
+
+// Shutdown stops the atomizer from receiving new electrons from its
+// conductors and waits for the in-flight electrons to finish processing
+// and complete to their conductors before closing the atomizer. Electrons
+// still in flight when the context expires are cancelled, completed to
+// their conductors with ErrShutdown and reported as abandoned along with
+// an error.
+func (a *atomizer) Shutdown(ctx context.Context) (ShutdownReport, error) {
+	start := time.Now()
+
+	if ctx == nil {
+		ctx = a.ctx
+	}
+
+	empty := a.inflight.drain()
+
+	// Stop receiving electrons from the conductors
+	stopped := a.stopIntake()
+
+	var err error
+	select {
+	case <-empty:
+	case <-ctx.Done():
+		err = &Error{
+			Event:    makeEvent("shutdown abandoned in-flight electrons"),
+			Internal: ctx.Err(),
+		}
+	case <-a.ctx.Done():
+		err = simple("context closed", nil)
+	}
+
+	drained, abandoned := a.inflight.abandon()
+
+	var ids []string
+	for _, ab := range abandoned {
+		ids = append(ids, ab.electron.ID)
+
+		// Bonded electrons are completed by their abort
+		if ab.abort != nil {
+			ab.abort()
+			continue
+		}
+
+		if ab.conductor != nil {
+			a.abort(
+				&instance{electron: ab.electron, conductor: ab.conductor},
+				ab.electron.AtomID,
+				nil,
+			)
+		}
+	}
+
+	// Flush the completions still pending in batches
+	a.batching.drain(a.ctx)
+
+	a.cancel()
+
+	report := ShutdownReport{
+		DrainedElectrons:   drained,
+		AbandonedElectrons: ids,
+		ConductorsStopped:  stopped,
+		Duration:           time.Since(start),
+	}
+
+	a.event(func() interface{} {
+		return makeEvent("atomizer shutdown")
+	})
+
+	return report, err
+}
+
+// abort completes the instance abandoned by a shutdown to its conductor
+// with ErrShutdown using the completion of the executing instance, if any
+func (a *atomizer) abort(
+	inst *instance,
+	atomID string,
+	complete func(ctx context.Context, p *Properties) error,
+) {
+	err := &Error{
+		Event: &Event{
+			Message:     "electron abandoned by shutdown",
+			ElectronID:  inst.electron.ID,
+			AtomID:      atomID,
+			ConductorID: ID(inst.conductor),
+		},
+		Internal: ErrShutdown,
+	}
+
+	if complete == nil {
+		complete = func(ctx context.Context, p *Properties) error {
+			return a.complete(ctx, inst, p)
+		}
+	}
+
+	completion := complete(a.ctx, &Properties{
+		ElectronID:    inst.electron.ID,
+		AtomID:        atomID,
+		CorrelationID: inst.electron.CorrelationID,
+		End:           time.Now(),
+		Error:         err,
+		Status:        StatusFailed,
+	})
+
+	if completion != nil {
+		a.err(func() error {
+			return completion
+		})
+	}
+}
+
+// stopIntake stops receiving electrons from the conductors and returns
+// the number of conductors stopped
+func (a *atomizer) stopIntake() int {
+	a.conductorsMu.Lock()
+	defer a.conductorsMu.Unlock()
+
+	stopped := len(a.conducting)
+	for id, cancel := range a.conducting {
+		cancel()
+		delete(a.conducting, id)
+	}
+
+	return stopped
+}
+
+// inflight tracks the electrons received from the conductors until
+// they finish processing so that they can be drained on shutdown
+type inflight struct {
+	mu       sync.Mutex
+	pending  map[*Electron]context.CancelFunc
+	draining bool
+	drained  int
+	empty    chan struct{}
+
+	// conductors are the conductors of the tracked electrons
+	// so that abandoned electrons can be completed
+	conductors map[*Electron]Conductor
+
+	// depths are the number of tracked electrons for each atom,
+	// counted by the atom the electrons were tracked for (atomIDs)
+	depths  map[string]int
+	atomIDs map[*Electron]string
+
+	// abandoned is set once a shutdown abandoned the tracked
+	// electrons so that untracked electrons are not processed
+	abandoned bool
+
+	// migrated are the electrons which were snapshotted
+	// before being bonded and must not be processed
+	migrated map[*Electron]struct{}
+	stopped  bool
+
+	// ids are the electron IDs claimed by in-flight
+	// electrons, bounded to window, when strict IDs are
+	// enabled (see WithStrictIDs)
+	ids    map[string]*Electron
+	window int
+
+	// finished is invoked with each electron
+	// which is done when set
+	finished func(e *Electron)
+}
+
+// add tracks a received electron along with its conductor
+func (f *inflight) add(e *Electron, conductor Conductor) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.pending == nil {
+		f.pending = make(map[*Electron]context.CancelFunc)
+	}
+
+	if f.conductors == nil {
+		f.conductors = make(map[*Electron]Conductor)
+	}
+
+	if f.depths == nil {
+		f.depths = make(map[string]int)
+		f.atomIDs = make(map[*Electron]string)
+	}
+
+	if _, ok := f.pending[e]; !ok {
+		f.depths[e.AtomID]++
+		f.atomIDs[e] = e.AtomID
+	}
+
+	f.pending[e] = nil
+	f.conductors[e] = conductor
+}
+
+// uncount removes the electron from the depth of its atom
+func (f *inflight) uncount(e *Electron) {
+	atomID, ok := f.atomIDs[e]
+	if !ok {
+		return
+	}
+
+	delete(f.atomIDs, e)
+
+	f.depths[atomID]--
+	if f.depths[atomID] <= 0 {
+		delete(f.depths, atomID)
+	}
+}
+
+// bond sets the cancellation of a tracked electron once it has started
+// processing and returns false if the electron was migrated or abandoned
+// and must not be processed
+func (f *inflight) bond(e *Electron, cancel context.CancelFunc) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.migrated[e]; ok {
+		return false
+	}
+
+	_, ok := f.pending[e]
+	if ok {
+		f.pending[e] = cancel
+	}
+
+	return ok || !f.abandoned
+}
+
+// queued returns the number of tracked electrons for the atom
+func (f *inflight) queued(atomID string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.depths[atomID]
+}
+
+// untracker is implemented by conductors which hold state for each
+// received electron until the electron is done (see TracingConductor)
+type untracker interface {
+	untrack(e *Electron)
+}
+
+// done stops tracking an electron
+func (f *inflight) done(e *Electron) {
+	if f.finished != nil && e != nil {
+		f.finished(e)
+	}
+
+	var u untracker
+	defer func() {
+		if u != nil {
+			u.untrack(e)
+		}
+	}()
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.release(e)
+
+	if _, ok := f.pending[e]; !ok {
+		return
+	}
+
+	// Untracked after unlocking the in-flight electrons
+	conductorAs(f.conductors[e], &u)
+
+	f.uncount(e)
+	delete(f.pending, e)
+	delete(f.conductors, e)
+	delete(f.migrated, e)
+
+	if !f.draining {
+		return
+	}
+
+	f.drained++
+	if len(f.pending) > 0 {
+		return
+	}
+
+	select {
+	case <-f.empty:
+	default:
+		close(f.empty)
+	}
+}
+
+// drain marks the start of a shutdown and returns a channel which is
+// closed once every tracked electron is done
+func (f *inflight) drain() <-chan struct{} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.empty = make(chan struct{})
+	if f.draining || len(f.pending) == 0 {
+		close(f.empty)
+	}
+
+	f.draining = true
+
+	return f.empty
+}
+
+// isDraining indicates if the atomizer is shutting down
+func (f *inflight) isDraining() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.draining
+}
+
+// stranded is an electron which was still in flight when the
+// shutdown context expired
+type stranded struct {
+	electron  *Electron
+	conductor Conductor
+
+	// abort cancels and completes the electron
+	// once it has started processing
+	abort context.CancelFunc
+}
+
+// abandon stops tracking the electrons which are still in flight and
+// returns the number of drained electrons along with the abandoned
+// electrons so they can be cancelled and completed
+func (f *inflight) abandon() (int, []stranded) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.abandoned = true
+
+	var out []stranded
+	for e, abort := range f.pending {
+		out = append(out, stranded{
+			electron:  e,
+			conductor: f.conductors[e],
+			abort:     abort,
+		})
+
+		f.release(e)
+		f.uncount(e)
+		delete(f.pending, e)
+		delete(f.conductors, e)
+	}
+
+	return f.drained, out
+}
@@ -0,0 +1,250 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// blockatom processes until the context is cancelled
+type blockatom struct{}
+
+func (*blockatom) Process(
+	ctx context.Context,
+	conductor Conductor,
+	electron *Electron,
+) ([]byte, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+// sleepatom processes for a short fixed duration
+type sleepatom struct{}
+
+func (*sleepatom) Process(
+	ctx context.Context,
+	conductor Conductor,
+	electron *Electron,
+) ([]byte, error) {
+	time.Sleep(time.Millisecond * 50)
+	return nil, nil
+}
+
+func TestAtomizer_Shutdown(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	rec, a := recHarness(ctx, t, &blockatom{}, &sleepatom{})
+
+	eventually(t, time.Second, func() bool {
+		return a.route(newElectron(ID(blockatom{}), nil)) != nil &&
+			a.route(newElectron(ID(sleepatom{}), nil)) != nil
+	})
+
+	long := newElectron(ID(blockatom{}), nil)
+	electrons := []*Electron{
+		long,
+		newElectron(ID(sleepatom{}), nil),
+		newElectron(ID(sleepatom{}), nil),
+	}
+
+	for _, e := range electrons {
+		if _, err := rec.Send(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	eventually(t, time.Second, func() bool {
+		a.inflight.mu.Lock()
+		defer a.inflight.mu.Unlock()
+
+		return len(a.inflight.pending) == len(electrons)
+	})
+
+	sctx, scancel := context.WithTimeout(ctx, time.Millisecond*500)
+	defer scancel()
+
+	report, err := a.Shutdown(sctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+
+	if report.DrainedElectrons != 2 {
+		t.Fatalf("expected 2 drained, got %v", report.DrainedElectrons)
+	}
+
+	if len(report.AbandonedElectrons) != 1 ||
+		report.AbandonedElectrons[0] != long.ID {
+		t.Fatalf(
+			"expected [%s] abandoned, got %v",
+			long.ID,
+			report.AbandonedElectrons,
+		)
+	}
+
+	if report.ConductorsStopped != 1 {
+		t.Fatalf("expected 1 conductor, got %v", report.ConductorsStopped)
+	}
+
+	if report.Duration <= 0 {
+		t.Fatal("expected shutdown duration")
+	}
+}
+
+func TestAtomizer_Shutdown_Idle(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	_, a := recHarness(ctx, t)
+
+	report, err := a.Shutdown(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report.DrainedElectrons != 0 || len(report.AbandonedElectrons) != 0 {
+		t.Fatalf("expected empty report, got %+v", report)
+	}
+
+	select {
+	case <-a.ctx.Done():
+	default:
+		t.Fatal("expected atomizer context to be closed")
+	}
+}
+
+func TestAtomizer_Shutdown_CompletesStarted(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	rec, a := recHarness(ctx, t, &sleepatom{})
+
+	eventually(t, time.Second, func() bool {
+		return a.route(newElectron(ID(sleepatom{}), nil)) != nil
+	})
+
+	e := newElectron(ID(sleepatom{}), nil)
+	if _, err := rec.Send(ctx, e); err != nil {
+		t.Fatal(err)
+	}
+
+	// Wait for the electron to start processing
+	eventually(t, time.Second, func() bool {
+		a.inflight.mu.Lock()
+		defer a.inflight.mu.Unlock()
+
+		return a.inflight.pending[e] != nil
+	})
+
+	report, err := a.Shutdown(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report.DrainedElectrons != 1 || len(report.AbandonedElectrons) != 0 {
+		t.Fatalf("expected 1 drained electron, got %+v", report)
+	}
+
+	select {
+	case p := <-rec.completions:
+		if p.ElectronID != e.ID || p.Error != nil {
+			t.Fatalf("expected successful completion, got %+v", p)
+		}
+	default:
+		t.Fatal("expected the electron to complete before shutdown returned")
+	}
+}
+
+func TestAtomizer_Shutdown_CompletesAbandoned(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	rec, a := recHarness(ctx, t, &blockatom{})
+
+	eventually(t, time.Second, func() bool {
+		return a.route(newElectron(ID(blockatom{}), nil)) != nil
+	})
+
+	// The first electron blocks the atom while the
+	// second waits in distribute without starting
+	electrons := []*Electron{
+		newElectron(ID(blockatom{}), nil),
+		newElectron(ID(blockatom{}), nil),
+	}
+
+	for _, e := range electrons {
+		if _, err := rec.Send(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	eventually(t, time.Second, func() bool {
+		a.inflight.mu.Lock()
+		defer a.inflight.mu.Unlock()
+
+		return len(a.inflight.pending) == len(electrons)
+	})
+
+	sctx, scancel := context.WithTimeout(ctx, time.Millisecond*100)
+	defer scancel()
+
+	report, err := a.Shutdown(sctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+
+	if len(report.AbandonedElectrons) != len(electrons) {
+		t.Fatalf("expected 2 abandoned, got %v", report.AbandonedElectrons)
+	}
+
+	completed := make(map[string]bool)
+	for range electrons {
+		select {
+		case p := <-rec.completions:
+			if !errors.Is(p.Error, ErrShutdown) {
+				t.Fatalf("expected shutdown error, got %v", p.Error)
+			}
+
+			completed[p.ElectronID] = true
+		default:
+			t.Fatal("expected abandoned electrons to be completed")
+		}
+	}
+
+	for _, e := range electrons {
+		if !completed[e.ID] {
+			t.Fatalf("expected %s to be completed", e.ID)
+		}
+	}
+
+	select {
+	case p := <-rec.completions:
+		t.Fatalf("unexpected completion %+v", p)
+	case <-time.After(time.Millisecond * 50):
+	}
+}
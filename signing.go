@@ -0,0 +1,112 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"errors"
+)
+
+// ErrInvalidSignature is returned when the signature of a result
+// does not verify against the public key of its signer
+var ErrInvalidSignature = errors.New("invalid result signature")
+
+// KeyResolver resolves the public key of the node which signed a result
+type KeyResolver interface {
+	PublicKey(signer string) (ed25519.PublicKey, error)
+}
+
+// KeyResolverFunc adapts a func to a KeyResolver
+type KeyResolverFunc func(signer string) (ed25519.PublicKey, error)
+
+// PublicKey calls the func
+func (f KeyResolverFunc) PublicKey(signer string) (ed25519.PublicKey, error) {
+	return f(signer)
+}
+
+// resultSigner is the identity and key the node signs results with
+type resultSigner struct {
+	id  string
+	key ed25519.PrivateKey
+}
+
+// WithResultSigning signs the result of every electron processed by this
+// node with the key before it is completed to the conductor. The signer
+// is included with the signature so the sender can resolve the public key
+// of the node to verify the result with Properties.Verify.
+func WithResultSigning(signer string, key ed25519.PrivateKey) Option {
+	return func(a *atomizer) error {
+		if signer == "" {
+			return simple("empty result signer", nil)
+		}
+
+		if len(key) != ed25519.PrivateKeySize {
+			return simple("invalid result signing key", nil)
+		}
+
+		a.signer = &resultSigner{signer, key}
+		return nil
+	}
+}
+
+// Sign signs the result of the properties with the key and records
+// the signer so the signature can be verified by the receiver
+func (p *Properties) Sign(signer string, key ed25519.PrivateKey) error {
+	if len(key) != ed25519.PrivateKeySize {
+		return simple("invalid result signing key", nil)
+	}
+
+	p.Signer = signer
+	p.Signature = ed25519.Sign(key, p.signed())
+
+	return nil
+}
+
+// Verify verifies the signature of the properties against the public
+// key of the signer resolved through the resolver
+func (p *Properties) Verify(resolver KeyResolver) error {
+	if len(p.Signature) == 0 {
+		return &Error{
+			Event: &Event{
+				Message:    "result not signed",
+				ElectronID: p.ElectronID,
+				AtomID:     p.AtomID,
+			},
+			Internal: ErrInvalidSignature,
+		}
+	}
+
+	key, err := resolver.PublicKey(p.Signer)
+	if err != nil {
+		return simple("error resolving signer key "+p.Signer, err)
+	}
+
+	if len(key) != ed25519.PublicKeySize ||
+		!ed25519.Verify(key, p.signed(), p.Signature) {
+		return &Error{
+			Event: &Event{
+				Message:    "result signature verification failed",
+				ElectronID: p.ElectronID,
+				AtomID:     p.AtomID,
+			},
+			Internal: ErrInvalidSignature,
+		}
+	}
+
+	return nil
+}
+
+// signed returns the signed content of the properties which binds the
+// result to the electron and atom that produced it
+func (p *Properties) signed() []byte {
+	return bytes.Join([][]byte{
+		[]byte(p.Signer),
+		[]byte(p.ElectronID),
+		[]byte(p.AtomID),
+		p.Result,
+	}, []byte{0})
+}
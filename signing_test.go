@@ -0,0 +1,89 @@
+package engine
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAtomizer_ResultSigning(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec, _ := recHarness(
+		ctx,
+		t,
+		WithResultSigning("node-1", priv),
+		&returner{},
+	)
+
+	payload, err := json.Marshal(&printerdata{Message: "42"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = rec.Send(ctx, newElectron(ID(returner{}), payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := rec.next(ctx, t)
+	if p.Error != nil {
+		t.Fatal(p.Error)
+	}
+
+	// Verify the signature survives the wire format
+	data, err := json.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	received := &Properties{}
+	if err = json.Unmarshal(data, received); err != nil {
+		t.Fatal(err)
+	}
+
+	resolver := KeyResolverFunc(func(signer string) (ed25519.PublicKey, error) {
+		if signer != "node-1" {
+			return nil, errors.New("unknown signer")
+		}
+
+		return pub, nil
+	})
+
+	if err = received.Verify(resolver); err != nil {
+		t.Fatalf("expected signature to verify, got %s", err)
+	}
+
+	received.Result = []byte("43")
+	if err = received.Verify(resolver); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("expected invalid signature, got %v", err)
+	}
+}
+
+func TestProperties_Verify_Unsigned(t *testing.T) {
+	p := &Properties{ElectronID: "electron", Result: []byte("1")}
+
+	err := p.Verify(KeyResolverFunc(func(string) (ed25519.PublicKey, error) {
+		return nil, errors.New("unexpected resolve")
+	}))
+
+	if !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("expected invalid signature, got %v", err)
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import (
+	"sync/atomic"
+)
+
+// EventSink receives the events emitted by the atomizer (i.e. a logger,
+// metrics exporter or dashboard)
+type EventSink interface {
+	Publish(event interface{})
+}
+
+// EventSinkFunc adapts a func to an EventSink
+type EventSinkFunc func(event interface{})
+
+// Publish calls the func with the event
+func (f EventSinkFunc) Publish(event interface{}) {
+	f(event)
+}
+
+// sink is a registered event sink with its own event queue
+type sink struct {
+	sink    EventSink
+	queue   chan interface{}
+	dropped uint64
+}
+
+// AddEventSink registers a sink to receive the events emitted by the
+// atomizer. Each sink has its own queue of the supplied buffer size which
+// is drained independently of the other sinks so that a slow sink does not
+// block the atomizer or the other sinks. When the queue of a sink is full
+// new events are dropped for that sink only.
+func (a *atomizer) AddEventSink(s EventSink, buffer int) error {
+	if s == nil {
+		return simple("nil event sink", nil)
+	}
+
+	if buffer < 0 {
+		buffer = 0
+	}
+
+	reg := &sink{
+		sink:  s,
+		queue: make(chan interface{}, buffer),
+	}
+
+	a.sinksMu.Lock()
+	a.sinks = append(a.sinks, reg)
+	a.sinksMu.Unlock()
+
+	a.routine(func() {
+		a.drain(reg)
+	})
+
+	return nil
+}
+
+// drain publishes the queued events to the sink
+func (a *atomizer) drain(s *sink) {
+	for {
+		select {
+		case <-a.ctx.Done():
+			return
+		case e := <-s.queue:
+			s.sink.Publish(e)
+		}
+	}
+}
+
+// publish queues the event for each of the registered sinks
+func (a *atomizer) publish(e interface{}) {
+	a.sinksMu.RLock()
+	defer a.sinksMu.RUnlock()
+
+	for _, s := range a.sinks {
+		select {
+		case s.queue <- e:
+		default:
+			atomic.AddUint64(&s.dropped, 1)
+		}
+	}
+}
+
+// hasSinks indicates if any event sinks are registered
+func (a *atomizer) hasSinks() bool {
+	a.sinksMu.RLock()
+	defer a.sinksMu.RUnlock()
+
+	return len(a.sinks) > 0
+}
@@ -0,0 +1,72 @@
+package engine
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAtomizer_AddEventSink(t *testing.T) {
+	_, cancel, a := unexpHarness(t)
+	defer cancel()
+
+	release := make(chan struct{})
+	var slow, fast int64
+
+	err := a.AddEventSink(EventSinkFunc(func(interface{}) {
+		<-release
+		atomic.AddInt64(&slow, 1)
+	}), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = a.AddEventSink(EventSinkFunc(func(interface{}) {
+		atomic.AddInt64(&fast, 1)
+	}), 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	total := 50
+	for i := 0; i < total; i++ {
+		a.event(func() interface{} {
+			return makeEvent("test event")
+		})
+	}
+
+	eventually(t, time.Second, func() bool {
+		return atomic.LoadInt64(&fast) == int64(total)
+	})
+
+	close(release)
+
+	// The slow sink can hold at most one event in flight
+	// and one queued event, the rest are dropped
+	eventually(t, time.Second, func() bool {
+		return atomic.LoadInt64(&slow) > 0
+	})
+
+	time.Sleep(time.Millisecond * 10)
+
+	if n := atomic.LoadInt64(&slow); n > 2 {
+		t.Fatalf("expected slow sink to drop events, received %v", n)
+	}
+
+	a.sinksMu.RLock()
+	dropped := atomic.LoadUint64(&a.sinks[0].dropped)
+	a.sinksMu.RUnlock()
+
+	if dropped < uint64(total-2) {
+		t.Fatalf("expected at least %v dropped events, got %v", total-2, dropped)
+	}
+}
+
+func TestAtomizer_AddEventSink_Nil(t *testing.T) {
+	_, cancel, a := unexpHarness(t)
+	defer cancel()
+
+	if err := a.AddEventSink(nil, 1); err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import (
+	"context"
+	"time"
+)
+
+// WithConductorSmoothing smooths the electrons received from the conductor
+// with the ID to rps electrons per second so that bursts are flattened
+// into a steady rate of distribution, reducing contention spikes
+// downstream. Unlike a rate limit (see WithAtomRateLimit) bursts of up to
+// burst electrons are admitted immediately and no electron is rejected,
+// the receipt of electrons from the conductor is paused until the bucket
+// refills.
+func WithConductorSmoothing(conductorID string, rps float64, burst int) Option {
+	return func(a *atomizer) error {
+		if conductorID == "" {
+			return simple("empty smoothing conductor id", nil)
+		}
+
+		if rps <= 0 || burst < 1 {
+			return simple("invalid smoothing for "+conductorID, nil)
+		}
+
+		if a.smoothers == nil {
+			a.smoothers = make(map[string]*limiter)
+		}
+
+		a.smoothers[conductorID] = &limiter{
+			rate:   rps,
+			burst:  float64(burst),
+			tokens: float64(burst),
+		}
+
+		return nil
+	}
+}
+
+// smooth waits until the smoother of the conductor admits the instance
+// and returns false if the context closed while waiting
+func (a *atomizer) smooth(ctx context.Context, inst instance) bool {
+	l, ok := a.smoothers[ID(inst.conductor)]
+	if !ok {
+		return true
+	}
+
+	delay, _ := l.reserve(time.Now(), true)
+	if delay == 0 {
+		return true
+	}
+
+	t := time.NewTimer(delay)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
@@ -0,0 +1,81 @@
+package engine
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestAtomizer_ConductorSmoothing(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	const (
+		burst = 5
+		total = 20
+		rps   = 50
+	)
+
+	rec, a := recHarness(
+		ctx,
+		t,
+		WithConductorSmoothing(ID(newRecorder()), rps, burst),
+		&noopatom{},
+	)
+
+	eventually(t, time.Second, func() bool {
+		return a.route(newElectron(ID(noopatom{}), nil)) != nil
+	})
+
+	go func() {
+		for i := 0; i < total; i++ {
+			if _, err := rec.Send(ctx, newElectron(ID(noopatom{}), nil)); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	}()
+
+	starts := make([]time.Time, 0, total)
+	for i := 0; i < total; i++ {
+		starts = append(starts, rec.next(ctx, t).Start)
+	}
+
+	sort.Slice(starts, func(i, j int) bool {
+		return starts[i].Before(starts[j])
+	})
+
+	// The burst is admitted at once
+	if elapsed := starts[burst-1].Sub(starts[0]); elapsed > time.Second/rps {
+		t.Fatalf("expected burst to be admitted immediately, took %s", elapsed)
+	}
+
+	// The remainder is admitted at the refill rate
+	expected := time.Second * (total - burst) / rps
+	if elapsed := starts[total-1].Sub(starts[0]); elapsed < expected*3/4 {
+		t.Fatalf("expected smoothed admission over %s, took %s", expected, elapsed)
+	}
+}
+
+func TestWithConductorSmoothing_Invalid(t *testing.T) {
+	tests := map[string]Option{
+		"empty conductor": WithConductorSmoothing("", 1, 1),
+		"zero rate":       WithConductorSmoothing("conductor", 0, 1),
+		"zero burst":      WithConductorSmoothing("conductor", 1, 0),
+	}
+
+	for name, opt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := opt(&atomizer{}); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
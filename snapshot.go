@@ -0,0 +1,100 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Snapshot stops the atomizer from receiving electrons from its
+// conductors and returns the electrons which were received but have not
+// yet been bonded to an atom so that they can be submitted to another
+// atomizer, such as when a node is scaled down. The returned electrons
+// are no longer processed or completed by this atomizer. Electrons which
+// are already processing are not included and can be drained using
+// Shutdown.
+func (a *atomizer) Snapshot() ([]Electron, error) {
+	if a.ctx.Err() != nil {
+		return nil, simple("atomizer closed", a.ctx.Err())
+	}
+
+	a.inflight.stop()
+	stopped := a.stopIntake()
+
+	queued := a.inflight.migrate()
+
+	electrons := make([]Electron, 0, len(queued))
+	for _, e := range queued {
+		electrons = append(electrons, *e)
+	}
+
+	sort.Slice(electrons, func(i, j int) bool {
+		return electrons[i].ID < electrons[j].ID
+	})
+
+	a.event(func() interface{} {
+		return makeEvent(fmt.Sprintf(
+			"snapshot of %v queued electrons, %v conductors stopped",
+			len(electrons),
+			stopped,
+		))
+	})
+
+	return electrons, nil
+}
+
+// stop stops the intake of new conductors
+func (f *inflight) stop() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.stopped = true
+}
+
+// isStopped indicates if the intake of new conductors was stopped
+func (f *inflight) isStopped() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.stopped
+}
+
+// migrate marks the tracked electrons which have not been bonded as
+// migrated so they are not processed and returns them
+func (f *inflight) migrate() []*Electron {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.migrated == nil {
+		f.migrated = make(map[*Electron]struct{})
+	}
+
+	var queued []*Electron
+	for e, cancel := range f.pending {
+		if cancel != nil {
+			continue
+		}
+
+		if _, ok := f.migrated[e]; ok {
+			continue
+		}
+
+		f.migrated[e] = struct{}{}
+		queued = append(queued, e)
+	}
+
+	return queued
+}
+
+// isMigrated indicates if the electron was migrated
+func (f *inflight) isMigrated(e *Electron) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	_, ok := f.migrated[e]
+	return ok
+}
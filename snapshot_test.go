@@ -0,0 +1,141 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestAtomizer_Snapshot(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	gaterelease = make(chan struct{})
+	rec, a := recHarness(ctx, t, &gateatom{})
+
+	atomID := ID(gateatom{})
+	eventually(t, time.Second, func() bool {
+		return a.route(newElectron(atomID, nil)) != nil
+	})
+
+	bonded := newElectron(atomID, nil)
+	if _, err := rec.Send(ctx, bonded); err != nil {
+		t.Fatal(err)
+	}
+
+	// Wait for the first electron to be bonded to the atom
+	eventually(t, time.Second, func() bool {
+		a.inflight.mu.Lock()
+		defer a.inflight.mu.Unlock()
+
+		return a.inflight.pending[bonded] != nil
+	})
+
+	queued := map[string]bool{}
+	for i := 0; i < 2; i++ {
+		e := newElectron(atomID, nil)
+		queued[e.ID] = true
+
+		if _, err := rec.Send(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	eventually(t, time.Second, func() bool {
+		a.inflight.mu.Lock()
+		defer a.inflight.mu.Unlock()
+
+		return len(a.inflight.pending) == len(queued)+1
+	})
+
+	snapshot, err := a.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(snapshot) != len(queued) {
+		t.Fatalf("expected %v queued electrons, got %v", len(queued), snapshot)
+	}
+
+	for _, e := range snapshot {
+		if !queued[e.ID] {
+			t.Fatalf("unexpected electron %s in snapshot", e.ID)
+		}
+	}
+
+	a.conductorsMu.RLock()
+	conducting := len(a.conducting)
+	a.conductorsMu.RUnlock()
+
+	if conducting != 0 {
+		t.Fatalf("expected intake stopped, got %v conductors", conducting)
+	}
+
+	close(gaterelease)
+
+	if p := rec.next(ctx, t); p.ElectronID != bonded.ID {
+		t.Fatalf("expected %s, got %s", bonded.ID, p.ElectronID)
+	}
+
+	// The snapshotted electrons are not processed by this atomizer
+	select {
+	case p := <-rec.completions:
+		t.Fatalf("unexpected completion %+v", p)
+	case <-time.After(time.Millisecond * 50):
+	}
+
+	eventually(t, time.Second, func() bool {
+		a.inflight.mu.Lock()
+		defer a.inflight.mu.Unlock()
+
+		return len(a.inflight.pending) == 0
+	})
+
+	// Replay the snapshot on another atomizer
+	mizer, err := Atomize(ctx, &gateatom{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = mizer.Exec(); err != nil {
+		t.Fatal(err)
+	}
+
+	b, _ := mizer.(*atomizer)
+	eventually(t, time.Second, func() bool {
+		return b.route(newElectron(atomID, nil)) != nil
+	})
+
+	results := make(chan Properties, len(snapshot))
+	for i := range snapshot {
+		err = b.SubmitWithCallback(
+			ctx,
+			&snapshot[i],
+			func(p Properties, err error) {
+				results <- p
+			},
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for range snapshot {
+		select {
+		case <-ctx.Done():
+			t.Fatal("expected replayed electrons to complete")
+		case p := <-results:
+			if !queued[p.ElectronID] || p.Error != nil {
+				t.Fatalf("unexpected replay result %+v", p)
+			}
+
+			delete(queued, p.ElectronID)
+		}
+	}
+}
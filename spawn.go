@@ -0,0 +1,92 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import "context"
+
+type parentKey struct{}
+
+// parent is the electron being processed by an atom along with the
+// atomizer processing it so the atom can submit child electrons
+type parent struct {
+	a        *atomizer
+	electron *Electron
+}
+
+// withParent adds the electron being processed to the context of the
+// atom instance
+func withParent(
+	ctx context.Context,
+	a *atomizer,
+	electron *Electron,
+) context.Context {
+	return context.WithValue(ctx, parentKey{}, &parent{a, electron})
+}
+
+// Submit submits a child electron of the electron being processed by an
+// atom using the context passed to the Process method of the atom. The
+// callback is invoked as with SubmitWithCallback.
+//
+// The child inherits the Priority and CorrelationID of the parent along
+// with any Tags of the parent which the child does not set. Fields set
+// on the child are not overridden. When the parent has no CorrelationID
+// the ID of the parent is used so the children can be correlated to it.
+// The parent is appended to the Lineage of the child.
+func Submit(
+	ctx context.Context,
+	child *Electron,
+	callback func(Properties, error),
+) error {
+	if ctx == nil {
+		return simple("nil context", nil)
+	}
+
+	p, ok := ctx.Value(parentKey{}).(*parent)
+	if !ok {
+		return simple("no electron being processed in context", nil)
+	}
+
+	if child == nil {
+		return simple("nil child electron", nil)
+	}
+
+	inherit(p.electron, child)
+
+	return p.a.SubmitWithCallback(ctx, child, callback)
+}
+
+// inherit copies the fields of the parent which are not set on the child
+// and records the parent in the lineage of the child
+func inherit(parent, child *Electron) {
+	if parent == nil {
+		return
+	}
+
+	child.Lineage = descend(parent)
+
+	if child.Priority == 0 {
+		child.Priority = parent.Priority
+	}
+
+	if child.CorrelationID == "" {
+		child.CorrelationID = parent.CorrelationID
+		if child.CorrelationID == "" {
+			child.CorrelationID = parent.ID
+		}
+	}
+
+	for k, v := range parent.Tags {
+		if _, exists := child.Tags[k]; exists {
+			continue
+		}
+
+		if child.Tags == nil {
+			child.Tags = make(map[string]string, len(parent.Tags))
+		}
+
+		child.Tags[k] = v
+	}
+}
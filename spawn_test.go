@@ -0,0 +1,222 @@
+package engine
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// spawned receives the children submitted by spawnatom along with
+// the error of their processing
+var spawned chan *Electron
+var spawnedErrs chan error
+
+type spawnatom struct{}
+
+func (*spawnatom) Process(
+	ctx context.Context,
+	conductor Conductor,
+	electron *Electron,
+) ([]byte, error) {
+	child := &Electron{
+		SenderID: "spawnatom",
+		ID:       uuid.New().String(),
+		AtomID:   ID(noopatom{}),
+		Tags:     map[string]string{"child": "true"},
+	}
+
+	err := Submit(ctx, child, func(p Properties, err error) {
+		spawnedErrs <- err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	spawned <- child
+	return nil, nil
+}
+
+func TestSubmit_Inherit(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	spawned = make(chan *Electron, 1)
+	spawnedErrs = make(chan error, 1)
+
+	rec, a := recHarness(ctx, t, &spawnatom{}, &noopatom{})
+
+	eventually(t, time.Second, func() bool {
+		return a.route(newElectron(ID(spawnatom{}), nil)) != nil &&
+			a.route(newElectron(ID(noopatom{}), nil)) != nil
+	})
+
+	e := newElectron(ID(spawnatom{}), nil)
+	e.Priority = 10
+	e.CorrelationID = "workflow"
+	e.Tags = map[string]string{"urgent": "true", "child": "false"}
+
+	if _, err := rec.Send(ctx, e); err != nil {
+		t.Fatal(err)
+	}
+
+	if p := rec.next(ctx, t); p.Error != nil {
+		t.Fatalf("unexpected error %v", p.Error)
+	}
+
+	var child *Electron
+	select {
+	case <-ctx.Done():
+		t.Fatal("expected child electron")
+	case child = <-spawned:
+	}
+
+	if child.Priority != e.Priority {
+		t.Fatalf("expected priority %v, got %v", e.Priority, child.Priority)
+	}
+
+	if child.CorrelationID != e.CorrelationID {
+		t.Fatalf(
+			"expected correlation %s, got %s",
+			e.CorrelationID,
+			child.CorrelationID,
+		)
+	}
+
+	if child.Tags["urgent"] != "true" || child.Tags["child"] != "true" {
+		t.Fatalf("unexpected tags %v", child.Tags)
+	}
+
+	select {
+	case <-ctx.Done():
+		t.Fatal("expected child completion")
+	case err := <-spawnedErrs:
+		if err != nil {
+			t.Fatalf("unexpected child error %v", err)
+		}
+	}
+}
+
+func TestInherit_Overrides(t *testing.T) {
+	parent := &Electron{ID: "parent", Priority: 10}
+	child := &Electron{Priority: 1, CorrelationID: "other"}
+
+	inherit(parent, child)
+
+	if child.Priority != 1 || child.CorrelationID != "other" {
+		t.Fatalf("expected child fields to be kept, got %+v", child)
+	}
+
+	child = &Electron{}
+	inherit(parent, child)
+
+	if child.CorrelationID != parent.ID {
+		t.Fatalf("expected parent ID correlation, got %s", child.CorrelationID)
+	}
+}
+
+func TestSubmit_NoParent(t *testing.T) {
+	err := Submit(context.TODO(), &Electron{}, func(Properties, error) {})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestSubmit_Priority(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	orderrelease = make(chan struct{})
+	release := orderrelease
+	var once sync.Once
+	open := func() {
+		once.Do(func() {
+			close(release)
+		})
+	}
+	t.Cleanup(open)
+
+	ordered = make(chan string, 10)
+
+	rec, a := recHarness(
+		ctx,
+		t,
+		WithConductorPriority("queue", 0),
+		&orderatom{},
+	)
+
+	atomID := ID(orderatom{})
+	eventually(t, time.Second, func() bool {
+		return a.route(newElectron(atomID, nil)) != nil
+	})
+
+	send := func(name string) {
+		if _, err := rec.Send(ctx, newElectron(atomID, []byte(name))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Occupy the atom, distribute and the dispatch loop so the
+	// following electrons contend in the queue
+	for _, name := range []string{"blocker", "low1", "low2"} {
+		send(name)
+		eventually(t, time.Second, func() bool {
+			return a.queue.len() == 0
+		})
+
+		// Allow the electron to move past the queue
+		time.Sleep(time.Millisecond * 20)
+	}
+
+	send("low3")
+	send("low4")
+
+	eventually(t, time.Second, func() bool {
+		return a.queue.len() == 2
+	})
+
+	parent := newElectron(atomID, nil)
+	parent.Priority = 10
+
+	child := newElectron(atomID, []byte("child"))
+	err := Submit(
+		withParent(ctx, a, parent),
+		child,
+		func(Properties, error) {},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	eventually(t, time.Second, func() bool {
+		return a.queue.len() == 3
+	})
+
+	open()
+
+	expected := []string{"blocker", "low1", "low2", "child", "low3", "low4"}
+	for _, name := range expected {
+		select {
+		case <-ctx.Done():
+			t.Fatalf("expected %s to be processed", name)
+		case got := <-ordered:
+			if got != name {
+				t.Fatalf("expected %s, got %s", name, got)
+			}
+		}
+	}
+}
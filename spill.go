@@ -0,0 +1,234 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/gob"
+	"io/ioutil"
+	"os"
+	"sync"
+)
+
+func init() {
+	gob.Register(&Lifecycle{})
+}
+
+// WithEventSpill spills events to a file in the directory when the buffer
+// of the events channel (see Events) is full rather than blocking the
+// atomizer. Spilled events are replayed onto the events channel in order
+// once the consumer catches up so no events are lost. The spill file is
+// bounded to maxBytes; once full the atomizer blocks on emitting events
+// until the spilled events are replayed. Events MUST be encodable using
+// encoding/gob to be spilled; events which cannot be encoded are dropped
+// and emitted as errors.
+func WithEventSpill(dir string, maxBytes int64) Option {
+	return func(a *atomizer) error {
+		if maxBytes < 1 {
+			return simple("event spill size must be positive", nil)
+		}
+
+		file, err := ioutil.TempFile(dir, "atomizer-events-*")
+		if err != nil {
+			return simple("unable to create event spill file", err)
+		}
+
+		a.spill = &spill{
+			file:  file,
+			max:   maxBytes,
+			wake:  make(chan struct{}, 1),
+			space: make(chan struct{}, 1),
+		}
+
+		return nil
+	}
+}
+
+// spilled is the encoded form of a spilled event
+type spilled struct {
+	Event interface{}
+}
+
+// spill is a bounded on-disk buffer of the events waiting for
+// the events channel
+type spill struct {
+	mu      sync.Mutex
+	file    *os.File
+	max     int64
+	written int64
+	read    int64
+	pending int
+
+	// wake signals replay of spilled events and space
+	// signals the spill file was emptied
+	wake  chan struct{}
+	space chan struct{}
+}
+
+// put sends the event to the events channel when there are no spilled
+// events and the channel has capacity, otherwise the event is spilled
+func (s *spill) put(
+	ctx context.Context,
+	events chan<- interface{},
+	e interface{},
+) error {
+	var record []byte
+	for {
+		s.mu.Lock()
+		if s.pending == 0 {
+			select {
+			case events <- e:
+				s.mu.Unlock()
+				return nil
+			default:
+			}
+		}
+
+		if record == nil {
+			s.mu.Unlock()
+
+			var err error
+			if record, err = encode(e); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if s.written+int64(len(record)) <= s.max || s.pending == 0 {
+			break
+		}
+		s.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-s.space:
+		}
+	}
+	defer s.mu.Unlock()
+
+	if _, err := s.file.WriteAt(record, s.written); err != nil {
+		return err
+	}
+
+	s.written += int64(len(record))
+	s.pending++
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+
+	return nil
+}
+
+// encode encodes the event as a length prefixed record
+func encode(e interface{}) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	buf.Write(make([]byte, 4))
+	if err := gob.NewEncoder(buf).Encode(&spilled{e}); err != nil {
+		return nil, err
+	}
+
+	record := buf.Bytes()
+	binary.BigEndian.PutUint32(record, uint32(len(record)-4))
+
+	return record, nil
+}
+
+// next decodes the oldest spilled event
+func (s *spill) next() (interface{}, int64, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.pending == 0 {
+		return nil, 0, false, nil
+	}
+
+	size := make([]byte, 4)
+	if _, err := s.file.ReadAt(size, s.read); err != nil {
+		return nil, 0, false, err
+	}
+
+	data := make([]byte, binary.BigEndian.Uint32(size))
+	if _, err := s.file.ReadAt(data, s.read+4); err != nil {
+		return nil, 0, false, err
+	}
+
+	var out spilled
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&out); err != nil {
+		return nil, 0, false, err
+	}
+
+	return out.Event, int64(len(data) + 4), true, nil
+}
+
+// advance marks the oldest spilled event as replayed, emptying the
+// spill file once every spilled event is replayed
+func (s *spill) advance(n int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.read += n
+	s.pending--
+	if s.pending > 0 {
+		return nil
+	}
+
+	s.read, s.written = 0, 0
+	select {
+	case s.space <- struct{}{}:
+	default:
+	}
+
+	return s.file.Truncate(0)
+}
+
+// replay pushes the spilled events onto the events channel in order
+func (a *atomizer) replay() {
+	defer func() {
+		_ = a.spill.file.Close()
+		_ = os.Remove(a.spill.file.Name())
+	}()
+
+	for {
+		e, n, ok, err := a.spill.next()
+		if err != nil {
+			a.err(func() error {
+				return simple("unable to replay spilled event", err)
+			})
+
+			return
+		}
+
+		if !ok {
+			select {
+			case <-a.ctx.Done():
+				return
+			case <-a.spill.wake:
+			}
+
+			continue
+		}
+
+		select {
+		case <-a.ctx.Done():
+			return
+		case a.events <- e:
+		}
+
+		if err = a.spill.advance(n); err != nil {
+			a.err(func() error {
+				return simple("unable to advance event spill", err)
+			})
+
+			return
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package engine
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestAtomizer_EventSpill(t *testing.T) {
+	tests := map[string]int64{
+		"unbounded": 1 << 20,
+		"bounded":   256,
+	}
+
+	for name, size := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+			defer cancel()
+
+			a := &atomizer{ctx: ctx, cancel: cancel}
+			if err := WithEventSpill(t.TempDir(), size)(a); err != nil {
+				t.Fatal(err)
+			}
+
+			events := a.Events(1)
+			go a.replay()
+
+			const total = 100
+
+			// Produce the events without a consumer
+			produced := make(chan struct{})
+			go func() {
+				defer close(produced)
+
+				for i := 0; i < total; i++ {
+					msg := strconv.Itoa(i)
+					a.event(func() interface{} {
+						return &Event{Message: msg}
+					})
+				}
+			}()
+
+			if size > 1<<10 {
+				select {
+				case <-ctx.Done():
+					t.Fatal("expected events to spill without blocking")
+				case <-produced:
+				}
+			}
+
+			for i := 0; i < total; i++ {
+				var e interface{}
+				select {
+				case <-ctx.Done():
+					t.Fatalf("expected event %v", i)
+				case e = <-events:
+				}
+
+				event, ok := e.(*Event)
+				if !ok || event.Message != strconv.Itoa(i) {
+					t.Fatalf("expected event %v, got %v", i, e)
+				}
+			}
+
+			<-produced
+		})
+	}
+}
+
+func TestWithEventSpill_Invalid(t *testing.T) {
+	if err := WithEventSpill(t.TempDir(), 0)(&atomizer{}); err == nil {
+		t.Fatal("expected error")
+	}
+}
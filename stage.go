@@ -0,0 +1,62 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import (
+	"sync"
+	"time"
+)
+
+// Stage is the timing of a single stage of the atomizer pipeline for
+// an electron. Stage events ("electron received", "electron distributed",
+// "pushing electron to atom" and "atom execution complete") carry a stage
+// so that consumers can separate time spent queued in the atomizer from
+// time spent processing in the atom.
+type Stage struct {
+	// Time is when the stage was reached
+	Time time.Time `json:"time"`
+
+	// Elapsed is the time since the previous stage of the same
+	// electron. The first stage of an electron has no elapsed time.
+	Elapsed time.Duration `json:"elapsed"`
+}
+
+// timing tracks the stages of a single electron as it moves through
+// the atomizer. Stages are reached from different routines so access
+// is synchronized.
+type timing struct {
+	mu   sync.Mutex
+	last time.Time
+}
+
+// stage marks a new stage for the electron and returns its timing. A nil
+// timing returns a nil stage so that instances created outside of the
+// conductor receive loop do not need to track stages.
+func (t *timing) stage() *Stage {
+	if t == nil {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+
+	// Stages may be marked from different routines so the
+	// time is clamped to keep the elapsed times non-negative
+	if now.Before(t.last) {
+		now = t.last
+	}
+
+	s := &Stage{Time: now}
+	if !t.last.IsZero() {
+		s.Elapsed = now.Sub(t.last)
+	}
+
+	t.last = now
+
+	return s
+}
@@ -0,0 +1,87 @@
+package engine
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAtomizer_StageTiming(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	conductor, _, a, err := optHarness(ctx, -1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := newElectron(ID(noopatom{}), nil)
+
+	var mu sync.Mutex
+	stages := map[string]*Stage{}
+	err = a.AddEventSink(EventSinkFunc(func(event interface{}) {
+		ev, ok := event.(*Event)
+		if !ok || ev.Stage == nil || ev.ElectronID != e.ID {
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		stages[ev.Message] = ev.Stage
+	}), 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sendAndWait(ctx, t, conductor, e)
+
+	expected := []string{
+		"electron received",
+		"electron distributed",
+		"pushing electron to atom",
+		"atom execution complete",
+	}
+
+	eventually(t, time.Second, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(stages) == len(expected)
+	})
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	var first, last time.Time
+	var sum time.Duration
+	for _, name := range expected {
+		s, ok := stages[name]
+		if !ok {
+			t.Fatalf("missing stage %s", name)
+		}
+
+		if s.Elapsed < 0 {
+			t.Fatalf("negative elapsed for %s: %v", name, s.Elapsed)
+		}
+
+		if first.IsZero() || s.Time.Before(first) {
+			first = s.Time
+		}
+
+		if s.Time.After(last) {
+			last = s.Time
+		}
+
+		sum += s.Elapsed
+	}
+
+	if total := last.Sub(first); sum != total {
+		t.Fatalf("expected stages to sum to %v, got %v", total, sum)
+	}
+}
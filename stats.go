@@ -0,0 +1,112 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import "time"
+
+// AtomStats contains the execution statistics gathered by the
+// atomizer for a registered atom
+type AtomStats struct {
+	// Executions is the number of electrons executed by the atom
+	Executions uint64 `json:"executions"`
+
+	// Errors is the number of executions which returned an error
+	Errors uint64 `json:"errors"`
+
+	// Allocated is the approximate number of bytes allocated by the
+	// atom across all executions. This is only populated when memory
+	// profiling is enabled using WithMemoryProfiling
+	Allocated uint64 `json:"allocated"`
+
+	// ErrorRate is the ratio of executions which returned an error to
+	// the total executions of the atom within the rolling error rate
+	// window (see WithErrorRateWindow)
+	ErrorRate float64 `json:"errorrate"`
+
+	// Elapsed is the total processing time of the atom across all
+	// executions
+	Elapsed time.Duration `json:"elapsed"`
+}
+
+// record updates the statistics of the atom which executed the instance
+func (a *atomizer) record(atomID string, p *Properties, err error) {
+	a.statsMu.Lock()
+	defer a.statsMu.Unlock()
+
+	if a.stats == nil {
+		a.stats = make(map[string]*AtomStats)
+	}
+
+	stats, ok := a.stats[atomID]
+	if !ok {
+		stats = &AtomStats{}
+		a.stats[atomID] = stats
+	}
+
+	if a.rates == nil {
+		a.rates = make(map[string]*errorRate)
+	}
+
+	rate, ok := a.rates[atomID]
+	if !ok {
+		rate = newErrorRate(a.rateWindow)
+		a.rates[atomID] = rate
+	}
+
+	failed := err != nil || (p != nil && p.Error != nil)
+	rate.add(time.Now(), failed)
+
+	stats.Executions++
+
+	if failed {
+		stats.Errors++
+	}
+
+	if p != nil {
+		stats.Allocated += p.Allocated
+
+		if p.End.After(p.Start) {
+			stats.Elapsed += p.End.Sub(p.Start)
+			a.timing(atomID).add(p.End.Sub(p.Start))
+		}
+	}
+}
+
+// timing returns the processing times of the atom.
+// The stats lock MUST be held for writing.
+func (a *atomizer) timing(atomID string) *timings {
+	if a.timings == nil {
+		a.timings = make(map[string]*timings)
+	}
+
+	t, ok := a.timings[atomID]
+	if !ok {
+		t = &timings{}
+		a.timings[atomID] = t
+	}
+
+	return t
+}
+
+// Stats returns a snapshot of the execution statistics for each
+// atom which has executed on this atomizer, keyed by atom ID
+func (a *atomizer) Stats() map[string]AtomStats {
+	a.statsMu.RLock()
+	defer a.statsMu.RUnlock()
+
+	now := time.Now()
+	stats := make(map[string]AtomStats, len(a.stats))
+	for id, s := range a.stats {
+		stat := *s
+		if rate, ok := a.rates[id]; ok {
+			stat.ErrorRate = rate.rate(now)
+		}
+
+		stats[id] = stat
+	}
+
+	return stats
+}
@@ -0,0 +1,21 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+// Status is the outcome of processing an electron
+type Status string
+
+const (
+	// StatusSucceeded indicates the atom returned without an error
+	StatusSucceeded Status = "succeeded"
+
+	// StatusFailed indicates the atom returned an error
+	StatusFailed Status = "failed"
+
+	// StatusTimedOut indicates the electron exceeded its timeout
+	// or the hard timeout of the atomizer
+	StatusTimedOut Status = "timedout"
+)
@@ -0,0 +1,90 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import "errors"
+
+// ErrDuplicateInFlightID is set as the error of an electron which was
+// rejected because another electron with the same ID is in-flight
+var ErrDuplicateInFlightID = errors.New("duplicate in-flight electron id")
+
+// WithStrictIDs rejects electrons with the ID of an electron which is
+// still in-flight with ErrDuplicateInFlightID. Two concurrent electrons
+// sharing an ID corrupt the correlation of their completions, which
+// usually indicates a sender reusing IDs. Unlike WithDedup the ID may be
+// reused once the electron completes.
+//
+// At most window IDs are tracked so that the memory used is bounded, the
+// electrons received while the window is full are not checked.
+func WithStrictIDs(window int) Option {
+	return func(a *atomizer) error {
+		if window < 1 {
+			return simple("strict id window must be at least 1", nil)
+		}
+
+		a.inflight.window = window
+		return nil
+	}
+}
+
+// reused claims the ID of the electron, rejecting the electron and
+// returning true when the ID is already claimed by an in-flight electron
+func (a *atomizer) reused(inst instance) bool {
+	if a.inflight.claim(inst.electron) {
+		return false
+	}
+
+	a.reject(inst, StageAdmission, &Error{
+		Event: &Event{
+			Message:     "duplicate in-flight electron id",
+			ElectronID:  inst.electron.ID,
+			AtomID:      inst.electron.AtomID,
+			ConductorID: ID(inst.conductor),
+		},
+		Internal: ErrDuplicateInFlightID,
+	})
+
+	return true
+}
+
+// claim claims the ID of the electron until it is done and returns
+// false if the ID is claimed by another electron. Every electron is
+// claimed when strict IDs are disabled.
+func (f *inflight) claim(e *Electron) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.window == 0 {
+		return true
+	}
+
+	if owner, ok := f.ids[e.ID]; ok {
+		return owner == e
+	}
+
+	if len(f.ids) >= f.window {
+		return true
+	}
+
+	if f.ids == nil {
+		f.ids = make(map[string]*Electron)
+	}
+
+	f.ids[e.ID] = e
+	return true
+}
+
+// release releases the ID claimed by the electron. The inflight
+// lock MUST be held.
+func (f *inflight) release(e *Electron) {
+	if e == nil {
+		return
+	}
+
+	if owner, ok := f.ids[e.ID]; ok && owner == e {
+		delete(f.ids, e.ID)
+	}
+}
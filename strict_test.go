@@ -0,0 +1,96 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAtomizer_StrictIDs(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	gaterelease = make(chan struct{})
+
+	rec, a := recHarness(ctx, t, WithStrictIDs(10), &gateatom{})
+	rejections := a.Rejections(10)
+
+	atomID := ID(gateatom{})
+	eventually(t, time.Second, func() bool {
+		return a.route(newElectron(atomID, nil)) != nil
+	})
+
+	e := newElectron(atomID, nil)
+	if _, err := rec.Send(ctx, e); err != nil {
+		t.Fatal(err)
+	}
+
+	// A second electron reusing the ID while the first is in-flight
+	concurrent := *e
+	if _, err := rec.Send(ctx, &concurrent); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-ctx.Done():
+		t.Fatal("expected rejection of the reused id")
+	case r := <-rejections:
+		if !errors.Is(r.Reason, ErrDuplicateInFlightID) {
+			t.Fatalf("expected duplicate in-flight id, got %v", r.Reason)
+		}
+	}
+
+	if p := rec.next(ctx, t); !errors.Is(p.Error, ErrDuplicateInFlightID) {
+		t.Fatalf("expected duplicate in-flight id, got %v", p.Error)
+	}
+
+	close(gaterelease)
+	if p := rec.next(ctx, t); p.Error != nil {
+		t.Fatalf("unexpected error %v", p.Error)
+	}
+
+	eventually(t, time.Second, func() bool {
+		return a.inflight.queued(atomID) == 0
+	})
+
+	// The ID is reusable once the first electron completed
+	sequential := *e
+	if _, err := rec.Send(ctx, &sequential); err != nil {
+		t.Fatal(err)
+	}
+
+	if p := rec.next(ctx, t); p.Error != nil {
+		t.Fatalf("unexpected error on reuse %v", p.Error)
+	}
+}
+
+func TestInflight_Claim_Window(t *testing.T) {
+	f := &inflight{window: 1}
+
+	first := newElectron("atom", nil)
+	if !f.claim(first) {
+		t.Fatal("expected first claim")
+	}
+
+	// The window is full so the electron is not checked
+	if !f.claim(newElectron("atom", nil)) {
+		t.Fatal("expected claim beyond the window")
+	}
+
+	reused := *first
+	if f.claim(&reused) {
+		t.Fatal("expected reused id to be refused")
+	}
+
+	f.done(first)
+	if !f.claim(&reused) {
+		t.Fatal("expected claim after release")
+	}
+}
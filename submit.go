@@ -0,0 +1,147 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import (
+	"context"
+	"sync"
+
+	"devnw.com/validator"
+)
+
+// SubmitWithCallback submits an electron for processing directly to the
+// atomizer without a conductor. The electron is admitted and queued the
+// same as the electrons received from the conductors. The callback is
+// invoked exactly once on its own routine with the properties of the
+// electron and the processing error, if any, once the electron completes.
+// Electrons which are not admitted return the rejection rather than
+// invoking the callback. Panics in the callback are recovered and emitted
+// as errors.
+func (a *atomizer) SubmitWithCallback(
+	ctx context.Context,
+	e *Electron,
+	callback func(Properties, error),
+) error {
+	if callback == nil {
+		return simple("nil submission callback", nil)
+	}
+
+	if !validator.Valid(e) {
+		return simple("invalid electron", nil)
+	}
+
+	if ctx == nil {
+		ctx = a.ctx
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	conductor := &oneshot{a: a, callback: callback, admitting: true}
+
+	inst, ok := a.admit(ctx, conductor, e)
+	rejection := conductor.admitted()
+	if !ok {
+		// The rejection is nil for electrons
+		// completed from the result store
+		return rejection
+	}
+
+	if !a.accept(ctx, inst) {
+		return simple("context closed", nil)
+	}
+
+	return nil
+}
+
+// oneshot is a conductor for a single electron which invokes a
+// callback with the completion of the electron
+type oneshot struct {
+	a        *atomizer
+	once     sync.Once
+	callback func(Properties, error)
+
+	// admitting holds the rejection of the electron
+	// while it is admitted rather than completing it
+	mu        sync.Mutex
+	admitting bool
+	rejection error
+}
+
+// admitted ends the admission of the electron returning its rejection
+func (o *oneshot) admitted() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.admitting = false
+
+	return o.rejection
+}
+
+func (o *oneshot) Receive(ctx context.Context) <-chan *Electron {
+	return nil
+}
+
+// Complete invokes the callback with the first completion
+// of the electron
+func (o *oneshot) Complete(ctx context.Context, p *Properties) error {
+	if p == nil {
+		return simple("nil properties", nil)
+	}
+
+	o.mu.Lock()
+	rejected := o.admitting && p.Error != nil
+	if rejected {
+		o.rejection = p.Error
+	}
+	o.mu.Unlock()
+
+	if rejected {
+		return nil
+	}
+
+	result := *p
+	o.once.Do(func() {
+		go o.call(result)
+	})
+
+	return nil
+}
+
+// call invokes the callback recovering any panic
+func (o *oneshot) call(p Properties) {
+	defer func() {
+		if r := recover(); r != nil {
+			o.a.err(func() error {
+				return &Error{
+					Event: &Event{
+						Message:    "panic in submission callback",
+						ElectronID: p.ElectronID,
+						AtomID:     p.AtomID,
+					},
+					Internal: ptoe(r),
+				}
+			})
+		}
+	}()
+
+	o.callback(p, p.Error)
+}
+
+func (o *oneshot) Send(
+	ctx context.Context,
+	electron *Electron,
+) (<-chan *Properties, error) {
+	return nil, simple("submission conductor cannot send electrons", nil)
+}
+
+func (o *oneshot) Close() {}
+
+// Validate ensures the oneshot has a callback
+func (o *oneshot) Validate() bool {
+	return o != nil && o.callback != nil
+}
@@ -0,0 +1,185 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type callbackresult struct {
+	properties Properties
+	err        error
+}
+
+func TestAtomizer_SubmitWithCallback(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	_, a := recHarness(ctx, t, &noopatom{}, &failatom{})
+
+	eventually(t, time.Second, func() bool {
+		return a.route(newElectron(ID(noopatom{}), nil)) != nil &&
+			a.route(newElectron(ID(failatom{}), nil)) != nil
+	})
+
+	tests := map[string]struct {
+		electron *Electron
+		err      bool
+	}{
+		"success": {newElectron(ID(noopatom{}), nil), false},
+		"error":   {newElectron(ID(failatom{}), nil), true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			results := make(chan callbackresult, 2)
+			err := a.SubmitWithCallback(
+				ctx,
+				test.electron,
+				func(p Properties, err error) {
+					results <- callbackresult{p, err}
+				},
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var res callbackresult
+			select {
+			case <-ctx.Done():
+				t.Fatal("callback not invoked")
+			case res = <-results:
+			}
+
+			if res.properties.ElectronID != test.electron.ID {
+				t.Fatalf(
+					"expected electron %s, got %s",
+					test.electron.ID,
+					res.properties.ElectronID,
+				)
+			}
+
+			if (res.err != nil) != test.err {
+				t.Fatalf("expected error %v, got %v", test.err, res.err)
+			}
+
+			select {
+			case res = <-results:
+				t.Fatalf("callback invoked twice %+v", res)
+			case <-time.After(time.Millisecond * 20):
+			}
+		})
+	}
+}
+
+func TestAtomizer_SubmitWithCallback_Panic(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	_, a := recHarness(ctx, t, &noopatom{})
+	errs := a.Errors(10)
+
+	eventually(t, time.Second, func() bool {
+		return a.route(newElectron(ID(noopatom{}), nil)) != nil
+	})
+
+	err := a.SubmitWithCallback(
+		ctx,
+		newElectron(ID(noopatom{}), nil),
+		func(Properties, error) {
+			panic("bad callback")
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			t.Fatal("callback panic not emitted")
+		case err := <-errs:
+			e, ok := err.(*Error)
+			if ok && e.Event.Message == "panic in submission callback" {
+				return
+			}
+		}
+	}
+}
+
+func TestAtomizer_SubmitWithCallback_Admission(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	invalid := errors.New("invalid payload")
+	_, a := recHarness(
+		ctx,
+		t,
+		WithElectronValidator(func(e Electron) error {
+			if string(e.Payload) == `"bad"` {
+				return invalid
+			}
+
+			return nil
+		}),
+		&noopatom{},
+	)
+
+	eventually(t, time.Second, func() bool {
+		return a.route(newElectron(ID(noopatom{}), nil)) != nil
+	})
+
+	called := make(chan struct{}, 2)
+	callback := func(Properties, error) {
+		called <- struct{}{}
+	}
+
+	err := a.SubmitWithCallback(
+		ctx,
+		newElectron(ID(noopatom{}), []byte(`"bad"`)),
+		callback,
+	)
+	if !errors.Is(err, invalid) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+
+	err = a.SubmitWithCallback(
+		ctx,
+		newElectron(ID(noopatom{}), []byte(`"good"`)),
+		callback,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-ctx.Done():
+		t.Fatal("expected callback")
+	case <-called:
+	}
+
+	select {
+	case <-called:
+		t.Fatal("unexpected callback for the rejected electron")
+	case <-time.After(time.Millisecond * 20):
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// TimingSamples is the number of the most recent processing times kept
+// for each atom to suggest timeouts from (see SuggestTimeout)
+const TimingSamples = 1024
+
+// WithSuggestionMargin sets the margin added to the observed 99th
+// percentile processing time of an atom when suggesting a timeout
+// for the atom (see SuggestTimeout)
+func WithSuggestionMargin(margin time.Duration) Option {
+	return func(a *atomizer) error {
+		if margin < 0 {
+			return simple("negative suggestion margin", nil)
+		}
+
+		a.margin = margin
+		return nil
+	}
+}
+
+// timings are the most recent processing times of an atom
+type timings struct {
+	samples []time.Duration
+	next    int
+}
+
+// add records the processing time, replacing the oldest
+// processing time once TimingSamples are recorded
+func (t *timings) add(d time.Duration) {
+	if len(t.samples) < TimingSamples {
+		t.samples = append(t.samples, d)
+		return
+	}
+
+	t.samples[t.next] = d
+	t.next = (t.next + 1) % TimingSamples
+}
+
+// percentile returns the processing time at the percentile (0-1]
+func (t *timings) percentile(p float64) time.Duration {
+	if len(t.samples) == 0 {
+		return 0
+	}
+
+	sorted := append([]time.Duration(nil), t.samples...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+
+	i := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if i < 0 {
+		i = 0
+	}
+
+	return sorted[i]
+}
+
+// SuggestTimeout recommends a timeout for the electrons of the atom
+// based on its recent processing times, the 99th percentile processing
+// time plus the suggestion margin (see WithSuggestionMargin). The
+// suggestion is advisory and does not change the timeouts used by the
+// atomizer. Zero is returned when the atom has no processing history.
+func (a *atomizer) SuggestTimeout(atomID string) time.Duration {
+	a.statsMu.RLock()
+	defer a.statsMu.RUnlock()
+
+	t, ok := a.timings[atomID]
+	if !ok {
+		return 0
+	}
+
+	return t.percentile(0.99) + a.margin
+}
@@ -0,0 +1,55 @@
+package engine
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAtomizer_SuggestTimeout(t *testing.T) {
+	margin := time.Millisecond * 50
+
+	a := &atomizer{}
+	if err := WithSuggestionMargin(margin)(a); err != nil {
+		t.Fatal(err)
+	}
+
+	if d := a.SuggestTimeout("atom"); d != 0 {
+		t.Fatalf("expected no suggestion without history, got %s", d)
+	}
+
+	// Processing times of 1ms through 100ms
+	start := time.Now()
+	for i := 1; i <= 100; i++ {
+		a.record("atom", &Properties{
+			Start: start,
+			End:   start.Add(time.Duration(i) * time.Millisecond),
+		}, nil)
+	}
+
+	p99 := time.Millisecond * 99
+	if d := a.SuggestTimeout("atom"); d < p99+margin {
+		t.Fatalf("expected suggestion of at least %s, got %s", p99+margin, d)
+	}
+}
+
+func TestTimings_Bounded(t *testing.T) {
+	tm := &timings{}
+	for i := 0; i < TimingSamples*2; i++ {
+		tm.add(time.Duration(i))
+	}
+
+	if len(tm.samples) != TimingSamples {
+		t.Fatalf("expected %v samples, got %v", TimingSamples, len(tm.samples))
+	}
+
+	// Only the most recent samples are kept
+	if p := tm.percentile(0.01); p < TimingSamples {
+		t.Fatalf("expected the oldest samples to be replaced, got %v", p)
+	}
+}
+
+func TestWithSuggestionMargin_Invalid(t *testing.T) {
+	if err := WithSuggestionMargin(-time.Second)(&atomizer{}); err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import (
+	"context"
+
+	"devnw.com/validator"
+)
+
+// Swap atomically replaces the implementation of the registered atom with
+// the new atom. Electrons for the atom ID received after the swap begins
+// are processed by the new atom while the electrons already pushed to the
+// old atom finish processing. Swap returns once the processing loops of the
+// old atom have stopped.
+func (a *atomizer) Swap(atomID string, atom Atom) error {
+	if !validator.Valid(atom) {
+		return &Error{Event: &Event{
+			Message: "invalid atom",
+			AtomID:  atomID,
+		}}
+	}
+
+	n := a.concurrency(atomID, atom)
+
+	// Start the processing loops of the new atom before it is routable
+	reps := newReplicas(atom)
+	reps.id = atomID
+	reps.sharder = a.sharders[atomID]
+	for i := 0; i < n; i++ {
+		reps.add(a.split(atom))
+	}
+
+	a.atomsMu.Lock()
+	old, ok := a.atoms[atomID]
+	if ok {
+		a.atoms[atomID] = reps
+		a.rebuild()
+	}
+	a.atomsMu.Unlock()
+
+	if !ok {
+		_ = reps.stop(a.ctx)
+		return simple("swap of unregistered atom "+atomID, nil)
+	}
+
+	// Ensure distribute is not pushing to the old atom
+	// before its processing loops are stopped
+	if err := a.barrier(a.ctx); err != nil {
+		return err
+	}
+
+	if err := old.stop(a.ctx); err != nil {
+		return err
+	}
+
+	// Nothing routes to the stopped loops any longer
+	for _, electrons := range old.loops() {
+		close(electrons)
+	}
+
+	a.event(func() interface{} {
+		return &Event{
+			Message: "atom swapped to " + ID(atom),
+			AtomID:  atomID,
+		}
+	})
+
+	return nil
+}
+
+// barrier blocks until distribute has finished pushing every electron it
+// received before the barrier to the atom processing loops or the
+// context closes
+func (a *atomizer) barrier(ctx context.Context) error {
+	b := make(chan struct{})
+
+	select {
+	case <-ctx.Done():
+		return simple("context closed", nil)
+	case a.electrons <- instance{barrier: b}:
+	}
+
+	select {
+	case <-ctx.Done():
+		return simple("context closed", nil)
+	case <-b:
+		return nil
+	}
+}
@@ -0,0 +1,118 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// swaprelease blocks oldswapatom until it is closed
+var swaprelease chan struct{}
+
+type oldswapatom struct{}
+
+func (*oldswapatom) Process(
+	ctx context.Context,
+	conductor Conductor,
+	electron *Electron,
+) ([]byte, error) {
+	<-swaprelease
+	return []byte("old"), nil
+}
+
+type newswapatom struct{}
+
+func (*newswapatom) Process(
+	ctx context.Context,
+	conductor Conductor,
+	electron *Electron,
+) ([]byte, error) {
+	return []byte("new"), nil
+}
+
+func TestAtomizer_Swap(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	swaprelease = make(chan struct{})
+	rec, a := recHarness(ctx, t, &oldswapatom{})
+
+	atomID := ID(oldswapatom{})
+	eventually(t, time.Second, func() bool {
+		return a.route(newElectron(atomID, nil)) != nil
+	})
+
+	inflight := newElectron(atomID, nil)
+	if _, err := rec.Send(ctx, inflight); err != nil {
+		t.Fatal(err)
+	}
+
+	// Wait for the old atom to pick up the electron
+	eventually(t, time.Second, func() bool {
+		a.inflight.mu.Lock()
+		defer a.inflight.mu.Unlock()
+
+		return a.inflight.pending[inflight] != nil
+	})
+
+	swapped := make(chan error, 1)
+	go func() {
+		swapped <- a.Swap(atomID, &newswapatom{})
+	}()
+
+	eventually(t, time.Second, func() bool {
+		a.atomsMu.RLock()
+		defer a.atomsMu.RUnlock()
+
+		_, ok := a.atoms[atomID].atom.(*newswapatom)
+		return ok
+	})
+
+	after := newElectron(atomID, nil)
+	if _, err := rec.Send(ctx, after); err != nil {
+		t.Fatal(err)
+	}
+
+	p := rec.next(ctx, t)
+	if p.ElectronID != after.ID || string(p.Result) != "new" {
+		t.Fatalf("expected new atom to process %s, got %+v", after.ID, p)
+	}
+
+	select {
+	case err := <-swapped:
+		t.Fatalf("swap returned before in-flight electron finished: %v", err)
+	default:
+	}
+
+	close(swaprelease)
+
+	p = rec.next(ctx, t)
+	if p.ElectronID != inflight.ID || string(p.Result) != "old" {
+		t.Fatalf("expected old atom to finish %s, got %+v", inflight.ID, p)
+	}
+
+	select {
+	case <-ctx.Done():
+		t.Fatal("swap did not complete")
+	case err := <-swapped:
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	select {
+	case p = <-rec.completions:
+		t.Fatalf("unexpected completion %+v", p)
+	case <-time.After(time.Millisecond * 20):
+	}
+
+	if err := a.Swap("unknown.Atom", &newswapatom{}); err == nil {
+		t.Fatal("expected error swapping unregistered atom")
+	}
+}
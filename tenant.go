@@ -0,0 +1,50 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+// DefaultTenantWeight is the weight of tenants without a configured weight
+const DefaultTenantWeight = 1
+
+// WithTenantWeight sets the share of the distribution of electrons the
+// tenant with the ID (see Electron.TenantID) receives under contention.
+// Electrons waiting to be distributed are queued by weighted fair queuing
+// so that each tenant is dispatched in proportion to its weight and a
+// tenant submitting far more electrons than the others cannot starve
+// them. Electron and conductor priorities (see WithConductorPriority) are
+// still honored first. Tenants without a weight, including electrons
+// without a TenantID, have a weight of DefaultTenantWeight.
+func WithTenantWeight(tenantID string, weight int) Option {
+	return func(a *atomizer) error {
+		if tenantID == "" {
+			return simple("empty tenant id", nil)
+		}
+
+		if weight < 1 {
+			return simple("tenant weight must be at least 1", nil)
+		}
+
+		if a.weights == nil {
+			a.weights = make(map[string]int)
+		}
+
+		a.weights[tenantID] = weight
+
+		if a.queue == nil {
+			a.queue = newPQueue(DefaultQueueSize)
+		}
+
+		return nil
+	}
+}
+
+// weight returns the weight of the tenant
+func (a *atomizer) weight(tenantID string) int {
+	if w, ok := a.weights[tenantID]; ok {
+		return w
+	}
+
+	return DefaultTenantWeight
+}
@@ -0,0 +1,146 @@
+package engine
+
+import (
+	"context"
+	"strconv"
+	"testing"
+)
+
+func TestPQueue_TenantFairness(t *testing.T) {
+	tests := map[string]struct {
+		weights  map[string]int
+		volumes  map[string]int
+		pops     int
+		expected map[string]int
+	}{
+		"equal weights": {
+			weights:  map[string]int{"heavy": 1, "light": 1},
+			volumes:  map[string]int{"heavy": 100, "light": 10},
+			pops:     20,
+			expected: map[string]int{"heavy": 10, "light": 10},
+		},
+		"weighted": {
+			weights:  map[string]int{"heavy": 1, "light": 3},
+			volumes:  map[string]int{"heavy": 100, "light": 30},
+			pops:     20,
+			expected: map[string]int{"heavy": 5, "light": 15},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			q := newPQueue(DefaultQueueSize)
+
+			// The heavy tenant submits its whole volume first
+			for _, tenant := range []string{"heavy", "light"} {
+				for i := 0; i < test.volumes[tenant]; i++ {
+					e := newElectron("atom", nil)
+					e.TenantID = tenant
+
+					err := q.push(
+						ctx,
+						instance{electron: e},
+						0,
+						test.weights[tenant],
+					)
+					if err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+
+			dispatched := make(map[string]int)
+			for i := 0; i < test.pops; i++ {
+				inst, ok := q.pop(ctx)
+				if !ok {
+					t.Fatal("expected queued instance")
+				}
+
+				dispatched[inst.electron.TenantID]++
+			}
+
+			for tenant, count := range test.expected {
+				if dispatched[tenant] != count {
+					t.Fatalf(
+						"expected %v dispatches for %s, got %v",
+						count,
+						tenant,
+						dispatched[tenant],
+					)
+				}
+			}
+		})
+	}
+}
+
+func TestPQueue_TenantPriority(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	q := newPQueue(DefaultQueueSize)
+
+	for i := 0; i < 5; i++ {
+		e := newElectron("atom", nil)
+		e.TenantID = "light"
+		if err := q.push(ctx, instance{electron: e}, 0, 10); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	urgent := newElectron("atom", nil)
+	urgent.TenantID = "heavy"
+	urgent.Priority = 1
+	if err := q.push(ctx, instance{electron: urgent}, 0, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	if inst, _ := q.pop(ctx); inst.electron.ID != urgent.ID {
+		t.Fatal("expected electron priority to precede tenant weight")
+	}
+}
+
+func TestWithTenantWeight_Invalid(t *testing.T) {
+	tests := map[string]Option{
+		"empty tenant": WithTenantWeight("", 1),
+		"zero weight":  WithTenantWeight("tenant", 0),
+	}
+
+	for name, opt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := opt(&atomizer{}); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
+
+func TestPQueue_TenantRelease(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	q := newPQueue(DefaultQueueSize)
+
+	for i := 0; i < 10; i++ {
+		e := newElectron("atom", nil)
+		e.TenantID = strconv.Itoa(i % 5)
+		if err := q.push(ctx, instance{electron: e}, 0, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i := 0; i < 10; i++ {
+		if _, ok := q.pop(ctx); !ok {
+			t.Fatal("expected queued instance")
+		}
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.finish) != 0 || len(q.tenants) != 0 {
+		t.Fatalf("expected drained tenants to be released, got %v", q.finish)
+	}
+}
@@ -0,0 +1,25 @@
+// Package main is a test atom plugin for LoadPlugins
+package main
+
+import (
+	"context"
+
+	engine "atomizer.io/engine"
+)
+
+type echo struct{}
+
+func (*echo) Process(
+	ctx context.Context,
+	conductor engine.Conductor,
+	electron *engine.Electron,
+) ([]byte, error) {
+	return electron.Payload, nil
+}
+
+// Atoms returns the atoms exported by the plugin
+func Atoms() []engine.Atom {
+	return []engine.Atom{&echo{}}
+}
+
+func main() {}
@@ -0,0 +1,22 @@
+// Package main is a test host which loads the atom plugins in the
+// directory passed as the first argument and prints their IDs
+package main
+
+import (
+	"fmt"
+	"os"
+
+	engine "atomizer.io/engine"
+)
+
+func main() {
+	atoms, err := engine.LoadPlugins(os.Args[1])
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	for _, a := range atoms {
+		fmt.Println(engine.ID(a))
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrRateLimited is returned for electrons which exceed the rate limit
+// of their atom when the limit rejects over-budget electrons
+var ErrRateLimited = errors.New("atom rate limited")
+
+// ThrottlePolicy determines how electrons which exceed the rate limit
+// of their atom are handled
+type ThrottlePolicy int
+
+const (
+	// ThrottleQueue holds the electrons until the rate limit admits them
+	ThrottleQueue ThrottlePolicy = iota
+
+	// ThrottleReject completes the electrons with ErrRateLimited
+	ThrottleReject
+)
+
+// WithAtomRateLimit limits the electrons executed by the atom with the ID
+// to rps electrons per second with bursts of up to burst electrons,
+// regardless of the conductor the electrons were received from. Electrons
+// over the limit are handled according to the policy.
+func WithAtomRateLimit(
+	atomID string,
+	rps float64,
+	burst int,
+	policy ThrottlePolicy,
+) Option {
+	return func(a *atomizer) error {
+		if atomID == "" {
+			return simple("empty rate limit atom id", nil)
+		}
+
+		if rps <= 0 || burst < 1 {
+			return simple("invalid rate limit for "+atomID, nil)
+		}
+
+		if a.limits == nil {
+			a.limits = make(map[string]*limiter)
+		}
+
+		a.limits[atomID] = &limiter{
+			rate:   rps,
+			burst:  float64(burst),
+			tokens: float64(burst),
+			policy: policy,
+		}
+
+		return nil
+	}
+}
+
+// limiter is a token bucket
+type limiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	policy ThrottlePolicy
+}
+
+// reserve takes a token from the bucket and returns how long to wait
+// before the token is available. When wait is false no token is taken
+// unless one is available immediately.
+func (l *limiter) reserve(now time.Time, wait bool) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.last.IsZero() {
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+	}
+	l.last = now
+
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0, true
+	}
+
+	if !wait {
+		return 0, false
+	}
+
+	l.tokens--
+	return time.Duration(-l.tokens / l.rate * float64(time.Second)), true
+}
+
+// throttle applies the rate limit of the atom to the instance and returns
+// false if the instance was rejected or the atomizer closed while waiting
+func (a *atomizer) throttle(atomID string, inst instance) bool {
+	l, ok := a.limits[atomID]
+	if !ok {
+		return true
+	}
+
+	delay, ok := l.reserve(time.Now(), l.policy == ThrottleQueue)
+	if delay == 0 && ok {
+		return true
+	}
+
+	event := &Event{
+		Message:     "atom throttled",
+		AtomID:      atomID,
+		ElectronID:  inst.electron.ID,
+		ConductorID: ID(inst.conductor),
+	}
+
+	if !ok {
+		a.reject(inst, StageExecution, &Error{Event: event, Internal: ErrRateLimited})
+		return false
+	}
+
+	a.event(func() interface{} {
+		return event
+	})
+
+	t := time.NewTimer(delay)
+	defer t.Stop()
+
+	select {
+	case <-a.ctx.Done():
+		a.inflight.done(inst.electron)
+		return false
+	case <-t.C:
+		return true
+	}
+}
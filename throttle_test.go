@@ -0,0 +1,140 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type throttledatom struct{}
+
+func (*throttledatom) Process(
+	ctx context.Context,
+	conductor Conductor,
+	electron *Electron,
+) ([]byte, error) {
+	return nil, nil
+}
+
+func TestAtomizer_AtomRateLimit(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	atomID := ID(throttledatom{})
+	rec, a := recHarness(
+		ctx,
+		t,
+		WithAtomRateLimit(atomID, 20, 1, ThrottleQueue),
+		&throttledatom{},
+		&noopatom{},
+	)
+
+	eventually(t, time.Second, func() bool {
+		return a.route(newElectron(atomID, nil)) != nil &&
+			a.route(newElectron(ID(noopatom{}), nil)) != nil
+	})
+
+	run := func(atomID string, n int) time.Duration {
+		start := time.Now()
+		for i := 0; i < n; i++ {
+			if _, err := rec.Send(ctx, newElectron(atomID, nil)); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		for i := 0; i < n; i++ {
+			if p := rec.next(ctx, t); p.Error != nil {
+				t.Fatalf("unexpected error %v", p.Error)
+			}
+		}
+
+		return time.Since(start)
+	}
+
+	// 5 electrons at 20 per second with a burst of 1 take at least 200ms
+	if elapsed := run(atomID, 5); elapsed < time.Millisecond*180 {
+		t.Fatalf("expected electrons to be paced, took %s", elapsed)
+	}
+
+	if elapsed := run(ID(noopatom{}), 5); elapsed > time.Millisecond*150 {
+		t.Fatalf("expected unlimited atom to be unpaced, took %s", elapsed)
+	}
+}
+
+func TestAtomizer_AtomRateLimit_Reject(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	atomID := ID(throttledatom{})
+	rec, a := recHarness(
+		ctx,
+		t,
+		WithAtomRateLimit(atomID, 0.1, 1, ThrottleReject),
+		&throttledatom{},
+	)
+	errs := a.Errors(10)
+
+	eventually(t, time.Second, func() bool {
+		return a.route(newElectron(atomID, nil)) != nil
+	})
+
+	for i, expected := range []error{nil, ErrRateLimited} {
+		if _, err := rec.Send(ctx, newElectron(atomID, nil)); err != nil {
+			t.Fatal(err)
+		}
+
+		p := rec.next(ctx, t)
+		if !errors.Is(p.Error, expected) ||
+			(expected == nil && p.Error != nil) {
+			t.Fatalf("electron %v: expected error %v, got %v", i, expected, p.Error)
+		}
+	}
+
+	select {
+	case <-ctx.Done():
+		t.Fatal("expected rate limited error")
+	case err := <-errs:
+		if !errors.Is(err, ErrRateLimited) {
+			t.Fatalf("expected rate limited error, got %v", err)
+		}
+	}
+}
+
+func TestWithAtomRateLimit_Invalid(t *testing.T) {
+	tests := map[string]struct {
+		atomID string
+		rps    float64
+		burst  int
+	}{
+		"empty id":   {"", 1, 1},
+		"zero rate":  {"atom", 0, 1},
+		"zero burst": {"atom", 1, 0},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := WithAtomRateLimit(
+				test.atomID,
+				test.rps,
+				test.burst,
+				ThrottleQueue,
+			)(&atomizer{})
+			if err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
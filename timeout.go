@@ -0,0 +1,49 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+// TimeoutSemantics determines when the clock of the Timeout of an
+// electron starts
+type TimeoutSemantics int
+
+const (
+	// TimeoutFromExecution starts the timeout of an electron when it
+	// is bonded to an atom for execution so the time the electron
+	// spent queued does not count towards the timeout
+	TimeoutFromExecution TimeoutSemantics = iota
+
+	// TimeoutFromSubmission starts the timeout of an electron when it
+	// is received from the conductor so the time the electron spent
+	// queued counts towards the timeout
+	TimeoutFromSubmission
+)
+
+// WithTimeoutSemantics sets when the clock of the Timeout of electrons
+// starts. The default is TimeoutFromExecution.
+func WithTimeoutSemantics(semantics TimeoutSemantics) Option {
+	return func(a *atomizer) error {
+		switch semantics {
+		case TimeoutFromExecution, TimeoutFromSubmission:
+		default:
+			return simple("unknown timeout semantics", nil)
+		}
+
+		a.timeoutFrom = semantics
+		return nil
+	}
+}
+
+// deadline sets the deadline of the instance when the timeout
+// of the electron starts from submission
+func (a *atomizer) deadline(inst *instance) {
+	timeout, ok := inst.electron.timeout()
+	if a.timeoutFrom != TimeoutFromSubmission || !ok ||
+		inst.received.IsZero() {
+		return
+	}
+
+	inst.deadline = inst.received.Add(timeout)
+}
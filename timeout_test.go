@@ -0,0 +1,185 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// queuedatom holds the first electron long enough for the
+// following electrons to be queued behind it
+type queuedatom struct{}
+
+func (*queuedatom) Process(
+	ctx context.Context,
+	conductor Conductor,
+	electron *Electron,
+) ([]byte, error) {
+	if string(electron.Payload) == `"hold"` {
+		time.Sleep(time.Millisecond * 200)
+		return nil, nil
+	}
+
+	return nil, ctx.Err()
+}
+
+func TestAtomizer_TimeoutSemantics(t *testing.T) {
+	tests := map[string]struct {
+		semantics TimeoutSemantics
+		timeout   bool
+	}{
+		"execution":  {TimeoutFromExecution, false},
+		"submission": {TimeoutFromSubmission, true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			d := time.Second * 30
+			ctx, cancel := _ctxT(context.TODO(), &d)
+			defer cancel()
+
+			reset(ctx, t)
+			t.Cleanup(func() {
+				reset(context.TODO(), t)
+			})
+
+			rec, a := recHarness(
+				ctx,
+				t,
+				WithTimeoutSemantics(test.semantics),
+				&queuedatom{},
+			)
+
+			eventually(t, time.Second, func() bool {
+				return a.route(newElectron(ID(queuedatom{}), nil)) != nil
+			})
+
+			hold := newElectron(ID(queuedatom{}), []byte(`"hold"`))
+			if _, err := rec.Send(ctx, hold); err != nil {
+				t.Fatal(err)
+			}
+
+			timeout := time.Millisecond * 100
+			queued := newElectron(ID(queuedatom{}), nil)
+			queued.Timeout = &timeout
+
+			if _, err := rec.Send(ctx, queued); err != nil {
+				t.Fatal(err)
+			}
+
+			if p := rec.next(ctx, t); p.ElectronID != hold.ID {
+				t.Fatalf("expected %s, got %s", hold.ID, p.ElectronID)
+			}
+
+			p := rec.next(ctx, t)
+			if p.ElectronID != queued.ID {
+				t.Fatalf("expected %s, got %s", queued.ID, p.ElectronID)
+			}
+
+			timedout := errors.Is(p.Error, context.DeadlineExceeded)
+			if timedout != test.timeout {
+				t.Fatalf("expected timeout %v, got %v", test.timeout, p.Error)
+			}
+		})
+	}
+}
+
+// sleepyatom sleeps for a second unless its context is cancelled
+type sleepyatom struct{}
+
+func (*sleepyatom) Process(
+	ctx context.Context,
+	conductor Conductor,
+	electron *Electron,
+) ([]byte, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(time.Second):
+		return []byte(`"slept"`), nil
+	}
+}
+
+func TestAtomizer_ElectronTimeout(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	rec, a := recHarness(ctx, t, &sleepyatom{})
+	errs := a.Errors(100)
+
+	eventually(t, time.Second, func() bool {
+		return a.route(newElectron(ID(sleepyatom{}), nil)) != nil
+	})
+
+	timeout := time.Millisecond * 50
+	e := newElectron(ID(sleepyatom{}), nil)
+	e.Timeout = &timeout
+
+	if _, err := rec.Send(ctx, e); err != nil {
+		t.Fatal(err)
+	}
+
+	p := rec.next(ctx, t)
+	if !errors.Is(p.Error, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", p.Error)
+	}
+
+	if p.Start.IsZero() || !p.End.After(p.Start) {
+		t.Fatalf("expected start and end times, got %s and %s", p.Start, p.End)
+	}
+
+	for {
+		var err error
+		select {
+		case <-ctx.Done():
+			t.Fatal("expected timeout error event")
+		case err = <-errs:
+		}
+
+		var aerr *Error
+		if errors.As(err, &aerr) && aerr.Event.ElectronID == e.ID &&
+			errors.Is(err, context.DeadlineExceeded) {
+			return
+		}
+	}
+}
+
+func TestAtomizer_ElectronTimeout_NonPositive(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	rec, a := recHarness(ctx, t, &queuedatom{})
+
+	eventually(t, time.Second, func() bool {
+		return a.route(newElectron(ID(queuedatom{}), nil)) != nil
+	})
+
+	// queuedatom fails with the error of its context so a zero or
+	// negative timeout must not cancel the context immediately
+	for _, timeout := range []time.Duration{0, -time.Second} {
+		timeout := timeout
+		e := newElectron(ID(queuedatom{}), nil)
+		e.Timeout = &timeout
+
+		if _, err := rec.Send(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+
+		if p := rec.next(ctx, t); p.Error != nil {
+			t.Fatalf("expected no timeout for %s, got %v", timeout, p.Error)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import (
+	"context"
+	"sync"
+
+	"devnw.com/validator"
+)
+
+// Span is a single traced operation
+type Span interface {
+	// End finishes the span recording the error of the operation if any
+	End(err error)
+}
+
+// Tracer creates spans for traced operations. Implementations bridge the
+// atomizer to a tracing system (i.e. OpenTelemetry)
+type Tracer interface {
+	// Start begins a new span with the supplied name. The parent is the
+	// propagated trace context of the electron (i.e. a W3C traceparent)
+	// and is empty when the electron was not sent with a trace context
+	Start(
+		ctx context.Context,
+		name string,
+		parent string,
+	) (context.Context, Span)
+}
+
+// TracingConductor wraps a conductor recording a span for the delivery of
+// each electron received from the conductor and for each completion sent
+// back through the conductor. The spans are linked to the trace context
+// propagated on the electron.
+//
+// The tracing conductor registers under the ID of the wrapped conductor and
+// unwraps to it (see Unwrapper) so the optional interfaces of the wrapped
+// conductor are still used. Completions delivered through those interfaces,
+// such as batches and groups, go directly to the wrapped conductor and are
+// not traced.
+type TracingConductor struct {
+	Conductor
+	tracer Tracer
+
+	// parents holds the trace context of received electrons
+	// until they are done or the receive context is canceled
+	parents sync.Map
+}
+
+// NewTracingConductor wraps the conductor with tracing using the tracer
+func NewTracingConductor(c Conductor, tracer Tracer) *TracingConductor {
+	return &TracingConductor{
+		Conductor: c,
+		tracer:    tracer,
+	}
+}
+
+// Receive starts a span for each electron received from the wrapped
+// conductor which ends once the electron is delivered to the atomizer
+func (t *TracingConductor) Receive(ctx context.Context) <-chan *Electron {
+	in := t.Conductor.Receive(ctx)
+	out := make(chan *Electron)
+
+	go func() {
+		defer close(out)
+		defer func() {
+			if ctx.Err() == nil {
+				return
+			}
+
+			t.parents.Range(func(key, _ interface{}) bool {
+				t.parents.Delete(key)
+				return true
+			})
+		}()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case e, ok := <-in:
+				if !ok {
+					return
+				}
+
+				var parent string
+				if e != nil {
+					parent = e.TraceParent
+					t.parents.Store(e.ID, parent)
+				}
+
+				_, span := t.tracer.Start(ctx, "atomizer.receive", parent)
+
+				select {
+				case <-ctx.Done():
+					span.End(ctx.Err())
+					return
+				case out <- e:
+					span.End(nil)
+				}
+			}
+		}
+	}()
+
+	return out
+}
+
+// Complete records a span around the completion of the electron by the
+// wrapped conductor
+func (t *TracingConductor) Complete(ctx context.Context, p *Properties) error {
+	var parent string
+	if p != nil {
+		if v, ok := t.parents.LoadAndDelete(p.ElectronID); ok {
+			parent, _ = v.(string)
+		}
+	}
+
+	ctx, span := t.tracer.Start(ctx, "atomizer.complete", parent)
+
+	err := t.Conductor.Complete(ctx, p)
+	span.End(err)
+
+	return err
+}
+
+// untrack drops the trace context of an electron once it is done so the
+// trace context of electrons which are never completed is not retained
+func (t *TracingConductor) untrack(e *Electron) {
+	if e != nil {
+		t.parents.Delete(e.ID)
+	}
+}
+
+// ID returns the registration ID of the wrapped conductor
+func (t *TracingConductor) ID() string {
+	return ID(t.Conductor)
+}
+
+// Unwrap returns the wrapped conductor
+func (t *TracingConductor) Unwrap() Conductor {
+	return t.Conductor
+}
+
+// Validate ensures the wrapped conductor and the tracer are valid
+func (t *TracingConductor) Validate() bool {
+	return t != nil && t.tracer != nil && validator.Valid(t.Conductor)
+}
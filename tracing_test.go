@@ -0,0 +1,233 @@
+package engine
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+type tspan struct {
+	name   string
+	parent string
+	ended  bool
+	err    error
+}
+
+type ttracer struct {
+	mu    sync.Mutex
+	spans []*tspan
+}
+
+func (t *ttracer) Start(
+	ctx context.Context,
+	name string,
+	parent string,
+) (context.Context, Span) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s := &tspan{name: name, parent: parent}
+	t.spans = append(t.spans, s)
+
+	return ctx, &tspanender{t, s}
+}
+
+type tspanender struct {
+	t *ttracer
+	s *tspan
+}
+
+func (e *tspanender) End(err error) {
+	e.t.mu.Lock()
+	defer e.t.mu.Unlock()
+
+	e.s.ended = true
+	e.s.err = err
+}
+
+func (t *ttracer) get(name string) *tspan {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, s := range t.spans {
+		if s.name == name {
+			c := *s
+			return &c
+		}
+	}
+
+	return nil
+}
+
+func TestTracingConductor(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	tracer := &ttracer{}
+	conductor := NewTracingConductor(
+		&passthrough{input: make(chan *Electron)},
+		tracer,
+	)
+
+	a, err := Atomize(ctx, conductor, &noopatom{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = a.Exec(); err != nil {
+		t.Fatal(err)
+	}
+
+	e := newElectron(ID(noopatom{}), nil)
+	e.TraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	p := sendAndWait(ctx, t, conductor, e)
+	if p.Error != nil {
+		t.Fatal(p.Error)
+	}
+
+	for _, name := range []string{"atomizer.receive", "atomizer.complete"} {
+		var span *tspan
+		eventually(t, time.Second, func() bool {
+			span = tracer.get(name)
+			return span != nil && span.ended
+		})
+
+		if span.parent != e.TraceParent {
+			t.Fatalf(
+				"%s span parent [%s] != [%s]",
+				name,
+				span.parent,
+				e.TraceParent,
+			)
+		}
+
+		if span.err != nil {
+			t.Fatalf("unexpected %s span error %s", name, span.err)
+		}
+	}
+}
+
+type pingpassthrough struct {
+	*passthrough
+}
+
+func (pp *pingpassthrough) Ping(ctx context.Context) error { return nil }
+
+func TestTracingConductor_Unwrap(t *testing.T) {
+	inner := &pingpassthrough{&passthrough{input: make(chan *Electron)}}
+	conductor := NewTracingConductor(inner, &ttracer{})
+
+	if ID(conductor) != ID(inner) {
+		t.Fatalf("id [%s] != wrapped id [%s]", ID(conductor), ID(inner))
+	}
+
+	other := NewTracingConductor(
+		&passthrough{input: make(chan *Electron)},
+		&ttracer{},
+	)
+
+	if ID(conductor) == ID(other) {
+		t.Fatalf("wrapped conductors share the id [%s]", ID(conductor))
+	}
+
+	var p Pinger
+	if !conductorAs(conductor, &p) {
+		t.Fatal("expected the pinger of the wrapped conductor")
+	}
+
+	if p != Pinger(inner) {
+		t.Fatal("expected the wrapped conductor as the pinger")
+	}
+
+	var bc BatchCompleter
+	if conductorAs(conductor, &bc) {
+		t.Fatal("unexpected batch completer")
+	}
+}
+
+func parentCount(t *TracingConductor) int {
+	var n int
+	t.parents.Range(func(_, _ interface{}) bool {
+		n++
+		return true
+	})
+
+	return n
+}
+
+func TestTracingConductor_Parents_Done(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	tracer := &ttracer{}
+	input := make(chan *Electron)
+	conductor := NewTracingConductor(&passthrough{input: input}, tracer)
+
+	a, err := Atomize(ctx, conductor, &noopatom{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = a.Exec(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The electron is never completed since no reply is expected
+	reply := false
+	e := newElectron(ID(noopatom{}), nil)
+	e.TraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	e.ReplyExpected = &reply
+
+	select {
+	case <-ctx.Done():
+		t.Fatal(ctx.Err())
+	case input <- e:
+	}
+
+	eventually(t, time.Second*5, func() bool {
+		span := tracer.get("atomizer.receive")
+		return span != nil && span.ended && parentCount(conductor) == 0
+	})
+}
+
+func TestTracingConductor_Parents_Cancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	input := make(chan *Electron)
+	conductor := NewTracingConductor(
+		&passthrough{input: input},
+		&ttracer{},
+	)
+
+	out := conductor.Receive(ctx)
+
+	input <- newElectron(ID(noopatom{}), nil)
+	<-out
+
+	if parentCount(conductor) != 1 {
+		t.Fatalf("expected 1 parent; got %v", parentCount(conductor))
+	}
+
+	cancel()
+	for range out {
+	}
+
+	if parentCount(conductor) != 0 {
+		t.Fatalf("expected 0 parents; got %v", parentCount(conductor))
+	}
+}
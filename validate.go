@@ -0,0 +1,52 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+// ElectronValidator validates an electron received from a conductor
+// returning an error if the electron should not be processed
+type ElectronValidator func(Electron) error
+
+// WithElectronValidator adds validation rules which are applied to every
+// electron received from a conductor after the built-in validation of
+// the electron. Validators run in the order they are configured and the
+// first error completes the electron with the error without processing.
+func WithElectronValidator(validators ...ElectronValidator) Option {
+	return func(a *atomizer) error {
+		for _, v := range validators {
+			if v == nil {
+				return simple("nil electron validator", nil)
+			}
+		}
+
+		a.validators = append(a.validators, validators...)
+		return nil
+	}
+}
+
+// invalid applies the electron validators to the instance and rejects
+// the instance if any of the validators fail
+func (a *atomizer) invalid(inst instance) bool {
+	for _, v := range a.validators {
+		err := v(*inst.electron)
+		if err == nil {
+			continue
+		}
+
+		a.reject(inst, StageAdmission, &Error{
+			Event: &Event{
+				Message:     "electron failed validation",
+				ElectronID:  inst.electron.ID,
+				AtomID:      inst.electron.AtomID,
+				ConductorID: ID(inst.conductor),
+			},
+			Internal: err,
+		})
+
+		return true
+	}
+
+	return false
+}
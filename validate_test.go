@@ -0,0 +1,105 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+var errEmptyPayload = errors.New("empty payload")
+
+func TestAtomizer_ElectronValidator(t *testing.T) {
+	d := time.Second * 30
+	ctx, cancel := _ctxT(context.TODO(), &d)
+	defer cancel()
+
+	reset(ctx, t)
+	t.Cleanup(func() {
+		reset(context.TODO(), t)
+	})
+
+	var order []string
+	rec, a := recHarness(
+		ctx,
+		t,
+		WithElectronValidator(
+			func(e Electron) error {
+				order = append(order, "first")
+				return nil
+			},
+			func(e Electron) error {
+				order = append(order, "second")
+				if len(e.Payload) == 0 {
+					return errEmptyPayload
+				}
+
+				return nil
+			},
+		),
+		&noopatom{},
+	)
+	errs := a.Errors(10)
+
+	eventually(t, time.Second, func() bool {
+		return a.route(newElectron(ID(noopatom{}), nil)) != nil
+	})
+
+	tests := map[string]struct {
+		electron *Electron
+		err      error
+	}{
+		"empty payload": {
+			newElectron(ID(noopatom{}), nil),
+			errEmptyPayload,
+		},
+		"valid payload": {
+			newElectron(ID(noopatom{}), []byte(`{}`)),
+			nil,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			order = nil
+
+			if _, err := rec.Send(ctx, test.electron); err != nil {
+				t.Fatal(err)
+			}
+
+			p := rec.next(ctx, t)
+			if p.ElectronID != test.electron.ID {
+				t.Fatalf(
+					"expected %s, got %s",
+					test.electron.ID,
+					p.ElectronID,
+				)
+			}
+
+			if !errors.Is(p.Error, test.err) ||
+				(test.err == nil && p.Error != nil) {
+				t.Fatalf("expected error %v, got %v", test.err, p.Error)
+			}
+
+			if len(order) != 2 || order[0] != "first" {
+				t.Fatalf("expected validators in order, got %v", order)
+			}
+		})
+	}
+
+	// The built-in validation still applies
+	invalid := newElectron(ID(noopatom{}), []byte(`{}`))
+	invalid.SenderID = ""
+
+	if _, err := rec.Send(ctx, invalid); err != nil {
+		t.Fatal(err)
+	}
+
+	if p := rec.next(ctx, t); p.Error == nil {
+		t.Fatal("expected built-in validation error")
+	}
+
+	for len(errs) > 0 {
+		<-errs
+	}
+}
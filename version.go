@@ -0,0 +1,55 @@
+// Copyright © 2019 Developer Network, LLC
+//
+// This file is subject to the terms and conditions defined in
+// file 'LICENSE', which is part of this source code package.
+
+package engine
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+)
+
+// ElectronVersion is the version of the wire format of marshaled
+// electrons. Electrons without a version are decoded as version 1.
+const ElectronVersion = 2
+
+// ErrUnsupportedVersion is returned when unmarshalling an electron
+// whose wire format version is unknown
+var ErrUnsupportedVersion = errors.New("unsupported electron version")
+
+// decoders decode the payload of the electron by wire format version
+var decoders = map[int]func(jsonE *jsonElectron, e *Electron) error{
+	0: decodeV1,
+	1: decodeV1,
+	2: decodeV2,
+}
+
+// decodeV1 decodes the payload of a version 1 electron which is either a
+// base64 encoded string or raw JSON
+func decodeV1(jsonE *jsonElectron, e *Electron) error {
+	if jsonE.Payload == nil {
+		return nil
+	}
+
+	var err error
+	pay := strings.Trim(string(jsonE.Payload), "\"")
+	e.Payload, err = base64.StdEncoding.DecodeString(pay)
+	if err != nil {
+		e.Payload = jsonE.Payload
+	}
+
+	return nil
+}
+
+// decodeV2 decodes the payload of a version 2 electron which is always
+// the raw JSON payload so that string payloads are never mistaken for
+// base64
+func decodeV2(jsonE *jsonElectron, e *Electron) error {
+	if jsonE.Payload != nil {
+		e.Payload = []byte(jsonE.Payload)
+	}
+
+	return nil
+}
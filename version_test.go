@@ -0,0 +1,68 @@
+package engine
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestElectron_UnmarshalJSON_Versions(t *testing.T) {
+	tests := map[string]struct {
+		data     string
+		expected string
+		err      error
+	}{
+		"v1 base64 payload": {
+			`{"senderid":"s","id":"id","atomid":"a","payload":"aGVsbG8="}`,
+			"hello",
+			nil,
+		},
+		"v2 string payload": {
+			`{"v":2,"senderid":"s","id":"id","atomid":"a","payload":"aGVsbG8="}`,
+			`"aGVsbG8="`,
+			nil,
+		},
+		"unknown version": {
+			`{"v":99,"senderid":"s","id":"id","atomid":"a"}`,
+			"",
+			ErrUnsupportedVersion,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			e := &Electron{}
+			err := json.Unmarshal([]byte(test.data), e)
+			if !errors.Is(err, test.err) || (test.err == nil && err != nil) {
+				t.Fatalf("expected error %v, got %v", test.err, err)
+			}
+
+			if string(e.Payload) != test.expected {
+				t.Fatalf("expected payload %s, got %s", test.expected, e.Payload)
+			}
+		})
+	}
+}
+
+func TestElectron_Version_RoundTrip(t *testing.T) {
+	e := &Electron{
+		SenderID: "s",
+		ID:       "id",
+		AtomID:   "a",
+		Payload:  []byte(`"aGVsbG8="`),
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := &Electron{}
+	if err = json.Unmarshal(data, out); err != nil {
+		t.Fatal(err)
+	}
+
+	if string(out.Payload) != string(e.Payload) {
+		t.Fatalf("expected payload %s, got %s", e.Payload, out.Payload)
+	}
+}